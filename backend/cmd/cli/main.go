@@ -0,0 +1,12 @@
+package main
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/app"
+	"os"
+)
+
+const configDir = "configs"
+
+func main() {
+	app.RunCommand(configDir, os.Args[1:])
+}
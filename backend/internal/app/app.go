@@ -10,13 +10,20 @@ import (
 	"github.com/mephistolie/chefbook-server/internal/app/dependencies/service"
 	"github.com/mephistolie/chefbook-server/internal/config"
 	"github.com/mephistolie/chefbook-server/internal/delivery/http/router"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/repository/local"
 	"github.com/mephistolie/chefbook-server/internal/repository/postgres"
+	"github.com/mephistolie/chefbook-server/internal/repository/s3"
+	"github.com/mephistolie/chefbook-server/internal/repository/store"
 	"github.com/mephistolie/chefbook-server/internal/server"
+	repositoryInterface "github.com/mephistolie/chefbook-server/internal/service/interface/repository"
 	"github.com/mephistolie/chefbook-server/pkg/auth"
 	"github.com/mephistolie/chefbook-server/pkg/cache"
 	"github.com/mephistolie/chefbook-server/pkg/hash"
+	"github.com/mephistolie/chefbook-server/pkg/imaging"
 	"github.com/mephistolie/chefbook-server/pkg/logger"
 	smtp "github.com/mephistolie/chefbook-server/pkg/mail"
+	"github.com/mephistolie/chefbook-server/pkg/scheduler"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"google.golang.org/api/option"
@@ -35,6 +42,82 @@ func Run(configPath string) {
 		return
 	}
 
+	services, tokenManager, err := initServices(cfg, configPath)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	handler := router.NewRouter(services, tokenManager, cfg.Uploads)
+
+	srv := server.NewServer(cfg, handler.Init(cfg))
+
+	go func() {
+		if err := srv.Run(); !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("error occurred while running http server: %s\n", err.Error())
+		}
+	}()
+
+	logger.Info("server started")
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	go scheduler.Every(jobsCtx, cfg.Trending.Interval, func() {
+		if err := services.Trending.UpdateTrendingScores(); err != nil {
+			logger.Errorf("failed to update trending scores: %s", err.Error())
+		}
+	})
+
+	go scheduler.Every(jobsCtx, cfg.Recommendations.Interval, func() {
+		if err := services.Recommendation.UpdateRecommendationData(); err != nil {
+			logger.Errorf("failed to update recommendation data: %s", err.Error())
+		}
+	})
+
+	go scheduler.Every(jobsCtx, cfg.Storage.GC.Interval, func() {
+		report, err := services.Storage.CollectGarbage(jobsCtx, false)
+		if err != nil {
+			logger.Errorf("failed to collect storage garbage: %s", err.Error())
+			return
+		}
+		logger.Infof("storage garbage collected: %d of %d files deleted, %d bytes reclaimed",
+			report.DeletedFiles, report.ScannedFiles, report.ReclaimedBytes)
+	})
+
+	go scheduler.Every(jobsCtx, cfg.Subscriptions.RefreshInterval, func() {
+		refreshed, err := services.Subscription.RefreshExpiredSubscriptions(jobsCtx)
+		if err != nil {
+			logger.Errorf("failed to refresh expired subscriptions: %s", err.Error())
+			return
+		}
+		if refreshed > 0 {
+			logger.Infof("expired subscriptions refreshed: %d", refreshed)
+		}
+	})
+
+	go func() {
+		if err := services.Allergen.DetectUnknownAllergens(); err != nil {
+			logger.Errorf("failed to detect unknown recipe allergens: %s", err.Error())
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
+
+	<-quit
+
+	const timeout = 5 * time.Second
+
+	ctx, shutdown := context.WithTimeout(context.Background(), timeout)
+	defer shutdown()
+
+	if err := srv.Stop(ctx); err != nil {
+		logger.Errorf("failed to stop server: %v", err)
+	}
+}
+
+func initServices(cfg *config.Config, configPath string) (*service.Service, auth.TokenManager, error) {
 	db, err := postgres.NewPostgresDB(postgres.Config{
 		Host:     cfg.Postgres.Host,
 		Port:     cfg.Postgres.Port,
@@ -52,20 +135,18 @@ func Run(configPath string) {
 
 	emailSender, err := smtp.NewSMTPSender(cfg.SMTP.From, cfg.SMTP.Password, cfg.SMTP.Host, cfg.SMTP.Port)
 	if err != nil {
-		logger.Error(err)
-		return
+		return nil, nil, err
 	}
 
 	tokenManager, err := auth.NewManager(cfg.Auth.JWT.SigningKey)
 	if err != nil {
-		logger.Error(err)
-		return
+		return nil, nil, err
 	}
 
-	client, err := minio.New(cfg.S3.Host, &minio.Options{
-		Creds:  credentials.NewStaticV4(cfg.S3.AccessKey, cfg.S3.SecretKey, ""),
-		Secure: true,
-	})
+	fileManager, err := initFileManager(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var firebaseApp *firebase.App = nil
 	if cfg.Firebase.Enabled {
@@ -73,12 +154,16 @@ func Run(configPath string) {
 		opt := option.WithCredentialsFile(firebaseKeyPath)
 		firebaseApp, err = firebase.NewApp(context.Background(), nil, opt)
 		if err != nil {
-			logger.Error(err)
-			return
+			return nil, nil, err
 		}
 	}
 
-	repositories := repository.NewRepository(db, client, firebaseApp, cfg.Firebase.ApiKey)
+	subscriptionProviders, err := initSubscriptionProviders(cfg, configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repositories := repository.NewRepository(db, fileManager, firebaseApp, cfg.Firebase.ApiKey, subscriptionProviders)
 	services := service.NewService(service.Dependencies{
 		Repo:                  repositories,
 		Cache:                 memCache,
@@ -92,30 +177,100 @@ func Run(configPath string) {
 		Environment:           cfg.Environment,
 		Domain:                cfg.HTTP.Host,
 		FirebaseImportEnabled: cfg.Firebase.Enabled,
+		TrendingParams: entity.TrendingParams{
+			LikeWeight: cfg.Trending.LikeWeight,
+			SaveWeight: cfg.Trending.SaveWeight,
+			ViewWeight: cfg.Trending.ViewWeight,
+			HalfLife:   cfg.Trending.HalfLife,
+			Window:     cfg.Trending.Window,
+		},
+		SimilarRecipesLimit: cfg.Recommendations.SimilarRecipes,
+		NutritionParams: entity.NutritionParams{
+			MinConfidence: cfg.Nutrition.MinConfidence,
+		},
+		PrivateLinkTTL:           cfg.S3.PrivateLinkTTL,
+		ImageProcessor:           imaging.NewJpegProcessor(cfg.Images.MaxSide, cfg.Images.MaxPixels, cfg.Images.Quality, cfg.Images.Thumbnails),
+		AvatarMaxSize:            cfg.Uploads.AvatarMaxSize,
+		RecipePictureMaxSize:     cfg.Uploads.RecipePictureMaxSize,
+		StorageGCGracePeriod:     cfg.Storage.GC.GracePeriod,
+		FreeEntitlements:         newTierEntitlements(cfg.Entitlements.Free),
+		PremiumEntitlements:      newTierEntitlements(cfg.Entitlements.Premium),
+		SubscriptionProducts:     cfg.Subscriptions.Products,
+		SubscriptionWebhookToken: cfg.Subscriptions.WebhookToken,
+		BroccoinParams: entity.BroccoinParams{
+			LikeReward:       cfg.Broccoins.LikeReward,
+			DailyLoginReward: cfg.Broccoins.DailyLoginReward,
+			PremiumDayPrice:  cfg.Broccoins.PremiumDayPrice,
+		},
+		ReferralParams: entity.ReferralParams{
+			ReferrerReward: entity.ReferralReward{
+				Broccoins:   cfg.Referrals.Referrer.Broccoins,
+				PremiumDays: cfg.Referrals.Referrer.PremiumDays,
+			},
+			InviteeReward: entity.ReferralReward{
+				Broccoins:   cfg.Referrals.Invitee.Broccoins,
+				PremiumDays: cfg.Referrals.Invitee.PremiumDays,
+			},
+			Limits: entity.ReferralLimits{
+				MaxRewards:        cfg.Referrals.Limits.MaxRewards,
+				MaxDailyRewards:   cfg.Referrals.Limits.MaxDailyRewards,
+				MaxPendingInvites: cfg.Referrals.Limits.MaxPendingInvites,
+			},
+		},
+		AchievementRewards: cfg.Achievements.Rewards,
 	})
-	handler := router.NewRouter(services, tokenManager)
-
-	srv := server.NewServer(cfg, handler.Init(cfg))
-
-	go func() {
-		if err := srv.Run(); !errors.Is(err, http.ErrServerClosed) {
-			logger.Errorf("error occurred while running http server: %s\n", err.Error())
-		}
-	}()
 
-	logger.Info("server started")
+	return services, tokenManager, nil
+}
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
+func initSubscriptionProviders(cfg *config.Config, configPath string) (map[string]repositoryInterface.SubscriptionProvider, error) {
+	providers := make(map[string]repositoryInterface.SubscriptionProvider)
 
-	<-quit
+	if cfg.Subscriptions.AppStore.Enabled {
+		providers[entity.SubscriptionProviderAppStore] = store.NewAppStoreProvider(cfg.Subscriptions.AppStore.SharedSecret,
+			cfg.Subscriptions.AppStore.BundleId)
+	}
+	if cfg.Subscriptions.Play.Enabled {
+		playKeyPath := fmt.Sprintf("%s/%s", configPath, cfg.Subscriptions.Play.PrivateKeyFileName)
+		playProvider, err := store.NewPlayProvider(context.Background(), cfg.Subscriptions.Play.PackageName, playKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		providers[entity.SubscriptionProviderPlay] = playProvider
+	}
+	if cfg.Subscriptions.Fake.Enabled {
+		providers[entity.SubscriptionProviderFake] = store.NewFakeProvider(cfg.Subscriptions.Fake.Period)
+	}
 
-	const timeout = 5 * time.Second
+	return providers, nil
+}
 
-	ctx, shutdown := context.WithTimeout(context.Background(), timeout)
-	defer shutdown()
+func newTierEntitlements(tier config.TierConfig) entity.TierEntitlements {
+	return entity.TierEntitlements{
+		Encryption: tier.Encryption,
+		StorageQuota: entity.StorageQuota{
+			MaxBytes: tier.Storage.MaxBytes,
+			MaxFiles: tier.Storage.MaxFiles,
+		},
+		MaxCollections: tier.MaxCollections,
+	}
+}
 
-	if err := srv.Stop(ctx); err != nil {
-		logger.Errorf("failed to stop server: %v", err)
+func initFileManager(cfg *config.Config) (repositoryInterface.File, error) {
+	switch cfg.Storage.Driver {
+	case config.StorageDriverS3:
+		client, err := minio.New(cfg.S3.Host, &minio.Options{
+			Creds:  credentials.NewStaticV4(cfg.S3.AccessKey, cfg.S3.SecretKey, ""),
+			Secure: cfg.S3.Secure,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return s3.NewAWSFileManager(client, cfg.Storage.PublicUrl), nil
+	case config.StorageDriverLocal:
+		return local.NewLocalFileManager(cfg.Storage.Local.Path, cfg.Storage.Local.BaseUrl(), cfg.Storage.PublicUrl,
+			cfg.Storage.Local.SigningKey), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", cfg.Storage.Driver)
 	}
 }
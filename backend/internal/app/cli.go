@@ -0,0 +1,188 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/mephistolie/chefbook-server/internal/app/dependencies/service"
+	"github.com/mephistolie/chefbook-server/internal/config"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/pkg/logger"
+	"os"
+	"unicode/utf8"
+)
+
+const (
+	commandImportFoods     = "import-foods"
+	commandMigrateKeys     = "migrate-keys"
+	commandMigratePictures = "migrate-pictures"
+	commandMigrateLinks    = "migrate-links"
+	commandImportPictures  = "import-pictures"
+	commandRecountStorage  = "recount-storage"
+	commandCollectGarbage  = "collect-garbage"
+)
+
+// RunCommand runs administrative command with its flags, e.g. 'import-foods -file foods.csv'
+func RunCommand(configPath string, args []string) {
+	if len(args) == 0 {
+		logger.Errorf("command is not specified. Available commands: %s, %s, %s, %s, %s, %s, %s",
+			commandImportFoods, commandMigrateKeys, commandMigratePictures, commandMigrateLinks, commandImportPictures,
+			commandCollectGarbage, commandRecountStorage)
+		os.Exit(2)
+	}
+
+	cfg, err := config.Init(configPath)
+	if err != nil {
+		logger.Errorf("failed to initialize config: %s", err.Error())
+		os.Exit(1)
+	}
+
+	services, _, err := initServices(cfg, configPath)
+	if err != nil {
+		logger.Error(err)
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case commandImportFoods:
+		err = importFoods(services, args[1:])
+	case commandMigrateKeys:
+		err = migrateKeys(services)
+	case commandMigratePictures:
+		err = migratePictures(services)
+	case commandMigrateLinks:
+		err = migrateLinks(services)
+	case commandImportPictures:
+		err = importPictures(services)
+	case commandCollectGarbage:
+		err = collectGarbage(services, args[1:])
+	case commandRecountStorage:
+		err = recountStorage(services)
+	default:
+		err = fmt.Errorf("unknown command: %s", args[0])
+	}
+
+	if err != nil {
+		logger.Errorf("%s failed: %s", args[0], err.Error())
+		os.Exit(1)
+	}
+}
+
+// importFoods loads food composition table. Column names are configurable,
+// so datasets like CIQUAL can be imported without conversion
+func importFoods(services *service.Service, args []string) error {
+	flags := flag.NewFlagSet(commandImportFoods, flag.ContinueOnError)
+	filePath := flags.String("file", "", "food composition table CSV file")
+	source := flags.String("source", "custom", "dataset name, e.g. usda or ciqual")
+	language := flags.String("language", entity.CodeEnglish, "language of food names")
+	delimiter := flags.String("delimiter", ",", "CSV delimiter")
+	idColumn := flags.String("id-column", "id", "food identifier column")
+	nameColumn := flags.String("name-column", "name", "food name column")
+	caloriesColumn := flags.String("calories-column", "calories", "kcal per 100 g column")
+	proteinColumn := flags.String("protein-column", "protein", "protein grams per 100 g column")
+	fatsColumn := flags.String("fats-column", "fats", "fats grams per 100 g column")
+	carbohydratesColumn := flags.String("carbohydrates-column", "carbohydrates", "carbohydrates grams per 100 g column")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *filePath == "" {
+		return errors.New("file is not specified")
+	}
+	delimiterRune, _ := utf8.DecodeRuneInString(*delimiter)
+
+	file, err := os.Open(*filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	imported, err := services.Nutrition.ImportFoods(file, entity.FoodsCsvFormat{
+		Source:              *source,
+		Language:            *language,
+		Delimiter:           delimiterRune,
+		IdColumn:            *idColumn,
+		NameColumn:          *nameColumn,
+		CaloriesColumn:      *caloriesColumn,
+		ProteinColumn:       *proteinColumn,
+		FatsColumn:          *fatsColumn,
+		CarbohydratesColumn: *carbohydratesColumn,
+	})
+	logger.Infof("%d foods imported from %s", imported, *filePath)
+
+	return err
+}
+
+// migrateKeys moves encryption keys uploaded with public access to private storage
+func migrateKeys(services *service.Service) error {
+	migrated, err := services.Encryption.MigrateKeys(context.Background())
+	logger.Infof("%d keys moved to private storage", migrated)
+
+	return err
+}
+
+// migratePictures revokes public access to pictures of private and shared recipes
+func migratePictures(services *service.Service) error {
+	migrated, err := services.RecipePicture.MigratePictures(context.Background())
+	logger.Infof("pictures of %d recipes moved to private storage", migrated)
+
+	return err
+}
+
+// migrateLinks replaces absolute links to storage in database with object keys, so storage host or CDN can be changed.
+// Encryption keys are migrated too, because object keys of keys are stored only for private keys
+func migrateLinks(services *service.Service) error {
+	if err := migrateKeys(services); err != nil {
+		return err
+	}
+
+	migrated, err := services.Profile.MigrateAvatarLinks()
+	logger.Infof("%d avatar links replaced with object keys", migrated)
+	if err != nil {
+		return err
+	}
+
+	migrated, err = services.RecipeOwnership.MigratePictureLinks()
+	logger.Infof("picture links of %d recipes replaced with object keys", migrated)
+
+	return err
+}
+
+// importPictures adds pictures uploaded before recipe galleries to galleries and links cooking steps to them by ids
+func importPictures(services *service.Service) error {
+	imported, err := services.RecipePicture.ImportPictures(context.Background())
+	logger.Infof("pictures of %d recipes imported to galleries", imported)
+
+	return err
+}
+
+// collectGarbage deletes storage files which aren't referenced by database. Dry run lists them without deletion
+func collectGarbage(services *service.Service, args []string) error {
+	flags := flag.NewFlagSet(commandCollectGarbage, flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "list orphaned files without deletion")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	report, err := services.Storage.CollectGarbage(context.Background(), *dryRun)
+	for _, file := range report.OrphanedFiles {
+		logger.Infof("orphaned file: %s", file)
+	}
+	if report.DryRun {
+		logger.Infof("%d of %d files are orphaned, %d bytes can be reclaimed",
+			len(report.OrphanedFiles), report.ScannedFiles, report.ReclaimedBytes)
+	} else {
+		logger.Infof("%d of %d files deleted, %d bytes reclaimed", report.DeletedFiles, report.ScannedFiles, report.ReclaimedBytes)
+	}
+
+	return err
+}
+
+// recountStorage recalculates storage usage of users by storage content
+func recountStorage(services *service.Service) error {
+	counted, err := services.Quota.RecountUsage(context.Background())
+	logger.Infof("%d files counted in storage usage", counted)
+
+	return err
+}
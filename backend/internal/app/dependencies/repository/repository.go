@@ -5,25 +5,40 @@ import (
 	"github.com/jmoiron/sqlx"
 	firebaseRepo "github.com/mephistolie/chefbook-server/internal/repository/firebase"
 	"github.com/mephistolie/chefbook-server/internal/repository/postgres"
-	"github.com/mephistolie/chefbook-server/internal/repository/s3"
 	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
-	"github.com/minio/minio-go/v7"
 )
 
 type Repository struct {
 	Auth            repository.Auth
 	Profile         repository.Profile
+	Follow          repository.Follow
 	Recipe          repository.Recipe
 	RecipeOwnership repository.RecipeOwnership
 	RecipeSharing   repository.RecipeSharing
+	RecipeLink      repository.RecipeLink
+	RecipePicture   repository.RecipePicture
 	Encryption      repository.Encryption
 	Category        repository.Category
+	Collection      repository.Collection
+	Tag             repository.Tag
+	Allergen        repository.Allergen
+	Nutrition       repository.Nutrition
 	ShoppingList    repository.ShoppingList
+	Trending        repository.Trending
+	Recommendation  repository.Recommendation
 	File            repository.File
+	Storage         repository.Storage
+	Subscription    repository.Subscription
+	Broccoin        repository.Broccoin
+	Referral        repository.Referral
+	Achievement     repository.Achievement
 	Migration       repository.FirebaseMigration
+
+	SubscriptionProviders map[string]repository.SubscriptionProvider
 }
 
-func NewRepository(db *sqlx.DB, client *minio.Client, firebaseApp *firebase.App, firebaseApiKey string) *Repository {
+func NewRepository(db *sqlx.DB, fileManager repository.File, firebaseApp *firebase.App, firebaseApiKey string,
+	subscriptionProviders map[string]repository.SubscriptionProvider) *Repository {
 	var migrationRepo repository.FirebaseMigration = nil
 	if firebaseApp != nil {
 		migrationRepo = firebaseRepo.NewMigrationRepo(*firebaseApp, firebaseApiKey)
@@ -32,13 +47,29 @@ func NewRepository(db *sqlx.DB, client *minio.Client, firebaseApp *firebase.App,
 	return &Repository{
 		Auth:            postgres.NewAuthPostgres(db),
 		Profile:         postgres.NewProfilePostgres(db),
+		Follow:          postgres.NewFollowPostgres(db),
 		RecipeOwnership: postgres.NewRecipeOwnershipPostgres(db),
 		Recipe:          postgres.NewRecipePostgres(db),
 		RecipeSharing:   postgres.NewRecipeSharingPostgres(db),
+		RecipeLink:      postgres.NewRecipeLinkPostgres(db),
+		RecipePicture:   postgres.NewRecipePicturePostgres(db),
 		Encryption:      postgres.NewEncryptionPostgres(db),
 		Category:        postgres.NewCategoryPostgres(db),
+		Collection:      postgres.NewCollectionPostgres(db),
+		Tag:             postgres.NewTagPostgres(db),
+		Allergen:        postgres.NewAllergenPostgres(db),
+		Nutrition:       postgres.NewNutritionPostgres(db),
 		ShoppingList:    postgres.NewShoppingListPostgres(db),
-		File:            s3.NewAWSFileManager(client),
+		Trending:        postgres.NewTrendingPostgres(db),
+		Recommendation:  postgres.NewRecommendationPostgres(db),
+		File:            fileManager,
+		Storage:         postgres.NewStoragePostgres(db),
+		Subscription:    postgres.NewSubscriptionPostgres(db),
+		Broccoin:        postgres.NewBroccoinPostgres(db),
+		Referral:        postgres.NewReferralPostgres(db),
+		Achievement:     postgres.NewAchievementPostgres(db),
 		Migration:       migrationRepo,
+
+		SubscriptionProviders: subscriptionProviders,
 	}
 }
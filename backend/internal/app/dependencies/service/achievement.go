@@ -0,0 +1,7 @@
+package service
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Achievement interface {
+	GetAchievements(userId int) (entity.Achievements, error)
+}
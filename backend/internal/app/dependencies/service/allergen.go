@@ -0,0 +1,6 @@
+package service
+
+type Allergen interface {
+	GetSupportedAllergens() []string
+	DetectUnknownAllergens() error
+}
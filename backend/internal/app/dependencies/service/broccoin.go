@@ -0,0 +1,8 @@
+package service
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Broccoin interface {
+	GetTransactions(userId int, query entity.BroccoinTransactionsQuery) ([]entity.BroccoinTransaction, error)
+	SpendOnPremium(userId, days int) (entity.PremiumPurchase, error)
+}
@@ -8,5 +8,6 @@ type Category interface {
 	CreateCategory(category entity.CategoryInput, userId int) (int, error)
 	GetCategory(categoryId int, userId int) (entity.Category, error)
 	UpdateCategory(categoryId int, category entity.CategoryInput, userId int) error
+	SetCategoriesOrder(categories []entity.CategoryPosition, userId int) error
 	DeleteCategory(categoryId, userId int) error
 }
@@ -0,0 +1,15 @@
+package service
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Collection interface {
+	GetCollections(query entity.CollectionsQuery, userId int) ([]entity.Collection, error)
+	GetCollection(collectionId, userId int) (entity.Collection, error)
+	CreateCollection(collection entity.CollectionInput, userId int) (int, error)
+	UpdateCollection(collectionId int, collection entity.CollectionInput, userId int) error
+	DeleteCollection(collectionId, userId int) error
+	SetCollectionRecipes(collectionId int, recipeIds []int, userId int) error
+	SetCollectionFollowed(collectionId int, isFollowed bool, userId int) error
+	SetCollectionSaved(collectionId int, isSaved bool, userId int) error
+	ConvertCategoryToCollection(categoryId int, collection entity.CollectionInput, userId int) (int, error)
+}
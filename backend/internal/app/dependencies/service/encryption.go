@@ -6,10 +6,11 @@ import (
 )
 
 type Encryption interface {
-	GetUserKeyLink(userId int) (string, error)
+	GetUserKeyLink(ctx context.Context, userId int) (string, error)
 	UploadUserKey(ctx context.Context, userId int, file entity.MultipartFile) (string, error)
 	DeleteUserKey(ctx context.Context, userId int) error
-	GetRecipeKey(recipeId, userId int) (string, error)
+	GetRecipeKey(ctx context.Context, recipeId, userId int) (string, error)
 	UploadRecipeKey(ctx context.Context, recipeId, userId int, file entity.MultipartFile) (string, error)
 	DeleteRecipeKey(ctx context.Context, recipeId, userId int) error
+	MigrateKeys(ctx context.Context) (int, error)
 }
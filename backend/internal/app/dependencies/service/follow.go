@@ -0,0 +1,10 @@
+package service
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Follow interface {
+	FollowUser(authorId, userId int) error
+	UnfollowUser(authorId, userId int) error
+	GetFollowers(userId int) ([]entity.ProfileInfo, error)
+	GetFollowing(userId int) ([]entity.ProfileInfo, error)
+}
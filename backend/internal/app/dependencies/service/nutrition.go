@@ -0,0 +1,12 @@
+package service
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"io"
+)
+
+type Nutrition interface {
+	GetRecipeNutrition(recipeId, userId int, linkToken *string) (entity.Nutrition, error)
+	CalculateNutrition(ingredients []entity.IngredientItem, servings *int16, language string) entity.Nutrition
+	ImportFoods(reader io.Reader, format entity.FoodsCsvFormat) (int, error)
+}
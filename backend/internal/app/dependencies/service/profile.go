@@ -7,8 +7,12 @@ import (
 
 type Profile interface {
 	GetProfile(userId int) (entity.Profile, error)
+	GetPublicProfile(userId, requesterId int) (entity.PublicProfile, error)
 	ChangePassword(userId int, oldPassword string, newPassword string) error
 	SetUsername(userId int, username *string) error
+	SetBio(userId int, bio *string) error
+	SetExcludedAllergens(userId int, allergens []string) error
 	UploadAvatar(ctx context.Context, userId int, file entity.MultipartFile) (string, error)
 	DeleteAvatar(ctx context.Context, userId int) error
+	MigrateAvatarLinks() (int, error)
 }
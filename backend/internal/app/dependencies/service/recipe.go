@@ -7,32 +7,57 @@ import (
 
 type Recipe interface {
 	GetRecipes(query entity.RecipesQuery, userId int) ([]entity.RecipeInfo, error)
-	GetRecipe(recipeId, userId int) (entity.UserRecipe, error)
+	GetRecipesTagFacets(query entity.RecipesQuery, userId int) ([]entity.TagFacet, error)
+	GetFeed(query entity.RecipesQuery, userId int) ([]entity.RecipeInfo, error)
+	GetFeedTagFacets(query entity.RecipesQuery, userId int) ([]entity.TagFacet, error)
+	GetRecipe(recipeId, userId int, linkToken *string) (entity.UserRecipe, error)
+	GetSharedRecipe(linkToken string, userId int) (entity.UserRecipe, error)
 	GetRandomRecipe(languages *[]string, userId int) (entity.UserRecipe, error)
-	AddRecipeToRecipeBook(recipeId, userId int) error
+	AddRecipeToRecipeBook(recipeId, userId int, linkToken *string) error
 	RemoveRecipeFromRecipeBook(recipeId, userId int) error
 	SetRecipeCategories(recipeId int, categories []int, userId int) error
 	SetRecipeFavourite(recipeId int, favourite bool, userId int) error
-	SetRecipeLikeStatus(recipeId int, favourite bool, userId int) error
+	SetRecipeLikeStatus(recipeId int, favourite bool, userId int, linkToken *string) error
+	MarkRecipeCooked(recipeId, userId int, linkToken *string) error
 }
 
 type RecipeOwnership interface {
 	CreateRecipe(recipe entity.RecipeInput, userId int) (int, error)
-	UpdateRecipe(recipe entity.RecipeInput, recipeId, userId int) error
+	UpdateRecipe(ctx context.Context, recipe entity.RecipeInput, recipeId, userId int) error
 	DeleteRecipe(recipeId, userId int) error
+	MigratePictureLinks() (int, error)
 }
 
 type RecipePicture interface {
-	GetRecipePictures(ctx context.Context, recipeId int, userId int) ([]string, error)
-	UploadRecipePicture(ctx context.Context, recipeId, userId int, file entity.MultipartFile) (string, error)
-	DeleteRecipePicture(ctx context.Context, recipeId, userId int, pictureName string) error
+	GetRecipePictures(ctx context.Context, recipeId int, userId int, linkToken *string) ([]entity.RecipePicture, error)
+	GetRecipePicture(ctx context.Context, recipeId int, userId int, pictureId int, linkToken *string) (string, error)
+	UploadRecipePicture(ctx context.Context, recipeId, userId int, file entity.MultipartFile) (entity.RecipePicture, error)
+	GetRecipePictureUploadLink(ctx context.Context, recipeId, userId int, input entity.UploadInput) (entity.UploadLink, error)
+	ConfirmRecipePictureUpload(ctx context.Context, recipeId, userId int, uploadId string) (entity.RecipePicture, error)
+	UpdateRecipePicture(recipeId, userId, pictureId int, input entity.RecipePictureInput) error
+	SetRecipePicturesOrder(recipeId, userId int, pictureIds []int) error
+	DeleteRecipePicture(ctx context.Context, recipeId, userId, pictureId int) error
+	MigratePictures(ctx context.Context) (int, error)
+	ImportPictures(ctx context.Context) (int, error)
+}
+
+type RecipeLink interface {
+	CreateRecipeLink(recipeId int, link entity.RecipeLinkInput, userId int) (entity.RecipeLink, error)
+	GetRecipeLinks(recipeId, userId int) ([]entity.RecipeLink, error)
+	DeleteRecipeLink(recipeId, linkId, userId int) error
 }
 
 type RecipeSharing interface {
 	GetUsersList(recipeId, userId int) ([]entity.ProfileInfo, error)
 	GetUserPublicKey(recipeId, userId, requesterId int) (string, error)
 	SetUserPublicKey(recipeId int, userId int, userKey *string) error
-	GetOwnerPrivateKeyForUser(recipeId, userId int) (string, error)
+	GetOwnerPrivateKeyForUser(recipeId, userId int) (entity.RecipeUserKey, error)
 	SetOwnerPrivateKeyForUser(recipeId int, userId int, requesterId int, ownerKey *string) error
 	DeleteUserAccess(recipeId, userId, requesterId int) error
+	RequestRecipeKey(recipeId, userId int, userKey string, linkToken *string) (int, error)
+	GetRecipeKeyRequests(recipeId, userId int, status *string) ([]entity.RecipeKeyRequest, error)
+	GetUserKeyRequests(userId int, status *string) ([]entity.RecipeKeyRequest, error)
+	ApproveKeyRequest(recipeId, requestId, userId int, recipeKey string) error
+	RejectKeyRequest(recipeId, requestId, userId int) error
+	CancelKeyRequest(recipeId, requestId, userId int) error
 }
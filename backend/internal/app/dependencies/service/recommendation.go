@@ -0,0 +1,8 @@
+package service
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Recommendation interface {
+	UpdateRecommendationData() error
+	GetRecommendedRecipes(languages *[]string, count, userId int) ([]entity.RecipeInfo, error)
+}
@@ -0,0 +1,7 @@
+package service
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Referral interface {
+	GetReferrals(userId int) (entity.Referrals, error)
+}
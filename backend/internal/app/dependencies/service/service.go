@@ -3,10 +3,12 @@ package service
 import (
 	"github.com/mephistolie/chefbook-server/internal/app/dependencies/repository"
 	"github.com/mephistolie/chefbook-server/internal/config"
+	"github.com/mephistolie/chefbook-server/internal/entity"
 	"github.com/mephistolie/chefbook-server/internal/service"
 	"github.com/mephistolie/chefbook-server/pkg/auth"
 	"github.com/mephistolie/chefbook-server/pkg/cache"
 	"github.com/mephistolie/chefbook-server/pkg/hash"
+	"github.com/mephistolie/chefbook-server/pkg/imaging"
 	"github.com/mephistolie/chefbook-server/pkg/mail"
 	"time"
 )
@@ -14,49 +16,119 @@ import (
 type Service struct {
 	Auth
 	Profile
+	Follow
 	Recipe
 	RecipeOwnership
 	RecipeSharing
+	RecipeLink
 	RecipePicture
 	Encryption
 	Category
+	Collection
+	Tag
+	Allergen
+	Nutrition
 	ShoppingList
+	Trending
+	Recommendation
+	Storage
+	Quota
+	Entitlement
+	Subscription
+	Broccoin
+	Referral
+	Achievement
 }
 
 type Dependencies struct {
-	Repo                  *repository.Repository
-	Cache                 cache.Cache
-	HashManager           hash.HashManager
-	TokenManager          auth.TokenManager
-	MailSender            mail.Sender
-	MailConfig            config.MailConfig
-	AccessTokenTTL        time.Duration
-	RefreshTokenTTL       time.Duration
-	CacheTTL              int64
-	Environment           string
-	Domain                string
-	FirebaseImportEnabled bool
+	Repo                     *repository.Repository
+	Cache                    cache.Cache
+	HashManager              hash.HashManager
+	TokenManager             auth.TokenManager
+	MailSender               mail.Sender
+	MailConfig               config.MailConfig
+	AccessTokenTTL           time.Duration
+	RefreshTokenTTL          time.Duration
+	CacheTTL                 int64
+	Environment              string
+	Domain                   string
+	FirebaseImportEnabled    bool
+	TrendingParams           entity.TrendingParams
+	SimilarRecipesLimit      int
+	NutritionParams          entity.NutritionParams
+	PrivateLinkTTL           time.Duration
+	ImageProcessor           imaging.Processor
+	AvatarMaxSize            int64
+	RecipePictureMaxSize     int64
+	StorageGCGracePeriod     time.Duration
+	FreeEntitlements         entity.TierEntitlements
+	PremiumEntitlements      entity.TierEntitlements
+	SubscriptionProducts     []string
+	SubscriptionWebhookToken string
+	BroccoinParams           entity.BroccoinParams
+	ReferralParams           entity.ReferralParams
+	AchievementRewards       map[string]int
 }
 
 func NewService(dependencies Dependencies) *Service {
 
 	mailService := service.NewMailService(dependencies.MailSender, dependencies.MailConfig, dependencies.Cache)
+	broccoinService := service.NewBroccoinService(dependencies.Repo.Broccoin, dependencies.BroccoinParams)
+	referralService := service.NewReferralService(dependencies.Repo.Referral, broccoinService, dependencies.ReferralParams)
+	achievementService := service.NewAchievementService(dependencies.Repo.Achievement, broccoinService,
+		dependencies.AchievementRewards)
+	nutritionService := service.NewNutritionService(dependencies.Repo.Nutrition, dependencies.Repo.Recipe, dependencies.Repo.RecipeLink,
+		dependencies.NutritionParams)
+	entitlementService := service.NewEntitlementService(dependencies.Repo.Auth, dependencies.Repo.Collection,
+		dependencies.FreeEntitlements, dependencies.PremiumEntitlements)
+	quotaService := service.NewQuotaService(dependencies.Repo.Storage, dependencies.Repo.File, entitlementService,
+		dependencies.ImageProcessor)
+	picturesService := service.NewRecipePicturesService(dependencies.Repo.Recipe, dependencies.Repo.RecipeOwnership,
+		dependencies.Repo.RecipePicture, dependencies.Repo.RecipeLink, dependencies.Repo.File, quotaService,
+		dependencies.ImageProcessor, dependencies.PrivateLinkTTL, dependencies.RecipePictureMaxSize)
 	var firebaseService *service.FirebaseService = nil
 	if dependencies.FirebaseImportEnabled {
 		firebaseService = service.NewFirebaseService(dependencies.Repo.Migration, dependencies.Repo.Auth, dependencies.Repo.Profile,
-			dependencies.Repo.Recipe, dependencies.Repo.RecipeOwnership, dependencies.Repo.Category, dependencies.Repo.ShoppingList)
+			dependencies.Repo.Recipe, dependencies.Repo.RecipeOwnership, dependencies.Repo.Category, dependencies.Repo.ShoppingList,
+			broccoinService)
 	}
 
 	return &Service{
 		Auth: service.NewAuthService(dependencies.Repo.Auth, firebaseService, dependencies.HashManager, dependencies.TokenManager,
-			dependencies.AccessTokenTTL, dependencies.RefreshTokenTTL, *mailService, dependencies.Domain),
-		Profile:         service.NewProfileService(dependencies.Repo.Auth, dependencies.Repo.Profile, dependencies.Repo.File, dependencies.HashManager),
-		Recipe:          service.NewRecipeService(dependencies.Repo.Recipe, dependencies.Repo.Category),
-		RecipeOwnership: service.NewRecipeOwnershipService(dependencies.Repo.Recipe, dependencies.Repo.RecipeOwnership),
-		RecipeSharing:   service.NewRecipeSharingService(dependencies.Repo.Recipe, dependencies.Repo.RecipeSharing),
-		RecipePicture:   service.NewRecipePicturesService(dependencies.Repo.Recipe, dependencies.Repo.File),
-		Encryption:      service.NewEncryptionService(dependencies.Repo.Encryption, dependencies.Repo.RecipeSharing, dependencies.Repo.Recipe, dependencies.Repo.File),
+			dependencies.AccessTokenTTL, dependencies.RefreshTokenTTL, *mailService, broccoinService, referralService,
+			dependencies.Domain),
+		Profile:         service.NewProfileService(dependencies.Repo.Auth, dependencies.Repo.Profile, dependencies.Repo.File, quotaService,
+			dependencies.HashManager, dependencies.ImageProcessor, dependencies.AvatarMaxSize),
+		Follow:          service.NewFollowService(dependencies.Repo.Follow, dependencies.Repo.Auth, dependencies.Repo.File),
+		Recipe:          service.NewRecipeService(dependencies.Repo.Recipe, dependencies.Repo.Category, dependencies.Repo.Trending,
+			dependencies.Repo.Tag, dependencies.Repo.Profile, dependencies.Repo.RecipeLink, picturesService, broccoinService,
+			achievementService),
+		RecipeOwnership: service.NewRecipeOwnershipService(dependencies.Repo.Recipe, dependencies.Repo.RecipeOwnership, nutritionService, picturesService,
+			entitlementService, achievementService),
+		RecipeSharing:   service.NewRecipeSharingService(dependencies.Repo.Recipe, dependencies.Repo.RecipeSharing, dependencies.Repo.RecipeLink,
+			dependencies.Repo.Auth, dependencies.Repo.File, *mailService),
+		RecipeLink:      service.NewRecipeLinkService(dependencies.Repo.RecipeLink, dependencies.Repo.Recipe),
+		RecipePicture:   picturesService,
+		Encryption:      service.NewEncryptionService(dependencies.Repo.Encryption, dependencies.Repo.RecipeSharing, dependencies.Repo.Recipe, dependencies.Repo.File,
+			quotaService, entitlementService, dependencies.PrivateLinkTTL),
 		Category:        service.NewCategoriesService(dependencies.Repo.Category),
+		Collection:      service.NewCollectionService(dependencies.Repo.Collection, dependencies.Repo.Category, dependencies.Repo.Tag, picturesService,
+			entitlementService),
+		Tag:             service.NewTagService(dependencies.Repo.Tag, dependencies.Repo.Recipe),
+		Allergen:        service.NewAllergenService(dependencies.Repo.Allergen),
+		Nutrition:       nutritionService,
 		ShoppingList:    service.NewShoppingListService(dependencies.Repo.ShoppingList),
+		Trending:        service.NewTrendingService(dependencies.Repo.Trending, dependencies.TrendingParams),
+		Recommendation:  service.NewRecommendationService(dependencies.Repo.Recommendation, picturesService,
+			dependencies.SimilarRecipesLimit),
+		Storage:         service.NewStorageService(dependencies.Repo.Storage, dependencies.Repo.File, dependencies.ImageProcessor,
+			dependencies.StorageGCGracePeriod),
+		Quota:           quotaService,
+		Entitlement:     entitlementService,
+		Subscription:    service.NewSubscriptionService(dependencies.Repo.Subscription, dependencies.Repo.SubscriptionProviders,
+			dependencies.SubscriptionProducts, dependencies.SubscriptionWebhookToken),
+		Broccoin:        broccoinService,
+		Referral:        referralService,
+		Achievement:     achievementService,
 	}
 }
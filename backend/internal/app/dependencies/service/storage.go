@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+)
+
+type Storage interface {
+	CollectGarbage(ctx context.Context, dryRun bool) (entity.GarbageCollectionReport, error)
+}
+
+type Quota interface {
+	RecountUsage(ctx context.Context) (int, error)
+}
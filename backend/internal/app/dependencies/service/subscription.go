@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+)
+
+type Entitlement interface {
+	GetEntitlements(userId int) (entity.Entitlements, error)
+}
+
+type Subscription interface {
+	GetSubscriptions(userId int) ([]entity.Subscription, error)
+	VerifyPurchase(ctx context.Context, receipt entity.PurchaseReceipt, userId int) (entity.Subscription, error)
+	HandleNotification(ctx context.Context, provider, token string, body []byte) error
+	RefreshExpiredSubscriptions(ctx context.Context) (int, error)
+}
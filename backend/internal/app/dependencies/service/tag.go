@@ -0,0 +1,8 @@
+package service
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Tag interface {
+	GetTags() ([]entity.Tag, error)
+	SetRecipeTags(recipeId int, tags []string, userId int) error
+}
@@ -0,0 +1,5 @@
+package service
+
+type Trending interface {
+	UpdateTrendingScores() error
+}
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -17,6 +19,43 @@ const (
 	defaultLimiterRPS             = 10
 	defaultLimiterBurst           = 2
 	defaultLimiterTTL             = 10 * time.Minute
+	defaultTrendingInterval       = 15 * time.Minute
+	defaultTrendingHalfLife       = 48 * time.Hour
+	defaultTrendingWindow         = 24 * time.Hour * 14
+	defaultTrendingLikeWeight     = 3
+	defaultTrendingSaveWeight     = 5
+	defaultTrendingViewWeight     = 1
+	defaultNutritionMinConfidence = 0.5
+	defaultS3PrivateLinkTTL       = 5 * time.Minute
+	defaultImagesMaxSide          = 2048
+	defaultImagesMaxPixels        = 40000000
+	defaultUploadMaxSize          = 1 << 20
+	defaultImagesQuality          = 85
+	defaultLocalStoragePath       = "./storage"
+	defaultLocalStorageRoute      = "/storage"
+	defaultStorageGCInterval      = 24 * time.Hour
+	defaultStorageGCGracePeriod   = 72 * time.Hour
+	defaultFreeQuotaMaxBytes      = 100 << 20
+	defaultFreeQuotaMaxFiles      = 500
+	defaultPremiumQuotaMaxBytes   = 5 << 30
+	defaultPremiumQuotaMaxFiles   = 20000
+	defaultFreeMaxCollections     = 20
+	defaultSubscriptionsRefresh   = time.Hour
+	defaultFakeSubscriptionPeriod = 24 * time.Hour * 30
+	defaultLikeReward             = 1
+	defaultDailyLoginReward       = 5
+	defaultPremiumDayPrice        = 20
+	defaultReferrerBroccoins      = 50
+	defaultInviteeBroccoins       = 20
+	defaultReferralMaxRewards     = 50
+	defaultReferralDailyRewards   = 5
+	defaultReferralMaxPending     = 20
+
+	defaultRecommendationsInterval       = time.Hour
+	defaultRecommendationsSimilarRecipes = 50
+
+	StorageDriverS3    = "s3"
+	StorageDriverLocal = "local"
 
 	EnvDebug   = "debug"
 	EnvRelease = "release"
@@ -24,16 +63,27 @@ const (
 
 type (
 	Config struct {
-		Environment string
-		Postgres    PostgresConfig
-		HTTP        HTTPConfig
-		S3          S3Config
-		Auth        AuthConfig
-		Firebase    FirebaseConfig
-		Mail        MailConfig
-		Limiter     LimiterConfig
-		CacheTTL    time.Duration `mapstructure:"ttl"`
-		SMTP        SMTPConfig
+		Environment     string
+		Postgres        PostgresConfig
+		HTTP            HTTPConfig
+		Storage         StorageConfig
+		S3              S3Config
+		Auth            AuthConfig
+		Firebase        FirebaseConfig
+		Mail            MailConfig
+		Limiter         LimiterConfig
+		CacheTTL        time.Duration `mapstructure:"ttl"`
+		SMTP            SMTPConfig
+		Trending        TrendingConfig
+		Recommendations RecommendationsConfig
+		Nutrition       NutritionConfig
+		Images          ImagesConfig
+		Uploads         UploadsConfig
+		Entitlements    EntitlementsConfig
+		Subscriptions   SubscriptionsConfig
+		Broccoins       BroccoinsConfig
+		Referrals       ReferralsConfig
+		Achievements    AchievementsConfig
 	}
 
 	PostgresConfig struct {
@@ -69,11 +119,13 @@ type (
 	}
 
 	MailTemplates struct {
-		Verification string `mapstructure:"emailVerification"`
+		Verification       string `mapstructure:"emailVerification"`
+		KeyRequestResolved string `mapstructure:"keyRequestResolved"`
 	}
 
 	MailSubjects struct {
-		Verification string `mapstructure:"emailVerification"`
+		Verification       string `mapstructure:"emailVerification"`
+		KeyRequestResolved string `mapstructure:"keyRequestResolved"`
 	}
 
 	HTTPConfig struct {
@@ -85,9 +137,30 @@ type (
 	}
 
 	S3Config struct {
-		Host      string `mapstructure:"host"`
-		AccessKey string `mapstructure:"accessKey"`
-		SecretKey string `mapstructure:"secretKey"`
+		Host           string        `mapstructure:"host"`
+		AccessKey      string        `mapstructure:"accessKey"`
+		SecretKey      string        `mapstructure:"secretKey"`
+		PrivateLinkTTL time.Duration `mapstructure:"privateLinkTTL"`
+		Secure         bool          `mapstructure:"secure"`
+	}
+
+	StorageConfig struct {
+		Driver    string             `mapstructure:"driver"`
+		PublicUrl string             `mapstructure:"publicUrl"`
+		Local     LocalStorageConfig `mapstructure:"local"`
+		GC        StorageGCConfig    `mapstructure:"gc"`
+	}
+
+	StorageGCConfig struct {
+		Interval    time.Duration `mapstructure:"interval"`
+		GracePeriod time.Duration `mapstructure:"gracePeriod"`
+	}
+
+	LocalStorageConfig struct {
+		Path       string `mapstructure:"path"`
+		Url        string `mapstructure:"url"`
+		Route      string `mapstructure:"route"`
+		SigningKey string
 	}
 
 	LimiterConfig struct {
@@ -96,6 +169,106 @@ type (
 		TTL   time.Duration
 	}
 
+	TrendingConfig struct {
+		Interval   time.Duration `mapstructure:"interval"`
+		HalfLife   time.Duration `mapstructure:"halfLife"`
+		Window     time.Duration `mapstructure:"window"`
+		LikeWeight float64       `mapstructure:"likeWeight"`
+		SaveWeight float64       `mapstructure:"saveWeight"`
+		ViewWeight float64       `mapstructure:"viewWeight"`
+	}
+
+	RecommendationsConfig struct {
+		Interval       time.Duration `mapstructure:"interval"`
+		SimilarRecipes int           `mapstructure:"similarRecipes"`
+	}
+
+	NutritionConfig struct {
+		MinConfidence float64 `mapstructure:"minConfidence"`
+	}
+
+	UploadsConfig struct {
+		AvatarMaxSize        int64 `mapstructure:"avatarMaxSize"`
+		RecipePictureMaxSize int64 `mapstructure:"recipePictureMaxSize"`
+		KeyMaxSize           int64 `mapstructure:"keyMaxSize"`
+	}
+
+	EntitlementsConfig struct {
+		Free    TierConfig `mapstructure:"free"`
+		Premium TierConfig `mapstructure:"premium"`
+	}
+
+	TierConfig struct {
+		Encryption     bool        `mapstructure:"encryption"`
+		MaxCollections int         `mapstructure:"maxCollections"`
+		Storage        QuotaConfig `mapstructure:"storage"`
+	}
+
+	QuotaConfig struct {
+		MaxBytes int64 `mapstructure:"maxBytes"`
+		MaxFiles int   `mapstructure:"maxFiles"`
+	}
+
+	SubscriptionsConfig struct {
+		Products        []string                `mapstructure:"products"`
+		RefreshInterval time.Duration           `mapstructure:"refreshInterval"`
+		AppStore        AppStoreConfig          `mapstructure:"appStore"`
+		Play            PlayConfig              `mapstructure:"play"`
+		Fake            FakeSubscriptionsConfig `mapstructure:"fake"`
+		WebhookToken    string
+	}
+
+	AppStoreConfig struct {
+		Enabled      bool   `mapstructure:"enabled"`
+		BundleId     string `mapstructure:"bundleId"`
+		SharedSecret string
+	}
+
+	PlayConfig struct {
+		Enabled            bool   `mapstructure:"enabled"`
+		PackageName        string `mapstructure:"packageName"`
+		PrivateKeyFileName string
+	}
+
+	FakeSubscriptionsConfig struct {
+		Enabled bool          `mapstructure:"enabled"`
+		Period  time.Duration `mapstructure:"period"`
+	}
+
+	BroccoinsConfig struct {
+		LikeReward       int `mapstructure:"likeReward"`
+		DailyLoginReward int `mapstructure:"dailyLoginReward"`
+		PremiumDayPrice  int `mapstructure:"premiumDayPrice"`
+	}
+
+	ReferralsConfig struct {
+		Referrer ReferralRewardConfig `mapstructure:"referrer"`
+		Invitee  ReferralRewardConfig `mapstructure:"invitee"`
+		Limits   ReferralLimitsConfig `mapstructure:"limits"`
+	}
+
+	ReferralRewardConfig struct {
+		Broccoins   int `mapstructure:"broccoins"`
+		PremiumDays int `mapstructure:"premiumDays"`
+	}
+
+	ReferralLimitsConfig struct {
+		MaxRewards        int `mapstructure:"maxRewards"`
+		MaxDailyRewards   int `mapstructure:"maxDailyRewards"`
+		MaxPendingInvites int `mapstructure:"maxPendingInvites"`
+	}
+
+	AchievementsConfig struct {
+		Rewards map[string]int `mapstructure:"rewards"`
+	}
+
+	ImagesConfig struct {
+		MaxSide    int   `mapstructure:"maxSide"`
+		MaxPixels  int64 `mapstructure:"maxPixels"`
+		Quality    int   `mapstructure:"quality"`
+		Thumbnails []int `mapstructure:"thumbnails"`
+	}
+
 	SMTPConfig struct {
 		Host     string `mapstructure:"host"`
 		Port     int    `mapstructure:"port"`
@@ -118,9 +291,33 @@ func Init(configsDir string) (*Config, error) {
 
 	setFromEnv(&cfg)
 
+	if err := validate(&cfg); err != nil {
+		return nil, err
+	}
+
 	return &cfg, nil
 }
 
+// validate checks values which can't be fixed by defaults, e.g. intervals of scheduled jobs and image limits
+func validate(cfg *Config) error {
+	intervals := map[string]time.Duration{
+		"trending.interval":             cfg.Trending.Interval,
+		"trending.halfLife":             cfg.Trending.HalfLife,
+		"recommendations.interval":      cfg.Recommendations.Interval,
+		"storage.gc.interval":           cfg.Storage.GC.Interval,
+		"subscriptions.refreshInterval": cfg.Subscriptions.RefreshInterval,
+	}
+	for key, interval := range intervals {
+		if interval <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", key, interval)
+		}
+	}
+	if cfg.Images.MaxPixels <= 0 {
+		return fmt.Errorf("images.maxPixels must be positive, got %d", cfg.Images.MaxPixels)
+	}
+	return nil
+}
+
 func unmarshal(cfg *Config) error {
 	if err := viper.UnmarshalKey("cache.ttl", &cfg.CacheTTL); err != nil {
 		return err
@@ -134,6 +331,10 @@ func unmarshal(cfg *Config) error {
 		return err
 	}
 
+	if err := viper.UnmarshalKey("storage", &cfg.Storage); err != nil {
+		return err
+	}
+
 	if err := viper.UnmarshalKey("s3", &cfg.S3); err != nil {
 		return err
 	}
@@ -154,6 +355,46 @@ func unmarshal(cfg *Config) error {
 		return err
 	}
 
+	if err := viper.UnmarshalKey("trending", &cfg.Trending); err != nil {
+		return err
+	}
+
+	if err := viper.UnmarshalKey("recommendations", &cfg.Recommendations); err != nil {
+		return err
+	}
+
+	if err := viper.UnmarshalKey("nutrition", &cfg.Nutrition); err != nil {
+		return err
+	}
+
+	if err := viper.UnmarshalKey("images", &cfg.Images); err != nil {
+		return err
+	}
+
+	if err := viper.UnmarshalKey("uploads", &cfg.Uploads); err != nil {
+		return err
+	}
+
+	if err := viper.UnmarshalKey("entitlements", &cfg.Entitlements); err != nil {
+		return err
+	}
+
+	if err := viper.UnmarshalKey("subscriptions", &cfg.Subscriptions); err != nil {
+		return err
+	}
+
+	if err := viper.UnmarshalKey("broccoins", &cfg.Broccoins); err != nil {
+		return err
+	}
+
+	if err := viper.UnmarshalKey("referrals", &cfg.Referrals); err != nil {
+		return err
+	}
+
+	if err := viper.UnmarshalKey("achievements", &cfg.Achievements); err != nil {
+		return err
+	}
+
 	if err := viper.UnmarshalKey("mail.templates", &cfg.Mail.Templates); err != nil {
 		return err
 	}
@@ -176,6 +417,15 @@ func setFromEnv(cfg *Config) {
 
 	cfg.HTTP.Host = os.Getenv("HTTP_HOST")
 
+	cfg.Storage.Local.SigningKey = os.Getenv("STORAGE_SIGNING_KEY")
+	if cfg.Storage.Local.SigningKey == "" {
+		cfg.Storage.Local.SigningKey = cfg.Auth.JWT.SigningKey
+	}
+
+	cfg.Subscriptions.WebhookToken = os.Getenv("SUBSCRIPTION_WEBHOOK_TOKEN")
+	cfg.Subscriptions.AppStore.SharedSecret = os.Getenv("APP_STORE_SHARED_SECRET")
+	cfg.Subscriptions.Play.PrivateKeyFileName = os.Getenv("PLAY_KEY_FILE_NAME")
+
 	cfg.S3.AccessKey = os.Getenv("S3_ACCESS_KEY")
 	cfg.S3.SecretKey = os.Getenv("S3_SECRET_KEY")
 
@@ -212,4 +462,48 @@ func populateDefaults() {
 	viper.SetDefault("limiter.rps", defaultLimiterRPS)
 	viper.SetDefault("limiter.burst", defaultLimiterBurst)
 	viper.SetDefault("limiter.ttl", defaultLimiterTTL)
+	viper.SetDefault("trending.interval", defaultTrendingInterval)
+	viper.SetDefault("trending.halfLife", defaultTrendingHalfLife)
+	viper.SetDefault("trending.window", defaultTrendingWindow)
+	viper.SetDefault("trending.likeWeight", defaultTrendingLikeWeight)
+	viper.SetDefault("trending.saveWeight", defaultTrendingSaveWeight)
+	viper.SetDefault("trending.viewWeight", defaultTrendingViewWeight)
+
+	viper.SetDefault("recommendations.interval", defaultRecommendationsInterval)
+	viper.SetDefault("recommendations.similarRecipes", defaultRecommendationsSimilarRecipes)
+	viper.SetDefault("nutrition.minConfidence", defaultNutritionMinConfidence)
+	viper.SetDefault("storage.driver", StorageDriverS3)
+	viper.SetDefault("storage.local.path", defaultLocalStoragePath)
+	viper.SetDefault("storage.local.route", defaultLocalStorageRoute)
+	viper.SetDefault("storage.gc.interval", defaultStorageGCInterval)
+	viper.SetDefault("storage.gc.gracePeriod", defaultStorageGCGracePeriod)
+	viper.SetDefault("s3.privateLinkTTL", defaultS3PrivateLinkTTL)
+	viper.SetDefault("s3.secure", true)
+	viper.SetDefault("images.maxSide", defaultImagesMaxSide)
+	viper.SetDefault("images.maxPixels", defaultImagesMaxPixels)
+	viper.SetDefault("images.quality", defaultImagesQuality)
+	viper.SetDefault("uploads.avatarMaxSize", defaultUploadMaxSize)
+	viper.SetDefault("uploads.recipePictureMaxSize", defaultUploadMaxSize)
+	viper.SetDefault("uploads.keyMaxSize", defaultUploadMaxSize)
+	viper.SetDefault("entitlements.free.maxCollections", defaultFreeMaxCollections)
+	viper.SetDefault("entitlements.free.storage.maxBytes", defaultFreeQuotaMaxBytes)
+	viper.SetDefault("entitlements.free.storage.maxFiles", defaultFreeQuotaMaxFiles)
+	viper.SetDefault("entitlements.premium.encryption", true)
+	viper.SetDefault("entitlements.premium.storage.maxBytes", defaultPremiumQuotaMaxBytes)
+	viper.SetDefault("entitlements.premium.storage.maxFiles", defaultPremiumQuotaMaxFiles)
+	viper.SetDefault("subscriptions.refreshInterval", defaultSubscriptionsRefresh)
+	viper.SetDefault("subscriptions.fake.period", defaultFakeSubscriptionPeriod)
+	viper.SetDefault("broccoins.likeReward", defaultLikeReward)
+	viper.SetDefault("broccoins.dailyLoginReward", defaultDailyLoginReward)
+	viper.SetDefault("broccoins.premiumDayPrice", defaultPremiumDayPrice)
+	viper.SetDefault("referrals.referrer.broccoins", defaultReferrerBroccoins)
+	viper.SetDefault("referrals.invitee.broccoins", defaultInviteeBroccoins)
+	viper.SetDefault("referrals.limits.maxRewards", defaultReferralMaxRewards)
+	viper.SetDefault("referrals.limits.maxDailyRewards", defaultReferralDailyRewards)
+	viper.SetDefault("referrals.limits.maxPendingInvites", defaultReferralMaxPending)
+}
+
+// BaseUrl returns address of local storage, which is prefix of all stored file links
+func (c LocalStorageConfig) BaseUrl() string {
+	return strings.TrimSuffix(c.Url, "/") + c.Route
 }
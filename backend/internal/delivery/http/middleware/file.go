@@ -1,45 +1,113 @@
 package middleware
 
 import (
+	"bufio"
 	"bytes"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/mephistolie/chefbook-server/internal/config"
 	"github.com/mephistolie/chefbook-server/internal/entity"
 	"github.com/mephistolie/chefbook-server/internal/entity/failure"
-	"mime/multipart"
+	"io"
 	"net/http"
 )
 
+const (
+	fileFormName = "file"
 
+	sniffLength          = 512
+	maxMultipartOverhead = 1 << 16
+	unknownSize          = -1
+)
 
 type FileMiddleware struct {
+	limits config.UploadsConfig
+}
+
+func NewFile(limits config.UploadsConfig) *FileMiddleware {
+	return &FileMiddleware{
+		limits: limits,
+	}
+}
+
+func (h *FileMiddleware) GetAvatar(c *gin.Context) (entity.MultipartFile, error) {
+	return h.GetFileWithMaxSize(c, h.limits.AvatarMaxSize)
 }
 
-func NewFile() *FileMiddleware {
-	return &FileMiddleware{}
+func (h *FileMiddleware) GetRecipePicture(c *gin.Context) (entity.MultipartFile, error) {
+	return h.GetFileWithMaxSize(c, h.limits.RecipePictureMaxSize)
 }
 
+func (h *FileMiddleware) GetKey(c *gin.Context) (entity.MultipartFile, error) {
+	return h.GetFileWithMaxSize(c, h.limits.KeyMaxSize)
+}
+
+// GetFileWithMaxSize returns file from multipart form without buffering it.
+// File content fails with failure.InvalidFileSize as soon as more than maxSize bytes are read
 func (h *FileMiddleware) GetFileWithMaxSize(c *gin.Context, maxSize int64) (entity.MultipartFile, error) {
-	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize)
-	file, header, err := c.Request.FormFile("file")
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+maxMultipartOverhead)
+	reader, err := c.Request.MultipartReader()
 	if err != nil {
-		return entity.MultipartFile{}, failure.InvalidFileSize
+		return entity.MultipartFile{}, failure.InvalidBody
 	}
 
-	defer func(file multipart.File) {
-		_ = file.Close()
-	}(file)
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return entity.MultipartFile{}, failure.InvalidFileSize
+		}
+		if part.FormName() != fileFormName {
+			continue
+		}
 
-	buffer := make([]byte, header.Size)
-	_, err = file.Read(buffer)
-	if err != nil {
-		return entity.MultipartFile{}, failure.InvalidFileSize
+		content := bufio.NewReaderSize(part, sniffLength)
+		header, err := content.Peek(sniffLength)
+		if len(header) == 0 || (err != nil && err != io.EOF) {
+			return entity.MultipartFile{}, failure.InvalidFileSize
+		}
+
+		return entity.MultipartFile{
+			Name:        uuid.NewString(),
+			Content:     &limitedReader{reader: content, remaining: maxSize},
+			Size:        unknownSize,
+			ContentType: detectContentType(header),
+		}, nil
+	}
+}
+
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n, failure.InvalidFileSize
+	}
+	return n, err
+}
+
+// detectContentType checks magic bytes of modern image formats which aren't covered by http.DetectContentType
+func detectContentType(header []byte) string {
+	if len(header) >= 12 && bytes.Equal(header[:4], []byte("RIFF")) && bytes.Equal(header[8:12], []byte("WEBP")) {
+		return entity.ContentTypeWebP
+	}
+
+	if len(header) >= 12 && bytes.Equal(header[4:8], []byte("ftyp")) {
+		switch string(header[8:12]) {
+		case "avif", "avis":
+			return entity.ContentTypeAvif
+		case "heic", "heix", "hevc", "hevx", "heim", "heis":
+			return entity.ContentTypeHeic
+		case "mif1", "msf1":
+			return entity.ContentTypeHeif
+		}
 	}
 
-	return entity.MultipartFile{
-		Name:        uuid.NewString(),
-		Content:     bytes.NewReader(buffer),
-		Size:        header.Size,
-		ContentType: http.DetectContentType(buffer),
-	}, nil
+	return http.DetectContentType(header)
 }
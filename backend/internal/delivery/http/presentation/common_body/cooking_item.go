@@ -7,11 +7,12 @@ import (
 )
 
 type CookingItem struct {
-	Text     string    `json:"text"`
-	Type     string    `json:"type"`
-	Link     *string   `json:"link,omitempty"`
-	Time     *int16    `json:"time,omitempty"`
-	Pictures *[]string `json:"pictures,omitempty"`
+	Text       string    `json:"text"`
+	Type       string    `json:"type"`
+	Link       *string   `json:"link,omitempty"`
+	Time       *int16    `json:"time,omitempty"`
+	Pictures   *[]string `json:"pictures,omitempty"`
+	PictureIds *[]int    `json:"picture_ids,omitempty"`
 }
 
 func (i *CookingItem) Validate() error {
@@ -30,20 +31,22 @@ func (i *CookingItem) IsEncrypted() bool {
 
 func (i *CookingItem) Entity() entity.CookingItem {
 	return entity.CookingItem{
-		Text:     i.Text,
-		Type:     i.Type,
-		Time:     i.Time,
-		Pictures: i.Pictures,
-		Link:     i.Link,
+		Text:       i.Text,
+		Type:       i.Type,
+		Time:       i.Time,
+		Pictures:   i.Pictures,
+		PictureIds: i.PictureIds,
+		Link:       i.Link,
 	}
 }
 
 func NewCookingItem(cookingItem entity.CookingItem) CookingItem {
 	return CookingItem{
-		Text:     cookingItem.Text,
-		Type:     cookingItem.Type,
-		Time:     cookingItem.Time,
-		Pictures: cookingItem.Pictures,
-		Link:     cookingItem.Link,
+		Text:       cookingItem.Text,
+		Type:       cookingItem.Type,
+		Time:       cookingItem.Time,
+		Pictures:   cookingItem.Pictures,
+		PictureIds: cookingItem.PictureIds,
+		Link:       cookingItem.Link,
 	}
 }
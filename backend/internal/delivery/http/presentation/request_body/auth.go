@@ -45,6 +45,11 @@ func (c *Credentials) Entity() entity.Credentials {
 	}
 }
 
+type SignUp struct {
+	Credentials
+	ReferralCode *string `json:"referral_code,omitempty" binding:"omitempty,max=16"`
+}
+
 type RefreshToken struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
\ No newline at end of file
@@ -0,0 +1,46 @@
+package request_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+)
+
+type BroccoinTransactionsQuery struct {
+	Page     int
+	PageSize int
+}
+
+func (p *BroccoinTransactionsQuery) Validate() error {
+	if p.Page == 0 {
+		p.Page = 1
+	}
+
+	if p.Page < 0 {
+		return failure.InvalidBody
+	}
+
+	if p.PageSize == 0 {
+		p.PageSize = 20
+	}
+
+	if p.PageSize < 0 {
+		return failure.InvalidBody
+	}
+
+	if p.PageSize > 50 {
+		p.PageSize = 50
+	}
+
+	return nil
+}
+
+func (p *BroccoinTransactionsQuery) Entity() entity.BroccoinTransactionsQuery {
+	return entity.BroccoinTransactionsQuery{
+		Page:     p.Page,
+		PageSize: p.PageSize,
+	}
+}
+
+type BroccoinsSpendInput struct {
+	PremiumDays int `json:"premium_days" binding:"required,min=1,max=365"`
+}
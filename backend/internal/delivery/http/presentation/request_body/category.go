@@ -1,15 +1,61 @@
 package request_body
 
-import "github.com/mephistolie/chefbook-server/internal/entity"
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+)
 
 type CategoryInput struct {
-	Name  string  `json:"name" binding:"required,min=1,max=50"`
-	Cover *string `json:"cover" binding:"max=20"`
+	Name     string  `json:"name" binding:"required,min=1,max=50"`
+	Cover    *string `json:"cover" binding:"max=20"`
+	ParentId *int    `json:"parent_id,omitempty"`
+}
+
+func (c *CategoryInput) Validate() error {
+	if c.ParentId != nil && *c.ParentId <= 0 {
+		return failure.InvalidBody
+	}
+	return nil
 }
 
 func (c *CategoryInput) Entity() entity.CategoryInput {
 	return entity.CategoryInput{
-		Name:  c.Name,
-		Cover: c.Cover,
+		Name:     c.Name,
+		Cover:    c.Cover,
+		ParentId: c.ParentId,
+	}
+}
+
+type CategoryPosition struct {
+	Id       int  `json:"id" binding:"required"`
+	ParentId *int `json:"parent_id,omitempty"`
+}
+
+type CategoriesOrderInput struct {
+	Categories []CategoryPosition `json:"categories" binding:"dive"`
+}
+
+func (p *CategoriesOrderInput) Validate() error {
+	uniqueCategories := make(map[int]bool)
+	for _, category := range p.Categories {
+		if category.Id <= 0 || uniqueCategories[category.Id] {
+			return failure.InvalidCategoriesOrder
+		}
+		if category.ParentId != nil && *category.ParentId <= 0 {
+			return failure.InvalidBody
+		}
+		uniqueCategories[category.Id] = true
+	}
+	return nil
+}
+
+func (p *CategoriesOrderInput) Entity() []entity.CategoryPosition {
+	categories := make([]entity.CategoryPosition, len(p.Categories))
+	for i, category := range p.Categories {
+		categories[i] = entity.CategoryPosition{
+			Id:       category.Id,
+			ParentId: category.ParentId,
+		}
 	}
+	return categories
 }
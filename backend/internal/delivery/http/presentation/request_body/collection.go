@@ -0,0 +1,166 @@
+package request_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"strings"
+)
+
+const maxCollectionRecipes = 500
+
+type CollectionInput struct {
+	Name        string  `json:"name" binding:"required,min=1,max=100"`
+	Description *string `json:"description" binding:"omitempty,max=1500"`
+	Visibility  string  `json:"visibility"`
+}
+
+func (c *CollectionInput) Validate() error {
+	visibility, err := validateCollectionVisibility(c.Visibility)
+	if err != nil {
+		return err
+	}
+	c.Visibility = visibility
+	return nil
+}
+
+func (c *CollectionInput) Entity() entity.CollectionInput {
+	return entity.CollectionInput{
+		Name:        c.Name,
+		Description: c.Description,
+		Visibility:  c.Visibility,
+	}
+}
+
+type CategoryConversionInput struct {
+	Description *string `json:"description" binding:"omitempty,max=1500"`
+	Visibility  string  `json:"visibility"`
+}
+
+func (c *CategoryConversionInput) Validate() error {
+	visibility, err := validateCollectionVisibility(c.Visibility)
+	if err != nil {
+		return err
+	}
+	c.Visibility = visibility
+	return nil
+}
+
+func (c *CategoryConversionInput) Entity() entity.CollectionInput {
+	return entity.CollectionInput{
+		Description: c.Description,
+		Visibility:  c.Visibility,
+	}
+}
+
+type CollectionRecipesInput struct {
+	Recipes []int `json:"recipes"`
+}
+
+func (c *CollectionRecipesInput) Validate() error {
+	uniqueRecipes := make(map[int]bool)
+	recipes := []int{}
+	for _, recipeId := range c.Recipes {
+		if recipeId <= 0 {
+			return failure.InvalidBody
+		}
+		if !uniqueRecipes[recipeId] {
+			uniqueRecipes[recipeId] = true
+			recipes = append(recipes, recipeId)
+		}
+	}
+
+	if len(recipes) > maxCollectionRecipes {
+		return failure.TooManyCollectionRecipes
+	}
+	c.Recipes = recipes
+
+	return nil
+}
+
+type CollectionsQuery struct {
+	AuthorId *int
+	Owned    bool
+	Saved    bool
+	Followed bool
+	Search   *string
+	Page     int
+	PageSize int
+	SortBy   string
+}
+
+func (p *CollectionsQuery) Validate(userId int) error {
+	if p.AuthorId != nil && *p.AuthorId <= 0 {
+		return failure.InvalidBody
+	}
+
+	if p.Owned && p.AuthorId != nil && *p.AuthorId != userId {
+		return failure.InvalidBody
+	}
+
+	if p.Owned {
+		p.AuthorId = &userId
+	}
+
+	if p.Search != nil && *p.Search == "" {
+		p.Search = nil
+	}
+
+	if p.Page == 0 {
+		p.Page = 1
+	}
+
+	if p.Page < 0 {
+		return failure.InvalidBody
+	}
+
+	if p.PageSize == 0 {
+		p.PageSize = 10
+	}
+
+	if p.PageSize < 0 {
+		return failure.InvalidBody
+	}
+
+	if p.PageSize > 50 {
+		p.PageSize = 50
+	}
+
+	if p.SortBy == "" {
+		p.SortBy = entity.SortingUpdateTimestamp
+	}
+	p.SortBy = strings.ToLower(p.SortBy)
+
+	switch p.SortBy {
+	case entity.SortingCreationTimestamp, entity.SortingUpdateTimestamp, entity.SortingFollowers:
+	default:
+		return failure.InvalidBody
+	}
+
+	return nil
+}
+
+func (p *CollectionsQuery) Entity() entity.CollectionsQuery {
+	return entity.CollectionsQuery{
+		AuthorId: p.AuthorId,
+		Saved:    p.Saved,
+		Followed: p.Followed,
+		Search:   p.Search,
+		Page:     p.Page,
+		PageSize: p.PageSize,
+		SortBy:   p.SortBy,
+	}
+}
+
+// validateCollectionVisibility allows public and shared collections only; shared collections
+// aren't listed anywhere and accessible by id
+func validateCollectionVisibility(visibility string) (string, error) {
+	visibility = strings.ToLower(visibility)
+	switch visibility {
+	case "":
+		return entity.VisibilityPublic, nil
+	case entity.VisibilityPublic, entity.VisibilityShared:
+		return visibility, nil
+	default:
+		return "", failure.InvalidBody
+	}
+}
@@ -0,0 +1,20 @@
+package request_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"strings"
+)
+
+func ValidateKeyRequestStatus(status *string) error {
+	if status == nil {
+		return nil
+	}
+	*status = strings.ToLower(*status)
+	switch *status {
+	case entity.KeyRequestPending, entity.KeyRequestApproved, entity.KeyRequestRejected, entity.KeyRequestCancelled:
+		return nil
+	default:
+		return failure.InvalidBody
+	}
+}
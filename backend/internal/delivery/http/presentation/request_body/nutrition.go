@@ -0,0 +1,44 @@
+package request_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/common_body"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"strings"
+)
+
+type NutritionInput struct {
+	Language    string                       `json:"language"`
+	Servings    *int16                       `json:"servings"`
+	Ingredients []common_body.IngredientItem `json:"ingredients"`
+}
+
+func (n *NutritionInput) Validate() error {
+	if len(n.Language) != 2 {
+		n.Language = entity.CodeEnglish
+	}
+	n.Language = strings.ToLower(n.Language)
+
+	if len(n.Ingredients) == 0 {
+		return failure.EmptyIngredients
+	}
+
+	for _, ingredient := range n.Ingredients {
+		if err := ingredient.Validate(); err != nil {
+			return err
+		}
+		if ingredient.IsEncrypted() {
+			return failure.UnableCalculateNutrition
+		}
+	}
+
+	return nil
+}
+
+func (n *NutritionInput) IngredientsEntity() []entity.IngredientItem {
+	ingredients := make([]entity.IngredientItem, len(n.Ingredients))
+	for i, ingredient := range n.Ingredients {
+		ingredients[i] = ingredient.Entity()
+	}
+	return ingredients
+}
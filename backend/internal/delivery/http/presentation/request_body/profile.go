@@ -1,5 +1,10 @@
 package request_body
 
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"github.com/mephistolie/chefbook-server/pkg/allergen"
+)
+
 type PasswordChanging struct {
 	OldPassword string `json:"old_password" binding:"max=64"`
 	NewPassword string `json:"new_password" binding:"required,min=8,max=64"`
@@ -12,3 +17,28 @@ func (p *PasswordChanging) Validate() error {
 type Username struct {
 	Username *string `json:"username" binding:"max=40"`
 }
+
+type Bio struct {
+	Bio *string `json:"bio" binding:"max=300"`
+}
+
+type ExcludedAllergens struct {
+	Allergens []string `json:"allergens"`
+}
+
+func (a *ExcludedAllergens) Validate() error {
+	uniqueAllergens := make(map[string]bool)
+	allergens := []string{}
+	for _, excludedAllergen := range a.Allergens {
+		if !allergen.IsSupported(excludedAllergen) {
+			return failure.InvalidAllergen
+		}
+		if !uniqueAllergens[excludedAllergen] {
+			uniqueAllergens[excludedAllergen] = true
+			allergens = append(allergens, excludedAllergen)
+		}
+	}
+	a.Allergens = allergens
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package request_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"time"
+)
+
+type RecipeLinkInput struct {
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func (l *RecipeLinkInput) Validate() error {
+	if l.ExpiresAt != nil && !l.ExpiresAt.After(time.Now()) {
+		return failure.InvalidBody
+	}
+	return nil
+}
+
+func (l *RecipeLinkInput) Entity() entity.RecipeLinkInput {
+	return entity.RecipeLinkInput{
+		ExpiresAt: l.ExpiresAt,
+	}
+}
@@ -0,0 +1,46 @@
+package request_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"unicode/utf8"
+)
+
+const maxPictureCaptionLength = 500
+
+type RecipePictureInput struct {
+	Caption   *string `json:"caption,omitempty"`
+	IsPreview bool    `json:"is_preview"`
+}
+
+func (p *RecipePictureInput) Validate() error {
+	if p.Caption != nil && utf8.RuneCountInString(*p.Caption) > maxPictureCaptionLength {
+		return failure.TooLongCaption
+	}
+	if p.Caption != nil && len(*p.Caption) == 0 {
+		p.Caption = nil
+	}
+	return nil
+}
+
+func (p *RecipePictureInput) Entity() entity.RecipePictureInput {
+	return entity.RecipePictureInput{
+		Caption:   p.Caption,
+		IsPreview: p.IsPreview,
+	}
+}
+
+type RecipePicturesOrderInput struct {
+	Pictures []int `json:"pictures"`
+}
+
+func (p *RecipePicturesOrderInput) Validate() error {
+	uniquePictures := make(map[int]bool)
+	for _, pictureId := range p.Pictures {
+		if pictureId <= 0 || uniquePictures[pictureId] {
+			return failure.InvalidPictureOrder
+		}
+		uniquePictures[pictureId] = true
+	}
+	return nil
+}
@@ -15,12 +15,19 @@ type RecipesQuery struct {
 	PageSize    int
 	SortBy      string
 	Languages   *[]string
+	Tags        *[]string
+	ExcludeTags *[]string
+	WithFacets  bool
+	Collections bool
 	MinTime     *int
 	MaxTime     *int
 	MinServings *int
 	MaxServings *int
 	MinCalories *int
 	MaxCalories *int
+
+	CategoryId           *int
+	IncludeSubcategories bool
 }
 
 func (p *RecipesQuery) Validate(userId int) error {
@@ -36,6 +43,10 @@ func (p *RecipesQuery) Validate(userId int) error {
 		p.AuthorId = &userId
 	}
 
+	if p.CategoryId != nil && *p.CategoryId <= 0 {
+		return failure.InvalidBody
+	}
+
 	if p.Search != nil && *p.Search == "" {
 		p.Search = nil
 	}
@@ -66,12 +77,20 @@ func (p *RecipesQuery) Validate(userId int) error {
 	p.SortBy = strings.ToLower(p.SortBy)
 
 	switch p.SortBy {
-	case entity.SortingCreationTimestamp, entity.SortingUpdateTimestamp, entity.SortingLikes, entity.SortingTime,
-		entity.SortingServings, entity.SortingCalories:
+	case entity.SortingCreationTimestamp, entity.SortingUpdateTimestamp, entity.SortingLikes, entity.SortingTrending,
+		entity.SortingTime, entity.SortingServings, entity.SortingCalories:
 	default:
 		return failure.InvalidBody
 	}
 
+	if err := validateTagsFilter(p.Tags); err != nil {
+		return err
+	}
+
+	if err := validateTagsFilter(p.ExcludeTags); err != nil {
+		return err
+	}
+
 	if p.MinTime != nil && *p.MinTime <= 0 {
 		p.MinTime = nil
 	}
@@ -113,18 +132,37 @@ func (p *RecipesQuery) Validate(userId int) error {
 
 func (p *RecipesQuery) Entity() entity.RecipesQuery {
 	return entity.RecipesQuery{
-		AuthorId:    p.AuthorId,
-		Saved:       p.Saved,
-		Search:      p.Search,
-		Page:        p.Page,
-		PageSize:    p.PageSize,
-		SortBy:      p.SortBy,
-		Languages:   p.Languages,
-		MinTime:     p.MinTime,
-		MaxTime:     p.MaxTime,
-		MinCalories: p.MinCalories,
-		MaxCalories: p.MaxCalories,
-		MinServings: p.MinServings,
-		MaxServings: p.MaxServings,
+		AuthorId:            p.AuthorId,
+		Saved:               p.Saved,
+		FollowedCollections: p.Collections,
+		Search:              p.Search,
+		Page:                p.Page,
+		PageSize:            p.PageSize,
+		SortBy:              p.SortBy,
+		Languages:           p.Languages,
+		Tags:                p.Tags,
+		ExcludeTags:         p.ExcludeTags,
+		MinTime:             p.MinTime,
+		MaxTime:             p.MaxTime,
+		MinCalories:         p.MinCalories,
+		MaxCalories:         p.MaxCalories,
+		MinServings:         p.MinServings,
+		MaxServings:         p.MaxServings,
+
+		CategoryId:           p.CategoryId,
+		IncludeSubcategories: p.IncludeSubcategories,
+	}
+}
+
+func validateTagsFilter(tags *[]string) error {
+	if tags == nil {
+		return nil
+	}
+	for i, tag := range *tags {
+		(*tags)[i] = strings.ToLower(tag)
+		if !IsValidTag((*tags)[i]) {
+			return failure.InvalidTag
+		}
 	}
+	return nil
 }
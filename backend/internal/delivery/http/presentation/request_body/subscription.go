@@ -0,0 +1,17 @@
+package request_body
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type PurchaseReceipt struct {
+	Provider  string `json:"provider" binding:"required"`
+	ProductId string `json:"product_id" binding:"required,max=255"`
+	Receipt   string `json:"receipt" binding:"required"`
+}
+
+func (r *PurchaseReceipt) Entity() entity.PurchaseReceipt {
+	return entity.PurchaseReceipt{
+		Provider:  r.Provider,
+		ProductId: r.ProductId,
+		Receipt:   r.Receipt,
+	}
+}
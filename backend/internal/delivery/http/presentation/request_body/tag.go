@@ -0,0 +1,46 @@
+package request_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"strings"
+)
+
+const maxRecipeTags = 10
+
+type RecipeTagsInput struct {
+	Tags []string `json:"tags"`
+}
+
+func (r *RecipeTagsInput) Validate() error {
+	uniqueTags := make(map[string]bool)
+	var tags []string
+	for _, tag := range r.Tags {
+		tag = strings.ToLower(tag)
+		if !IsValidTag(tag) {
+			return failure.InvalidTag
+		}
+		if !uniqueTags[tag] {
+			uniqueTags[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	if len(tags) > maxRecipeTags {
+		return failure.TooManyTags
+	}
+	r.Tags = tags
+
+	return nil
+}
+
+func IsValidTag(tag string) bool {
+	if len(tag) == 0 || len(tag) > 32 {
+		return false
+	}
+	for _, symbol := range tag {
+		if (symbol < 'a' || symbol > 'z') && symbol != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,21 @@
+package request_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+)
+
+type UploadInput struct {
+	ContentType string `json:"content_type" binding:"required,max=100"`
+	Size        int64  `json:"size" binding:"required,min=1"`
+}
+
+func (i *UploadInput) Entity() entity.UploadInput {
+	return entity.UploadInput{
+		ContentType: i.ContentType,
+		Size:        i.Size,
+	}
+}
+
+type UploadConfirmation struct {
+	UploadId string `json:"upload_id" binding:"required,uuid"`
+}
@@ -0,0 +1,49 @@
+package response_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"time"
+)
+
+type Achievement struct {
+	Code            string     `json:"code"`
+	Unlocked        bool       `json:"unlocked"`
+	UnlockTimestamp *time.Time `json:"unlock_timestamp,omitempty"`
+	Progress        int        `json:"progress"`
+	Target          int        `json:"target"`
+	Reward          int        `json:"reward,omitempty"`
+}
+
+type CookingStreak struct {
+	Current int `json:"current"`
+	Longest int `json:"longest"`
+}
+
+type Achievements struct {
+	Achievements  []Achievement `json:"achievements"`
+	CookingStreak CookingStreak `json:"cooking_streak"`
+}
+
+func NewAchievements(achievements entity.Achievements) Achievements {
+	response := Achievements{
+		Achievements: make([]Achievement, len(achievements.Achievements)),
+		CookingStreak: CookingStreak{
+			Current: achievements.CookingStreak.Current,
+			Longest: achievements.CookingStreak.Longest,
+		},
+	}
+	for i, achievement := range achievements.Achievements {
+		response.Achievements[i] = Achievement{
+			Code:     achievement.Code,
+			Unlocked: achievement.UnlockTimestamp != nil,
+			Progress: achievement.Progress,
+			Target:   achievement.Target,
+			Reward:   achievement.Reward,
+		}
+		if achievement.UnlockTimestamp != nil {
+			unlockTimestamp := achievement.UnlockTimestamp.UTC()
+			response.Achievements[i].UnlockTimestamp = &unlockTimestamp
+		}
+	}
+	return response
+}
@@ -0,0 +1,44 @@
+package response_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"time"
+)
+
+type BroccoinTransaction struct {
+	Id                int       `json:"id"`
+	Amount            int       `json:"amount"`
+	Balance           int       `json:"balance"`
+	Reason            string    `json:"reason"`
+	CreationTimestamp time.Time `json:"creation_timestamp"`
+}
+
+func NewBroccoinTransaction(transaction entity.BroccoinTransaction) BroccoinTransaction {
+	return BroccoinTransaction{
+		Id:                transaction.Id,
+		Amount:            transaction.Amount,
+		Balance:           transaction.Balance,
+		Reason:            transaction.Reason,
+		CreationTimestamp: transaction.CreationTimestamp.UTC(),
+	}
+}
+
+func NewBroccoinTransactions(entities []entity.BroccoinTransaction) []BroccoinTransaction {
+	transactions := make([]BroccoinTransaction, len(entities))
+	for i, transaction := range entities {
+		transactions[i] = NewBroccoinTransaction(transaction)
+	}
+	return transactions
+}
+
+type PremiumPurchase struct {
+	Transaction    BroccoinTransaction `json:"transaction"`
+	PremiumEndDate time.Time           `json:"premium_end_date"`
+}
+
+func NewPremiumPurchase(purchase entity.PremiumPurchase) PremiumPurchase {
+	return PremiumPurchase{
+		Transaction:    NewBroccoinTransaction(purchase.Transaction),
+		PremiumEndDate: purchase.PremiumEndDate.UTC(),
+	}
+}
@@ -3,16 +3,20 @@ package response_body
 import "github.com/mephistolie/chefbook-server/internal/entity"
 
 type Category struct {
-	Id    int     `json:"id"`
-	Name  string  `json:"name"`
-	Cover *string `json:"cover"`
+	Id       int     `json:"id"`
+	Name     string  `json:"name"`
+	Cover    *string `json:"cover"`
+	ParentId *int    `json:"parent_id,omitempty"`
+	Position int     `json:"position"`
 }
 
 func NewCategory(category entity.Category) Category {
 	return Category{
-		Id:    category.Id,
-		Name:  category.Name,
-		Cover: category.Cover,
+		Id:       category.Id,
+		Name:     category.Name,
+		Cover:    category.Cover,
+		ParentId: category.ParentId,
+		Position: category.Position,
 	}
 }
 
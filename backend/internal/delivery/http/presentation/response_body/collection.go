@@ -0,0 +1,61 @@
+package response_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"time"
+)
+
+type Collection struct {
+	Id          int     `json:"id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	OwnerId     int     `json:"owner_id"`
+	OwnerName   string  `json:"owner_name"`
+	Owned       bool    `json:"owned"`
+	Visibility  string  `json:"visibility"`
+
+	RecipesCount int  `json:"recipes_count"`
+	Followers    int  `json:"followers"`
+	IsFollowed   bool `json:"followed"`
+	IsSaved      bool `json:"saved"`
+
+	CreationTimestamp time.Time `json:"creation_timestamp"`
+	UpdateTimestamp   time.Time `json:"update_timestamp"`
+
+	Recipes *[]RecipeInfo `json:"recipes,omitempty"`
+}
+
+func NewCollectionInfo(collection entity.Collection) Collection {
+	return Collection{
+		Id:          collection.Id,
+		Name:        collection.Name,
+		Description: collection.Description,
+		OwnerId:     collection.OwnerId,
+		OwnerName:   collection.OwnerName,
+		Owned:       collection.Owned,
+		Visibility:  collection.Visibility,
+
+		RecipesCount: collection.RecipesCount,
+		Followers:    collection.Followers,
+		IsFollowed:   collection.IsFollowed,
+		IsSaved:      collection.IsSaved,
+
+		CreationTimestamp: collection.CreationTimestamp.UTC(),
+		UpdateTimestamp:   collection.UpdateTimestamp.UTC(),
+	}
+}
+
+func NewCollection(collection entity.Collection) Collection {
+	response := NewCollectionInfo(collection)
+	recipes := NewRecipes(collection.Recipes)
+	response.Recipes = &recipes
+	return response
+}
+
+func NewCollections(entities []entity.Collection) []Collection {
+	collections := make([]Collection, len(entities))
+	for i, collection := range entities {
+		collections[i] = NewCollectionInfo(collection)
+	}
+	return collections
+}
@@ -9,9 +9,15 @@ const (
 	errTypeInvalidAccessToken  = "INVALID_ACCESS_TOKEN"
 	errTypeInvalidRefreshToken = "INVALID_REFRESH_TOKEN"
 
-	errTypeInvalidBody = "INVALID_BODY"
-	errTypeBigFile     = "BIG_FILE"
-	errTypeNotFound    = "NOT_FOUND"
+	errTypeInvalidBody   = "INVALID_BODY"
+	errTypeBigFile       = "BIG_FILE"
+	errTypeNotFound      = "NOT_FOUND"
+	errTypeQuotaExceeded = "QUOTA_EXCEEDED"
+
+	errTypePremiumRequired = "PREMIUM_REQUIRED"
+	errTypeInvalidReceipt  = "INVALID_RECEIPT"
+
+	errTypeNotEnoughBroccoins = "NOT_ENOUGH_BROCCOINS"
 
 	errTypeUnableSendMail        = "UNABLE_SEND_MAIL"
 	errTypeInvalidCredentials    = "INVALID_CREDENTIALS"
@@ -36,22 +42,38 @@ type Error struct {
 func NewError(err error) Error {
 	errType := errTypeUnknown
 	switch err {
-	case failure.AccessDenied, failure.NotOwner:
+	case failure.AccessDenied, failure.NotOwner, failure.SubscriptionOwnedByAnotherUser:
 		errType = errTypeAccessDenied
 	case failure.EmptyAuthHeader, failure.InvalidAuthHeader, failure.EmptyToken, failure.InvalidToken,
 		failure.SessionExpired:
 		errType = errTypeInvalidAccessToken
 	case failure.UserNotFound, failure.RecipeNotFound, failure.CategoryNotFound, failure.ActivationLinkNotFound,
-		failure.NoKey, failure.ShoppingListNotFound, failure.UnableGetRandomRecipe:
+		failure.NoKey, failure.ShoppingListNotFound, failure.UnableGetRandomRecipe, failure.FoodNotFound,
+		failure.CollectionNotFound, failure.RecipeLinkNotFound, failure.KeyRequestNotFound,
+		failure.PictureNotFound, failure.UploadNotFound, failure.SubscriptionNotFound:
 		errType = errTypeNotFound
 	case failure.SessionNotFound:
 		errType = errTypeInvalidRefreshToken
-	case failure.InvalidBody, failure.UnsupportedFileType, failure.EmptyRecipeName, failure.EmptyIngredients, failure.EmptyCooking,
+	case failure.InvalidBody, failure.UnsupportedFileType, failure.UnsupportedImage, failure.EmptyRecipeName, failure.EmptyIngredients, failure.EmptyCooking,
 		failure.InvalidUserId, failure.TooLongRecipeName, failure.TooLongRecipeDescription, failure.TooLongIngredientItemText,
-		failure.InvalidIngredientItemType, failure.InvalidCookingItemType, failure.InvalidEncryptionType:
+		failure.InvalidIngredientItemType, failure.InvalidCookingItemType, failure.InvalidEncryptionType,
+		failure.UnableFollowYourself, failure.InvalidTag, failure.TooManyTags, failure.TagNotFound,
+		failure.InvalidAllergen, failure.TooManyCollectionRecipes, failure.RecipeUnavailableForCollection,
+		failure.UnableFollowOwnCollection, failure.RecipeNotEncrypted, failure.KeyRequestAlreadyExists,
+		failure.KeyRequestAlreadyHandled, failure.TooLongCaption, failure.InvalidPictureOrder, failure.InvalidCookingPicture,
+		failure.TooManyCollections, failure.UnsupportedSubscriptionProvider, failure.InvalidReferralCode,
+		failure.CategoryCycle, failure.InvalidCategoriesOrder:
 		errType = errTypeInvalidBody
-	case failure.InvalidFileSize:
+	case failure.InvalidFileSize, failure.TooLargeImage:
 		errType = errTypeBigFile
+	case failure.QuotaExceeded:
+		errType = errTypeQuotaExceeded
+	case failure.PremiumRequired:
+		errType = errTypePremiumRequired
+	case failure.InvalidReceipt:
+		errType = errTypeInvalidReceipt
+	case failure.NotEnoughBroccoins:
+		errType = errTypeNotEnoughBroccoins
 	case failure.UnableSendEmail:
 		errType = errTypeUnableSendMail
 	case failure.InvalidCredentials:
@@ -66,7 +88,7 @@ func NewError(err error) Error {
 		errType = errTypeUserBlocked
 	case failure.UnableImportFirebaseProfile:
 		errType = errFirebaseProfileImport
-	case failure.InvalidRecipe:
+	case failure.InvalidRecipe, failure.UnableCalculateNutrition:
 		errType = errInvalidRecipe
 	case failure.RecipeNotInRecipeBook:
 		errType = errNotInRecipeBook
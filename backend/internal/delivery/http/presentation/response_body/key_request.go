@@ -0,0 +1,55 @@
+package response_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"time"
+)
+
+type RecipeKeyRequest struct {
+	Id                int       `json:"id"`
+	RecipeId          int       `json:"recipe_id"`
+	RecipeName        string    `json:"recipe_name"`
+	UserId            int       `json:"user_id"`
+	Username          *string   `json:"username,omitempty"`
+	UserKey           string    `json:"encrypted_public_key"`
+	RecipeKey         *string   `json:"encrypted_private_key,omitempty"`
+	Status            string    `json:"status"`
+	CreationTimestamp time.Time `json:"creation_timestamp"`
+	UpdateTimestamp   time.Time `json:"update_timestamp"`
+}
+
+func NewRecipeKeyRequest(request entity.RecipeKeyRequest) RecipeKeyRequest {
+	return RecipeKeyRequest{
+		Id:                request.Id,
+		RecipeId:          request.RecipeId,
+		RecipeName:        request.RecipeName,
+		UserId:            request.UserId,
+		Username:          request.Username,
+		UserKey:           request.UserKey,
+		RecipeKey:         request.RecipeKey,
+		Status:            request.Status,
+		CreationTimestamp: request.CreationTimestamp.UTC(),
+		UpdateTimestamp:   request.UpdateTimestamp.UTC(),
+	}
+}
+
+// RecipeUserKey contains either link to key set by owner manually, or key from approved key request
+type RecipeUserKey struct {
+	PrivateKey         *string `json:"encrypted_private_key,omitempty"`
+	ApprovedPrivateKey *string `json:"approved_private_key,omitempty"`
+}
+
+func NewRecipeUserKey(key entity.RecipeUserKey) RecipeUserKey {
+	return RecipeUserKey{
+		PrivateKey:         key.Link,
+		ApprovedPrivateKey: key.ApprovedKey,
+	}
+}
+
+func NewRecipeKeyRequests(entities []entity.RecipeKeyRequest) []RecipeKeyRequest {
+	requests := make([]RecipeKeyRequest, len(entities))
+	for i, request := range entities {
+		requests[i] = NewRecipeKeyRequest(request)
+	}
+	return requests
+}
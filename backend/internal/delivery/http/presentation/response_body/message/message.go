@@ -7,23 +7,51 @@ const (
 
 	PasswordChanged = "password successfully changed"
 	UsernameChanged = "username successfully changed"
+	BioChanged      = "bio successfully changed"
+	AllergensSet    = "excluded allergens successfully set"
 	AvatarDeleted   = "avatar has been deleted"
 	KeySet          = "encrypted key set"
 	KeyDeleted      = "encrypted key deleted"
 
+	KeyRequested        = "key has been requested"
+	KeyRequestApproved  = "key request has been approved"
+	KeyRequestRejected  = "key request has been rejected"
+	KeyRequestCancelled = "key request has been cancelled"
+
+	UserFollowed   = "user has been followed"
+	UserUnfollowed = "user has been unfollowed"
+
 	RecipeCreated               = "recipe has been created"
 	RecipeAddedToRecipeBook     = "recipe has been added to recipe book"
 	RecipeUpdated               = "recipe has been updated"
 	RecipeDeleted               = "recipe has been deleted"
 	RecipeRemovedFromRecipeBook = "recipe has been removed from recipe book"
 	CategoriesUpdated           = "categories has been updated"
+	TagsUpdated                 = "tags has been updated"
 	FavouriteStatusUpdated      = "favourite status has been updated"
 	RecipeLikeSet               = "recipe like status has been set"
+	RecipeCooked                = "recipe cooking has been recorded"
+	RecipePictureUpdated        = "picture has been updated"
 	RecipePictureDeleted        = "picture has been deleted"
+	RecipePicturesOrderUpdated  = "pictures order has been updated"
+	RecipeLinkDeleted           = "share link has been revoked"
 
-	CategoryCreated = "category has been created"
-	CategoryUpdated = "category has been updated"
-	CategoryDeleted = "category has been deleted"
+	CategoryCreated        = "category has been created"
+	CategoryUpdated        = "category has been updated"
+	CategoryDeleted        = "category has been deleted"
+	CategoriesOrderUpdated = "categories order has been updated"
+
+	CollectionCreated        = "collection has been created"
+	CollectionUpdated        = "collection has been updated"
+	CollectionDeleted        = "collection has been deleted"
+	CollectionRecipesUpdated = "collection recipes has been updated"
+	CollectionFollowed       = "collection has been followed"
+	CollectionUnfollowed     = "collection has been unfollowed"
+	CollectionSaved          = "collection has been saved"
+	CollectionRemoved        = "collection has been removed from saved"
+	CategoryConverted        = "category has been converted to collection"
 
 	ShoppingListUpdated = "shopping list has been updated"
+
+	SubscriptionNotificationHandled = "notification has been handled"
 )
@@ -0,0 +1,41 @@
+package response_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/common_body"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+)
+
+type IngredientNutrition struct {
+	Text       string   `json:"text"`
+	Food       *string  `json:"food,omitempty"`
+	Grams      *float64 `json:"grams,omitempty"`
+	Calories   *float64 `json:"calories,omitempty"`
+	Confidence float64  `json:"confidence"`
+}
+
+type Nutrition struct {
+	Calories       *int16                      `json:"calories,omitempty"`
+	Macronutrients *common_body.Macronutrients `json:"macronutrients,omitempty"`
+	Confidence     float64                     `json:"confidence"`
+	Ingredients    []IngredientNutrition       `json:"ingredients"`
+}
+
+func NewNutrition(nutrition entity.Nutrition) Nutrition {
+	ingredients := make([]IngredientNutrition, len(nutrition.Ingredients))
+	for i, ingredient := range nutrition.Ingredients {
+		ingredients[i] = IngredientNutrition{
+			Text:       ingredient.Text,
+			Food:       ingredient.Food,
+			Grams:      ingredient.Grams,
+			Calories:   ingredient.Calories,
+			Confidence: ingredient.Confidence,
+		}
+	}
+
+	return Nutrition{
+		Calories:       nutrition.Calories,
+		Macronutrients: common_body.NewMacronutrients(nutrition.Macronutrients),
+		Confidence:     nutrition.Confidence,
+		Ingredients:    ingredients,
+	}
+}
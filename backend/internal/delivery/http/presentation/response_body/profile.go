@@ -29,17 +29,6 @@ func NewMinimalProfileInfo(profile entity.ProfileInfo) MinimalProfileInfo {
 	}
 }
 
-func NewMinimalProfileInfoByProfile(profile entity.Profile) MinimalProfileInfo {
-	return MinimalProfileInfo{
-		Id:                profile.Id,
-		Username:          profile.Username,
-		CreationTimestamp: profile.CreationTimestamp.UTC(),
-		Avatar:            profile.Avatar,
-		IsPremium:         profile.PremiumEndDate != nil && profile.PremiumEndDate.Unix() > time.Now().Unix(),
-		Broccoins:         profile.Broccoins,
-	}
-}
-
 func NewUsersList(profiles []entity.ProfileInfo) []MinimalProfileInfo {
 	users := make([]MinimalProfileInfo, len(profiles))
 	for i, profile := range profiles {
@@ -48,15 +37,91 @@ func NewUsersList(profiles []entity.ProfileInfo) []MinimalProfileInfo {
 	return users
 }
 
-type DetailedProfileInfo struct {
+// PublicUserInfo is user info shown to other users, so it doesn't contain balance
+type PublicUserInfo struct {
 	Id                int       `json:"id"`
-	Email             string    `json:"email"`
 	Username          *string   `json:"username,omitempty"`
 	CreationTimestamp time.Time `json:"creation_timestamp"`
 	Avatar            *string   `json:"avatar,omitempty"`
 	IsPremium         bool      `json:"premium,omitempty"`
-	Broccoins         int       `json:"broccoins"`
-	IsBlocked         bool      `json:"is_blocked,omitempty"`
+}
+
+func NewPublicUsersList(profiles []entity.ProfileInfo) []PublicUserInfo {
+	users := make([]PublicUserInfo, len(profiles))
+	for i, profile := range profiles {
+		users[i] = PublicUserInfo{
+			Id:                profile.Id,
+			Username:          profile.Username,
+			CreationTimestamp: profile.CreationTimestamp.UTC(),
+			Avatar:            profile.Avatar,
+			IsPremium:         profile.PremiumEndDate != nil && profile.PremiumEndDate.After(time.Now()),
+		}
+	}
+	return users
+}
+
+type PublicProfileInfo struct {
+	Id                int            `json:"id"`
+	Username          *string        `json:"username,omitempty"`
+	Bio               *string        `json:"bio,omitempty"`
+	CreationTimestamp time.Time      `json:"creation_timestamp"`
+	Avatar            *string        `json:"avatar,omitempty"`
+	AvatarThumbnails  map[int]string `json:"avatar_thumbnails,omitempty"`
+	RecipesCount      int            `json:"recipes_count"`
+	LikesCount        int            `json:"likes_count"`
+	FollowersCount    int            `json:"followers_count"`
+	FollowingCount    int            `json:"following_count"`
+	IsFollowed        bool           `json:"followed"`
+}
+
+func NewPublicProfileInfo(profile entity.PublicProfile) PublicProfileInfo {
+	return PublicProfileInfo{
+		Id:                profile.Id,
+		Username:          profile.Username,
+		Bio:               profile.Bio,
+		CreationTimestamp: profile.CreationTimestamp.UTC(),
+		Avatar:            profile.Avatar,
+		AvatarThumbnails:  profile.AvatarThumbnails,
+		RecipesCount:      profile.RecipesCount,
+		LikesCount:        profile.LikesCount,
+		FollowersCount:    profile.FollowersCount,
+		FollowingCount:    profile.FollowingCount,
+		IsFollowed:        profile.IsFollowed,
+	}
+}
+
+type DetailedProfileInfo struct {
+	Id                int            `json:"id"`
+	Email             string         `json:"email"`
+	Username          *string        `json:"username,omitempty"`
+	Bio               *string        `json:"bio,omitempty"`
+	CreationTimestamp time.Time      `json:"creation_timestamp"`
+	Avatar            *string        `json:"avatar,omitempty"`
+	AvatarThumbnails  map[int]string `json:"avatar_thumbnails,omitempty"`
+	IsPremium         bool           `json:"premium,omitempty"`
+	Broccoins         int            `json:"broccoins"`
+	IsBlocked         bool           `json:"is_blocked,omitempty"`
+	ExcludedAllergens []string       `json:"excluded_allergens"`
+	StorageUsage      *StorageUsage  `json:"storage_usage,omitempty"`
+}
+
+type StorageUsage struct {
+	UsedBytes int64 `json:"used_bytes"`
+	UsedFiles int   `json:"used_files"`
+	MaxBytes  int64 `json:"max_bytes"`
+	MaxFiles  int   `json:"max_files"`
+}
+
+func NewStorageUsage(usage *entity.StorageUsage) *StorageUsage {
+	if usage == nil {
+		return nil
+	}
+	return &StorageUsage{
+		UsedBytes: usage.Bytes,
+		UsedFiles: usage.Files,
+		MaxBytes:  usage.MaxBytes,
+		MaxFiles:  usage.MaxFiles,
+	}
 }
 
 func NewDetailedProfileInfo(profile entity.Profile) DetailedProfileInfo {
@@ -64,10 +129,14 @@ func NewDetailedProfileInfo(profile entity.Profile) DetailedProfileInfo {
 		Id:                profile.Id,
 		Email:             profile.Email,
 		Username:          profile.Username,
+		Bio:               profile.Bio,
 		CreationTimestamp: profile.CreationTimestamp.UTC(),
 		Avatar:            profile.Avatar,
+		AvatarThumbnails:  profile.AvatarThumbnails,
 		IsPremium:         profile.PremiumEndDate != nil && profile.PremiumEndDate.Unix() > time.Now().Unix(),
 		Broccoins:         profile.Broccoins,
 		IsBlocked:         profile.IsBlocked,
+		ExcludedAllergens: profile.ExcludedAllergens,
+		StorageUsage:      NewStorageUsage(profile.StorageUsage),
 	}
 }
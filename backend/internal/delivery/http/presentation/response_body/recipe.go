@@ -19,10 +19,13 @@ type Recipe struct {
 	Description *string `json:"description,omitempty"`
 	Preview     *string `json:"preview,omitempty"`
 
+	PreviewThumbnails map[int]string `json:"preview_thumbnails,omitempty"`
+
 	CreationTimestamp time.Time `json:"creation_timestamp"`
 	UpdateTimestamp   time.Time `json:"update_timestamp"`
 
 	Categories  *[]Category `json:"categories,omitempty"`
+	Tags        *[]Tag      `json:"tags,omitempty"`
 	IsFavourite bool        `json:"favourite"`
 	IsLiked     bool        `json:"liked"`
 
@@ -34,6 +37,9 @@ type Recipe struct {
 
 	Ingredients []common_body.IngredientItem `json:"ingredients"`
 	Cooking     []common_body.CookingItem    `json:"cooking"`
+
+	Allergens       *[]string `json:"allergens,omitempty"`
+	AllergensStatus string    `json:"allergens_status"`
 }
 
 func NewRecipe(recipe entity.UserRecipe) Recipe {
@@ -60,10 +66,13 @@ func NewRecipe(recipe entity.UserRecipe) Recipe {
 		Description: recipe.Description,
 		Preview:     recipe.Preview,
 
+		PreviewThumbnails: recipe.PreviewThumbnails,
+
 		CreationTimestamp: recipe.CreationTimestamp.UTC(),
 		UpdateTimestamp:   recipe.UpdateTimestamp.UTC(),
 
 		Categories:  getRecipeCategories(&recipe.Categories),
+		Tags:        getRecipeTags(&recipe.Tags),
 		IsFavourite: recipe.IsFavourite,
 		IsLiked:     recipe.IsLiked,
 
@@ -75,6 +84,9 @@ func NewRecipe(recipe entity.UserRecipe) Recipe {
 
 		Ingredients: ingredients,
 		Cooking:     cooking,
+
+		Allergens:       recipe.Allergens,
+		AllergensStatus: getAllergensStatus(recipe.Allergens),
 	}
 }
 
@@ -90,10 +102,13 @@ type RecipeInfo struct {
 	Language    string  `json:"language"`
 	Preview     *string `json:"preview,omitempty"`
 
+	PreviewThumbnails map[int]string `json:"preview_thumbnails,omitempty"`
+
 	CreationTimestamp time.Time `json:"creation_timestamp"`
 	UpdateTimestamp   time.Time `json:"update_timestamp"`
 
 	Categories  *[]Category `json:"categories,omitempty"`
+	Tags        *[]Tag      `json:"tags,omitempty"`
 	IsFavourite bool        `json:"favourite"`
 	IsLiked     bool        `json:"liked"`
 
@@ -101,6 +116,9 @@ type RecipeInfo struct {
 	Time     *int16 `json:"time,omitempty"`
 
 	Calories *int16 `json:"calories,omitempty"`
+
+	Allergens       *[]string `json:"allergens,omitempty"`
+	AllergensStatus string    `json:"allergens_status"`
 }
 
 func NewRecipeInfo(recipe entity.RecipeInfo) RecipeInfo {
@@ -116,10 +134,13 @@ func NewRecipeInfo(recipe entity.RecipeInfo) RecipeInfo {
 		Language:    recipe.Language,
 		Preview:     recipe.Preview,
 
+		PreviewThumbnails: recipe.PreviewThumbnails,
+
 		CreationTimestamp: recipe.CreationTimestamp.UTC(),
 		UpdateTimestamp:   recipe.UpdateTimestamp.UTC(),
 
 		Categories:  getRecipeCategories(&recipe.Categories),
+		Tags:        getRecipeTags(&recipe.Tags),
 		IsFavourite: recipe.IsFavourite,
 		IsLiked:     recipe.IsLiked,
 
@@ -127,6 +148,9 @@ func NewRecipeInfo(recipe entity.RecipeInfo) RecipeInfo {
 		Time:     recipe.Time,
 
 		Calories: recipe.Calories,
+
+		Allergens:       recipe.Allergens,
+		AllergensStatus: getAllergensStatus(recipe.Allergens),
 	}
 }
 
@@ -142,6 +166,25 @@ func getRecipeCategories(categories *[]entity.Category) *[]Category {
 	return categoriesPointer
 }
 
+func getAllergensStatus(allergens *[]string) string {
+	if allergens == nil {
+		return entity.AllergensStatusUnknown
+	}
+	if len(*allergens) > 0 {
+		return entity.AllergensStatusContains
+	}
+	return entity.AllergensStatusSafe
+}
+
+func getRecipeTags(tags *[]entity.Tag) *[]Tag {
+	var tagsPointer *[]Tag = nil
+	if tags != nil && len(*tags) > 0 {
+		responseTags := NewTags(*tags)
+		tagsPointer = &responseTags
+	}
+	return tagsPointer
+}
+
 func NewRecipes(entities []entity.RecipeInfo) []RecipeInfo {
 	recipes := make([]RecipeInfo, len(entities))
 	for i, recipe := range entities {
@@ -0,0 +1,38 @@
+package response_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"time"
+)
+
+type RecipeLink struct {
+	Id                int        `json:"id"`
+	Token             string     `json:"token"`
+	RecipeId          int        `json:"recipe_id"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	CreationTimestamp time.Time  `json:"creation_timestamp"`
+}
+
+func NewRecipeLink(link entity.RecipeLink) RecipeLink {
+	var expiresAt *time.Time = nil
+	if link.ExpiresAt != nil {
+		utcExpiresAt := link.ExpiresAt.UTC()
+		expiresAt = &utcExpiresAt
+	}
+
+	return RecipeLink{
+		Id:                link.Id,
+		Token:             link.Token,
+		RecipeId:          link.RecipeId,
+		ExpiresAt:         expiresAt,
+		CreationTimestamp: link.CreationTimestamp.UTC(),
+	}
+}
+
+func NewRecipeLinks(entities []entity.RecipeLink) []RecipeLink {
+	links := make([]RecipeLink, len(entities))
+	for i, link := range entities {
+		links[i] = NewRecipeLink(link)
+	}
+	return links
+}
@@ -0,0 +1,36 @@
+package response_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"time"
+)
+
+type RecipePicture struct {
+	Id                int            `json:"id"`
+	Link              string         `json:"link"`
+	Thumbnails        map[int]string `json:"thumbnails,omitempty"`
+	Caption           *string        `json:"caption,omitempty"`
+	Position          int            `json:"position"`
+	IsPreview         bool           `json:"is_preview"`
+	CreationTimestamp time.Time      `json:"creation_timestamp"`
+}
+
+func NewRecipePicture(picture entity.RecipePicture) RecipePicture {
+	return RecipePicture{
+		Id:                picture.Id,
+		Link:              picture.Link,
+		Thumbnails:        picture.Thumbnails,
+		Caption:           picture.Caption,
+		Position:          picture.Position,
+		IsPreview:         picture.IsPreview,
+		CreationTimestamp: picture.CreationTimestamp.UTC(),
+	}
+}
+
+func NewRecipePictures(entities []entity.RecipePicture) []RecipePicture {
+	pictures := make([]RecipePicture, len(entities))
+	for i, picture := range entities {
+		pictures[i] = NewRecipePicture(picture)
+	}
+	return pictures
+}
@@ -0,0 +1,39 @@
+package response_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"time"
+)
+
+type Referral struct {
+	InviteeId           int        `json:"invitee_id"`
+	InviteeName         *string    `json:"invitee_name,omitempty"`
+	Status              string     `json:"status"`
+	CreationTimestamp   time.Time  `json:"creation_timestamp"`
+	ActivationTimestamp *time.Time `json:"activation_timestamp,omitempty"`
+}
+
+type Referrals struct {
+	ReferralCode string     `json:"referral_code"`
+	Referrals    []Referral `json:"referrals"`
+}
+
+func NewReferrals(referrals entity.Referrals) Referrals {
+	response := Referrals{
+		ReferralCode: referrals.Code,
+		Referrals:    make([]Referral, len(referrals.Referrals)),
+	}
+	for i, referral := range referrals.Referrals {
+		response.Referrals[i] = Referral{
+			InviteeId:         referral.InviteeId,
+			InviteeName:       referral.InviteeName,
+			Status:            referral.Status,
+			CreationTimestamp: referral.CreationTimestamp.UTC(),
+		}
+		if referral.ActivationTimestamp != nil {
+			activationTimestamp := referral.ActivationTimestamp.UTC()
+			response.Referrals[i].ActivationTimestamp = &activationTimestamp
+		}
+	}
+	return response
+}
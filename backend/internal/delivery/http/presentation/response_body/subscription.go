@@ -0,0 +1,63 @@
+package response_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"time"
+)
+
+type Entitlements struct {
+	IsPremium      bool       `json:"is_premium"`
+	PremiumEndDate *time.Time `json:"premium_end_date,omitempty"`
+	Encryption     bool       `json:"encryption"`
+	MaxBytes       int64      `json:"max_bytes"`
+	MaxFiles       int        `json:"max_files"`
+	MaxCollections int        `json:"max_collections"`
+}
+
+func NewEntitlements(entitlements entity.Entitlements) Entitlements {
+	var premiumEndDate *time.Time
+	if entitlements.PremiumEndDate != nil {
+		date := entitlements.PremiumEndDate.UTC()
+		premiumEndDate = &date
+	}
+	return Entitlements{
+		IsPremium:      entitlements.IsPremium,
+		PremiumEndDate: premiumEndDate,
+		Encryption:     entitlements.Encryption,
+		MaxBytes:       entitlements.StorageQuota.MaxBytes,
+		MaxFiles:       entitlements.StorageQuota.MaxFiles,
+		MaxCollections: entitlements.MaxCollections,
+	}
+}
+
+type Subscription struct {
+	Id                int       `json:"id"`
+	Provider          string    `json:"provider"`
+	ProductId         string    `json:"product_id"`
+	Status            string    `json:"status"`
+	ExpiresAt         time.Time `json:"expires_at"`
+	AutoRenew         bool      `json:"auto_renew"`
+	CreationTimestamp time.Time `json:"creation_timestamp"`
+	UpdateTimestamp   time.Time `json:"update_timestamp"`
+}
+
+func NewSubscription(subscription entity.Subscription) Subscription {
+	return Subscription{
+		Id:                subscription.Id,
+		Provider:          subscription.Provider,
+		ProductId:         subscription.ProductId,
+		Status:            subscription.Status,
+		ExpiresAt:         subscription.ExpiresAt.UTC(),
+		AutoRenew:         subscription.AutoRenew,
+		CreationTimestamp: subscription.CreationTimestamp.UTC(),
+		UpdateTimestamp:   subscription.UpdateTimestamp.UTC(),
+	}
+}
+
+func NewSubscriptions(entities []entity.Subscription) []Subscription {
+	subscriptions := make([]Subscription, len(entities))
+	for i, subscription := range entities {
+		subscriptions[i] = NewSubscription(subscription)
+	}
+	return subscriptions
+}
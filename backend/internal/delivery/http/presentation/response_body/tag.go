@@ -0,0 +1,53 @@
+package response_body
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Tag struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+}
+
+func NewTag(tag entity.Tag) Tag {
+	return Tag{
+		Id:   tag.Id,
+		Type: tag.Type,
+	}
+}
+
+func NewTags(entities []entity.Tag) []Tag {
+	tags := make([]Tag, len(entities))
+	for i, tag := range entities {
+		tags[i] = NewTag(tag)
+	}
+	return tags
+}
+
+type TagFacet struct {
+	Id    string `json:"id"`
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+func NewTagFacets(entities []entity.TagFacet) []TagFacet {
+	facets := make([]TagFacet, len(entities))
+	for i, facet := range entities {
+		facets[i] = TagFacet{
+			Id:    facet.Id,
+			Type:  facet.Type,
+			Count: facet.Count,
+		}
+	}
+	return facets
+}
+
+type RecipesWithFacets struct {
+	Recipes   []RecipeInfo `json:"recipes"`
+	TagFacets []TagFacet   `json:"tag_facets"`
+}
+
+func NewRecipesWithFacets(recipes []entity.RecipeInfo, facets []entity.TagFacet) RecipesWithFacets {
+	return RecipesWithFacets{
+		Recipes:   NewRecipes(recipes),
+		TagFacets: NewTagFacets(facets),
+	}
+}
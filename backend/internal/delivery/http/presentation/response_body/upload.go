@@ -0,0 +1,22 @@
+package response_body
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"time"
+)
+
+type UploadLink struct {
+	UploadId  string            `json:"upload_id"`
+	Url       string            `json:"url"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+func NewUploadLink(link entity.UploadLink) UploadLink {
+	return UploadLink{
+		UploadId:  link.UploadId,
+		Url:       link.Url,
+		Headers:   link.Headers,
+		ExpiresAt: link.ExpiresAt.UTC(),
+	}
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mephistolie/chefbook-server/internal/app/dependencies/service"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware/response"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body"
+)
+
+type AchievementHandler struct {
+	middleware middleware.AuthMiddleware
+	service    service.Achievement
+}
+
+func NewAchievementHandler(middleware middleware.AuthMiddleware, service service.Achievement) *AchievementHandler {
+	return &AchievementHandler{
+		middleware: middleware,
+		service:    service,
+	}
+}
+
+// GetAchievements Swagger Documentation
+// @Summary Get Achievements
+// @Security ApiKeyAuth
+// @Tags profile
+// @Description Get all achievements with user progress and cooking streak. Reward is broccoins credited on unlock.
+// @Description Codes: 'first_public_recipe', 'first_recipe_saved', 'likes_received_100', 'recipes_cooked_10', 'cooking_streak_7'
+// @Accept json
+// @Produce json
+// @Success 200 {object} response_body.Achievements
+// @Failure 400 {object} response_body.Error
+// @Router /v1/profile/achievements [get]
+func (r *AchievementHandler) GetAchievements(c *gin.Context) {
+	userId, err := r.middleware.GetUserId(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	achievements, err := r.service.GetAchievements(userId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewAchievements(achievements))
+}
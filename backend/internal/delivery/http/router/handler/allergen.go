@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mephistolie/chefbook-server/internal/app/dependencies/service"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware/response"
+)
+
+type AllergenHandler struct {
+	service service.Allergen
+}
+
+func NewAllergenHandler(service service.Allergen) *AllergenHandler {
+	return &AllergenHandler{
+		service: service,
+	}
+}
+
+// GetAllergens Swagger Documentation
+// @Summary Get Allergens
+// @Security ApiKeyAuth
+// @Tags allergens
+// @Description Get allergens detected in recipe ingredients
+// @Accept json
+// @Produce json
+// @Success 200 {object} []string
+// @Failure 400 {object} response_body.Error
+// @Router /v1/allergens [get]
+func (r *AllergenHandler) GetAllergens(c *gin.Context) {
+	response.Success(c, r.service.GetSupportedAllergens())
+}
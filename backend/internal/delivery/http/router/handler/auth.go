@@ -31,12 +31,12 @@ func NewAuthHandler(service service.Auth) *AuthHandler {
 // @Description Create new profile
 // @Accept json
 // @Produce json
-// @Param input body request_body.Credentials true "Credentials"
+// @Param input body request_body.SignUp true "Credentials with optional referral code"
 // @Success 200 {object} response_body.Id
 // @Failure 400 {object} response_body.Error
 // @Router /v1/auth/sign-up [post]
 func (h *AuthHandler) SignUp(c *gin.Context) {
-	var body request_body.Credentials
+	var body request_body.SignUp
 	if err := c.BindJSON(&body); err != nil {
 		response.Failure(c, failure.InvalidBody)
 		return
@@ -46,7 +46,7 @@ func (h *AuthHandler) SignUp(c *gin.Context) {
 		return
 	}
 
-	id, err := h.service.SignUp(body.Entity())
+	id, err := h.service.SignUp(body.Entity(), body.ReferralCode)
 	if err != nil {
 		response.Failure(c, err)
 		return
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mephistolie/chefbook-server/internal/app/dependencies/service"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware/response"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/request_body"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"strconv"
+)
+
+type BroccoinHandler struct {
+	middleware middleware.AuthMiddleware
+	service    service.Broccoin
+}
+
+func NewBroccoinHandler(middleware middleware.AuthMiddleware, service service.Broccoin) *BroccoinHandler {
+	return &BroccoinHandler{
+		middleware: middleware,
+		service:    service,
+	}
+}
+
+// GetBroccoinTransactions Swagger Documentation
+// @Summary Get Broccoin Transactions
+// @Security ApiKeyAuth
+// @Tags profile
+// @Description Get history of broccoins credits and debits, newest first. Transaction balance is user balance right after it.
+// @Description Reasons: 'opening_balance', 'migration_bonus', 'like_received', 'daily_login', 'premium_purchase', 'referral'
+// @Accept json
+// @Produce json
+// @Param page query string false "Page of the result"
+// @Param page_size query string false "Page size of the result. Maximum is 50"
+// @Success 200 {object} []response_body.BroccoinTransaction
+// @Failure 400 {object} response_body.Error
+// @Router /v1/profile/broccoins/transactions [get]
+func (r *BroccoinHandler) GetBroccoinTransactions(c *gin.Context) {
+	userId, err := r.middleware.GetUserId(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	var query request_body.BroccoinTransactionsQuery
+	if page, err := strconv.Atoi(c.Query(queryPage)); err == nil {
+		query.Page = page
+	}
+	if pageSize, err := strconv.Atoi(c.Query(queryPageSize)); err == nil {
+		query.PageSize = pageSize
+	}
+	if err := query.Validate(); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	transactions, err := r.service.GetTransactions(userId, query.Entity())
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewBroccoinTransactions(transactions))
+}
+
+// SpendBroccoins Swagger Documentation
+// @Summary Spend Broccoins
+// @Security ApiKeyAuth
+// @Tags profile
+// @Description Exchange broccoins for premium days. Premium is extended from its end date or from now if it's expired
+// @Accept json
+// @Produce json
+// @Param input body request_body.BroccoinsSpendInput true "Premium days"
+// @Success 200 {object} response_body.PremiumPurchase
+// @Failure 400 {object} response_body.Error
+// @Router /v1/profile/broccoins/spend [post]
+func (r *BroccoinHandler) SpendBroccoins(c *gin.Context) {
+	userId, err := r.middleware.GetUserId(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	var body request_body.BroccoinsSpendInput
+	if err := c.BindJSON(&body); err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+
+	purchase, err := r.service.SpendOnPremium(userId, body.PremiumDays)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewPremiumPurchase(purchase))
+}
@@ -32,7 +32,7 @@ func NewCategoryHandler(middleware middleware.AuthMiddleware, service service.Ca
 // @Summary Get Categories
 // @Security ApiKeyAuth
 // @Tags categories
-// @Description Get user categories
+// @Description Get user categories ordered by position. Subcategories have parent ID, positions are set among siblings
 // @Accept json
 // @Produce json
 // @Success 200 {object} []response_body.Category
@@ -77,6 +77,10 @@ func (r *CategoriesHandler) CreateCategory(c *gin.Context) {
 		response.Failure(c, failure.InvalidBody)
 		return
 	}
+	if err := body.Validate(); err != nil {
+		response.Failure(c, err)
+		return
+	}
 
 	categoryId, err := r.service.CreateCategory(body.Entity(), userId)
 	if err != nil {
@@ -124,7 +128,7 @@ func (r *CategoriesHandler) GetCategory(c *gin.Context) {
 // @Summary Update Category
 // @Security ApiKeyAuth
 // @Tags categories
-// @Description Update user category
+// @Description Update user category. Category without parent ID is moved to root. Moved category is placed last
 // @Accept json
 // @Produce json
 // @Param category_id path int true "Category ID"
@@ -150,6 +154,10 @@ func (r *CategoriesHandler) UpdateCategory(c *gin.Context) {
 		response.Failure(c, failure.InvalidBody)
 		return
 	}
+	if err := body.Validate(); err != nil {
+		response.Failure(c, err)
+		return
+	}
 
 	if err := r.service.UpdateCategory(categoryId, body.Entity(), userId); err != nil {
 		response.Failure(c, err)
@@ -159,11 +167,47 @@ func (r *CategoriesHandler) UpdateCategory(c *gin.Context) {
 	response.Message(c, message.CategoryUpdated)
 }
 
+// SetCategoriesOrder Swagger Documentation
+// @Summary Set Categories Order
+// @Security ApiKeyAuth
+// @Tags categories
+// @Description Move and reorder categories. Body must contain all user categories with their parents in new order
+// @Accept json
+// @Produce json
+// @Param input body request_body.CategoriesOrderInput true "Categories"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/categories/order [put]
+func (r *CategoriesHandler) SetCategoriesOrder(c *gin.Context) {
+	userId, err := r.middleware.GetUserId(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	var body request_body.CategoriesOrderInput
+	if err := c.BindJSON(&body); err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+	if err := body.Validate(); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	if err := r.service.SetCategoriesOrder(body.Entity(), userId); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, message.CategoriesOrderUpdated)
+}
+
 // DeleteCategory Swagger Documentation
 // @Summary Delete Category
 // @Security ApiKeyAuth
 // @Tags categories
-// @Description Delete user category
+// @Description Delete user category. Subcategories are moved to parent of deleted category
 // @Accept json
 // @Produce json
 // @Param category_id path int true "Category ID"
@@ -0,0 +1,428 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mephistolie/chefbook-server/internal/app/dependencies/service"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware/response"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/request_body"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body/message"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"strconv"
+)
+
+const (
+	ParamCollectionId = "collection_id"
+
+	queryFollowed = "followed"
+)
+
+type CollectionHandler struct {
+	middleware middleware.AuthMiddleware
+	service    service.Collection
+}
+
+func NewCollectionHandler(middleware middleware.AuthMiddleware, service service.Collection) *CollectionHandler {
+	return &CollectionHandler{
+		middleware: middleware,
+		service:    service,
+	}
+}
+
+// GetCollections Swagger Documentation
+// @Summary Get Collections
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Browse public collections, or get user own, saved or followed collections including shared ones
+// @Accept json
+// @Produce json
+// @Param author_id query int false "Collections author ID"
+// @Param owned query bool false "Get only those collections that were created by user"
+// @Param saved query bool false "Get only those collections that were saved by user"
+// @Param followed query bool false "Get only those collections that are followed by user"
+// @Param search query string false "Search collections with specified name"
+// @Param sort_by query string false "Sorting. Acceptable values: 'creation_timestamp', 'update_timestamp', 'followers'"
+// @Param page query string false "Page of the result"
+// @Param page_size query string false "Page size of the result. Maximum is 50"
+// @Success 200 {object} []response_body.Collection
+// @Failure 400 {object} response_body.Error
+// @Router /v1/collections [get]
+func (r *CollectionHandler) GetCollections(c *gin.Context) {
+	userId, err := r.middleware.GetUserId(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	query := r.getCollectionsQuery(c)
+	if err := query.Validate(userId); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	collections, err := r.service.GetCollections(query.Entity(), userId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewCollections(collections))
+}
+
+// CreateCollection Swagger Documentation
+// @Summary Create Collection
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Create new collection. Acceptable visibility values: 'public', 'shared'
+// @Accept json
+// @Produce json
+// @Param input body request_body.CollectionInput true "Collection"
+// @Success 200 {object} response_body.Id
+// @Failure 400 {object} response_body.Error
+// @Router /v1/collections [post]
+func (r *CollectionHandler) CreateCollection(c *gin.Context) {
+	userId, err := r.middleware.GetUserId(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	var body request_body.CollectionInput
+	if err := c.BindJSON(&body); err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+	if err := body.Validate(); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	collectionId, err := r.service.CreateCollection(body.Entity(), userId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.NewId(c, collectionId, message.CollectionCreated)
+}
+
+// GetCollection Swagger Documentation
+// @Summary Get Collection
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Get collection with its recipes
+// @Accept json
+// @Produce json
+// @Param collection_id path int true "Collection ID"
+// @Success 200 {object} response_body.Collection
+// @Failure 400 {object} response_body.Error
+// @Router /v1/collections/{collection_id} [get]
+func (r *CollectionHandler) GetCollection(c *gin.Context) {
+	userId, collectionId, err := getUserAndCollectionIds(c, r.middleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	collection, err := r.service.GetCollection(collectionId, userId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewCollection(collection))
+}
+
+// UpdateCollection Swagger Documentation
+// @Summary Update Collection
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Update collection info
+// @Accept json
+// @Produce json
+// @Param collection_id path int true "Collection ID"
+// @Param input body request_body.CollectionInput true "Collection"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/collections/{collection_id} [put]
+func (r *CollectionHandler) UpdateCollection(c *gin.Context) {
+	userId, collectionId, err := getUserAndCollectionIds(c, r.middleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	var body request_body.CollectionInput
+	if err := c.BindJSON(&body); err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+	if err := body.Validate(); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	if err := r.service.UpdateCollection(collectionId, body.Entity(), userId); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, message.CollectionUpdated)
+}
+
+// DeleteCollection Swagger Documentation
+// @Summary Delete Collection
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Delete collection
+// @Accept json
+// @Produce json
+// @Param collection_id path int true "Collection ID"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/collections/{collection_id} [delete]
+func (r *CollectionHandler) DeleteCollection(c *gin.Context) {
+	userId, collectionId, err := getUserAndCollectionIds(c, r.middleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	if err := r.service.DeleteCollection(collectionId, userId); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, message.CollectionDeleted)
+}
+
+// SetCollectionRecipes Swagger Documentation
+// @Summary Set Collection Recipes
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Replace collection recipes keeping passed order. Only public recipes and your own shared recipes are allowed
+// @Accept json
+// @Produce json
+// @Param collection_id path int true "Collection ID"
+// @Param input body request_body.CollectionRecipesInput true "Recipe IDs"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/collections/{collection_id}/recipes [put]
+func (r *CollectionHandler) SetCollectionRecipes(c *gin.Context) {
+	userId, collectionId, err := getUserAndCollectionIds(c, r.middleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	var body request_body.CollectionRecipesInput
+	if err := c.BindJSON(&body); err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+	if err := body.Validate(); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	if err := r.service.SetCollectionRecipes(collectionId, body.Recipes, userId); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, message.CollectionRecipesUpdated)
+}
+
+// FollowCollection Swagger Documentation
+// @Summary Follow Collection
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Follow collection to get its public recipes in feed
+// @Accept json
+// @Produce json
+// @Param collection_id path int true "Collection ID"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/collections/{collection_id}/follow [put]
+func (r *CollectionHandler) FollowCollection(c *gin.Context) {
+	r.setCollectionFollowed(c, true, message.CollectionFollowed)
+}
+
+// UnfollowCollection Swagger Documentation
+// @Summary Unfollow Collection
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Unfollow collection
+// @Accept json
+// @Produce json
+// @Param collection_id path int true "Collection ID"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/collections/{collection_id}/follow [delete]
+func (r *CollectionHandler) UnfollowCollection(c *gin.Context) {
+	r.setCollectionFollowed(c, false, message.CollectionUnfollowed)
+}
+
+// SaveCollection Swagger Documentation
+// @Summary Save Collection
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Save collection to user library
+// @Accept json
+// @Produce json
+// @Param collection_id path int true "Collection ID"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/collections/{collection_id}/save [post]
+func (r *CollectionHandler) SaveCollection(c *gin.Context) {
+	r.setCollectionSaved(c, true, message.CollectionSaved)
+}
+
+// RemoveCollectionFromSaved Swagger Documentation
+// @Summary Remove Collection from Saved
+// @Security ApiKeyAuth
+// @Tags collections
+// @Description Remove collection from user library
+// @Accept json
+// @Produce json
+// @Param collection_id path int true "Collection ID"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/collections/{collection_id}/save [delete]
+func (r *CollectionHandler) RemoveCollectionFromSaved(c *gin.Context) {
+	r.setCollectionSaved(c, false, message.CollectionRemoved)
+}
+
+// ConvertCategoryToCollection Swagger Documentation
+// @Summary Convert Category to Collection
+// @Security ApiKeyAuth
+// @Tags categories
+// @Description Create collection from category recipes. Private recipes can't be published, so they are skipped;
+// @Description category itself stays untouched
+// @Accept json
+// @Produce json
+// @Param category_id path int true "Category ID"
+// @Param input body request_body.CategoryConversionInput true "Collection"
+// @Success 200 {object} response_body.Id
+// @Failure 400 {object} response_body.Error
+// @Router /v1/categories/{category_id}/collection [post]
+func (r *CollectionHandler) ConvertCategoryToCollection(c *gin.Context) {
+	userId, err := r.middleware.GetUserId(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	categoryId, err := strconv.Atoi(c.Param(ParamCategoryId))
+	if err != nil {
+		response.Failure(c, failure.Unknown)
+		return
+	}
+
+	var body request_body.CategoryConversionInput
+	if err := c.BindJSON(&body); err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+	if err := body.Validate(); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	collectionId, err := r.service.ConvertCategoryToCollection(categoryId, body.Entity(), userId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.NewId(c, collectionId, message.CategoryConverted)
+}
+
+func (r *CollectionHandler) setCollectionFollowed(c *gin.Context, isFollowed bool, successMessage string) {
+	userId, collectionId, err := getUserAndCollectionIds(c, r.middleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	if err := r.service.SetCollectionFollowed(collectionId, isFollowed, userId); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, successMessage)
+}
+
+func (r *CollectionHandler) setCollectionSaved(c *gin.Context, isSaved bool, successMessage string) {
+	userId, collectionId, err := getUserAndCollectionIds(c, r.middleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	if err := r.service.SetCollectionSaved(collectionId, isSaved, userId); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, successMessage)
+}
+
+func (r *CollectionHandler) getCollectionsQuery(c *gin.Context) *request_body.CollectionsQuery {
+	var params request_body.CollectionsQuery
+
+	if query, ok := c.GetQuery(queryAuthorId); ok {
+		if authorId, err := strconv.Atoi(query); err == nil {
+			params.AuthorId = &authorId
+		}
+	}
+
+	if ownedQuery, ok := c.GetQuery(queryOwned); ok {
+		params.Owned = ownedQuery == "true"
+	}
+
+	if savedQuery, ok := c.GetQuery(querySaved); ok {
+		params.Saved = savedQuery == "true"
+	}
+
+	if followedQuery, ok := c.GetQuery(queryFollowed); ok {
+		params.Followed = followedQuery == "true"
+	}
+
+	if search, ok := c.GetQuery(querySearch); ok {
+		params.Search = &search
+	}
+
+	if sortBy, ok := c.GetQuery(querySortBy); ok {
+		params.SortBy = sortBy
+	}
+
+	if query, ok := c.GetQuery(queryPage); ok {
+		if page, err := strconv.Atoi(query); err == nil {
+			params.Page = page
+		}
+	}
+
+	if query, ok := c.GetQuery(queryPageSize); ok {
+		if pageSize, err := strconv.Atoi(query); err == nil {
+			params.PageSize = pageSize
+		}
+	}
+
+	return &params
+}
+
+func getUserAndCollectionIds(c *gin.Context, middleware middleware.AuthMiddleware) (int, int, error) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	collectionId, err := strconv.Atoi(c.Param(ParamCollectionId))
+	if err != nil {
+		return 0, 0, failure.InvalidBody
+	}
+
+	return userId, collectionId, nil
+}
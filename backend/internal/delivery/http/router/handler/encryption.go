@@ -10,10 +10,6 @@ import (
 	"strconv"
 )
 
-const (
-	maxKeySize = 1 << 20
-)
-
 type EncryptionHandler struct {
 	authMiddleware middleware.AuthMiddleware
 	fileMiddleware middleware.FileMiddleware
@@ -32,7 +28,7 @@ func NewEncryptionHandler(authMiddleware middleware.AuthMiddleware, fileMiddlewa
 // @Summary Get User Key
 // @Security ApiKeyAuth
 // @Tags profile-encryption
-// @Description Get user encrypted vault key (AES encrypted by generated RSA)
+// @Description Get short-lived link to user encrypted vault key (AES encrypted by generated RSA)
 // @Accept json
 // @Produce json
 // @Success 200 {object} response_body.Link
@@ -45,7 +41,7 @@ func (r *EncryptionHandler) GetUserKey(c *gin.Context) {
 		return
 	}
 
-	url, err := r.service.GetUserKeyLink(userId)
+	url, err := r.service.GetUserKeyLink(c.Request.Context(), userId)
 	if err != nil {
 		response.Failure(c, err)
 		return
@@ -72,7 +68,7 @@ func (r *EncryptionHandler) UploadUserKey(c *gin.Context) {
 		return
 	}
 
-	file, err := r.fileMiddleware.GetFileWithMaxSize(c, maxKeySize)
+	file, err := r.fileMiddleware.GetKey(c)
 	if err != nil {
 		response.Failure(c, err)
 		return
@@ -117,7 +113,7 @@ func (r *EncryptionHandler) DeleteUserKey(c *gin.Context) {
 // @Summary Get Recipe Key
 // @Security ApiKeyAuth
 // @Tags recipe-encryption
-// @Description Get recipe encrypted key (AES encrypted by user RSA Private Key)
+// @Description Get short-lived link to recipe encrypted key (AES encrypted by user RSA Private Key)
 // @Accept json
 // @Produce json
 // @Param recipe_id path int true "Recipe ID"
@@ -131,7 +127,7 @@ func (r *EncryptionHandler) GetRecipeKey(c *gin.Context) {
 		return
 	}
 
-	url, err := r.service.GetRecipeKey(recipeId, userId)
+	url, err := r.service.GetRecipeKey(c.Request.Context(), recipeId, userId)
 	if err != nil {
 		response.Failure(c, err)
 		return
@@ -165,7 +161,7 @@ func (r *EncryptionHandler) UploadRecipeKey(c *gin.Context) {
 		return
 	}
 
-	file, err := r.fileMiddleware.GetFileWithMaxSize(c, maxKeySize)
+	file, err := r.fileMiddleware.GetKey(c)
 	if err != nil {
 		response.Failure(c, err)
 		return
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mephistolie/chefbook-server/internal/app/dependencies/service"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware/response"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body/message"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"strconv"
+)
+
+type FollowHandler struct {
+	middleware middleware.AuthMiddleware
+	service    service.Follow
+}
+
+func NewFollowHandler(middleware middleware.AuthMiddleware, service service.Follow) *FollowHandler {
+	return &FollowHandler{
+		middleware: middleware,
+		service:    service,
+	}
+}
+
+// FollowUser Swagger Documentation
+// @Summary Follow User
+// @Security ApiKeyAuth
+// @Tags users
+// @Description Follow recipes author
+// @Accept json
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/users/{user_id}/follow [put]
+func (r *FollowHandler) FollowUser(c *gin.Context) {
+	userId, authorId, err := getUserAndTargetUserIds(c, r.middleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	if err := r.service.FollowUser(authorId, userId); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, message.UserFollowed)
+}
+
+// UnfollowUser Swagger Documentation
+// @Summary Unfollow User
+// @Security ApiKeyAuth
+// @Tags users
+// @Description Unfollow recipes author
+// @Accept json
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/users/{user_id}/follow [delete]
+func (r *FollowHandler) UnfollowUser(c *gin.Context) {
+	userId, authorId, err := getUserAndTargetUserIds(c, r.middleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	if err := r.service.UnfollowUser(authorId, userId); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, message.UserUnfollowed)
+}
+
+// GetFollowers Swagger Documentation
+// @Summary Get Followers
+// @Security ApiKeyAuth
+// @Tags users
+// @Description Get users that follow user
+// @Accept json
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Success 200 {object} []response_body.PublicUserInfo
+// @Failure 400 {object} response_body.Error
+// @Router /v1/users/{user_id}/followers [get]
+func (r *FollowHandler) GetFollowers(c *gin.Context) {
+	_, targetUserId, err := getUserAndTargetUserIds(c, r.middleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	followers, err := r.service.GetFollowers(targetUserId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewPublicUsersList(followers))
+}
+
+// GetFollowing Swagger Documentation
+// @Summary Get Following
+// @Security ApiKeyAuth
+// @Tags users
+// @Description Get users followed by user
+// @Accept json
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Success 200 {object} []response_body.PublicUserInfo
+// @Failure 400 {object} response_body.Error
+// @Router /v1/users/{user_id}/following [get]
+func (r *FollowHandler) GetFollowing(c *gin.Context) {
+	_, targetUserId, err := getUserAndTargetUserIds(c, r.middleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	following, err := r.service.GetFollowing(targetUserId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewPublicUsersList(following))
+}
+
+func getUserAndTargetUserIds(c *gin.Context, middleware middleware.AuthMiddleware) (int, int, error) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	targetUserId, err := strconv.Atoi(c.Param(ParamUserId))
+	if err != nil {
+		return 0, 0, failure.InvalidUserId
+	}
+
+	return userId, targetUserId, nil
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mephistolie/chefbook-server/internal/app/dependencies/service"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware/response"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/request_body"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+)
+
+type NutritionHandler struct {
+	middleware middleware.AuthMiddleware
+	service    service.Nutrition
+}
+
+func NewNutritionHandler(middleware middleware.AuthMiddleware, service service.Nutrition) *NutritionHandler {
+	return &NutritionHandler{
+		middleware: middleware,
+		service:    service,
+	}
+}
+
+// GetRecipeNutrition Swagger Documentation
+// @Summary Get Recipe Nutrition
+// @Security ApiKeyAuth
+// @Tags recipes
+// @Description Calculate recipe calories and macronutrients per serving by ingredients. Every ingredient has match confidence from 0 to 1
+// @Accept json
+// @Produce json
+// @Param recipe_id path int true "Recipe ID"
+// @Param link_token query string false "Share link token granting access to private recipe"
+// @Success 200 {object} response_body.Nutrition
+// @Failure 400 {object} response_body.Error
+// @Router /v1/recipes/{recipe_id}/nutrition [get]
+func (r *NutritionHandler) GetRecipeNutrition(c *gin.Context) {
+	userId, recipeId, err := getUserAndRecipeIds(c, r.middleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	nutrition, err := r.service.GetRecipeNutrition(recipeId, userId, getLinkToken(c))
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewNutrition(nutrition))
+}
+
+// CalculateNutrition Swagger Documentation
+// @Summary Calculate Nutrition
+// @Security ApiKeyAuth
+// @Tags recipes
+// @Description Calculate calories and macronutrients per serving for recipe draft ingredients
+// @Accept json
+// @Produce json
+// @Param input body request_body.NutritionInput true "Ingredients"
+// @Success 200 {object} response_body.Nutrition
+// @Failure 400 {object} response_body.Error
+// @Router /v1/nutrition [post]
+func (r *NutritionHandler) CalculateNutrition(c *gin.Context) {
+	var body request_body.NutritionInput
+	if err := c.BindJSON(&body); err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+
+	if err := body.Validate(); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	nutrition := r.service.CalculateNutrition(body.IngredientsEntity(), body.Servings, body.Language)
+
+	response.Success(c, response_body.NewNutrition(nutrition))
+}
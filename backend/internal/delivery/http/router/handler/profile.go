@@ -16,8 +16,6 @@ const (
 	ParamUserId = "user_id"
 
 	queryUserId = "user_id"
-
-	maxAvatarSize = 1 << 20
 )
 
 type ProfileHandler struct {
@@ -43,7 +41,7 @@ func NewProfileHandler(authMiddleware middleware.AuthMiddleware, fileMiddleware
 // @Produce json
 // @Param user_id query string false "User ID"
 // @Success 200 {object} response_body.DetailedProfileInfo
-// @Success 200 {object} response_body.MinimalProfileInfo
+// @Success 200 {object} response_body.PublicProfileInfo
 // @Failure 400 {object} response_body.Error
 // @Router /v1/profile [get]
 func (r *ProfileHandler) GetProfileInfo(c *gin.Context) {
@@ -54,21 +52,49 @@ func (r *ProfileHandler) GetProfileInfo(c *gin.Context) {
 	}
 
 	requestedUserId, err := strconv.Atoi(c.Request.URL.Query().Get(queryUserId))
+	if err == nil && requestedUserId != userId {
+		r.getPublicProfile(c, requestedUserId, userId)
+		return
+	}
+
+	profile, err := r.service.GetProfile(userId)
 	if err != nil {
-		requestedUserId = userId
+		response.Failure(c, err)
+		return
 	}
 
-	profile, err := r.service.GetProfile(requestedUserId)
+	response.Success(c, response_body.NewDetailedProfileInfo(profile))
+}
+
+// GetPublicProfile Swagger Documentation
+// @Summary Get User Profile
+// @Security ApiKeyAuth
+// @Tags users
+// @Description Get public profile of user
+// @Accept json
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Success 200 {object} response_body.PublicProfileInfo
+// @Failure 400 {object} response_body.Error
+// @Router /v1/users/{user_id} [get]
+func (r *ProfileHandler) GetPublicProfile(c *gin.Context) {
+	userId, requestedUserId, err := getUserAndTargetUserIds(c, r.authMiddleware)
 	if err != nil {
 		response.Failure(c, err)
 		return
 	}
 
-	if userId == profile.Id {
-		response.Success(c, response_body.NewDetailedProfileInfo(profile))
-	} else {
-		response.Success(c, response_body.NewMinimalProfileInfoByProfile(profile))
+	r.getPublicProfile(c, requestedUserId, userId)
+}
+
+func (r *ProfileHandler) getPublicProfile(c *gin.Context, requestedUserId, userId int) {
+	profile, err := r.service.GetPublicProfile(requestedUserId, userId)
+	if err != nil {
+		response.Failure(c, err)
+		return
 	}
+
+	response.Success(c, response_body.NewPublicProfileInfo(profile))
 }
 
 // ChangePassword Swagger Documentation
@@ -142,11 +168,82 @@ func (r *ProfileHandler) SetUsername(c *gin.Context) {
 	response.Message(c, message.UsernameChanged)
 }
 
+// SetBio Swagger Documentation
+// @Summary Change Bio
+// @Security ApiKeyAuth
+// @Tags profile
+// @Description Change profile bio
+// @Accept json
+// @Produce json
+// @Param input body request_body.Bio true "Bio"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/profile/bio [put]
+func (r *ProfileHandler) SetBio(c *gin.Context) {
+	userId, err := r.authMiddleware.GetUserId(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	var body request_body.Bio
+	if err := c.BindJSON(&body); err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+
+	err = r.service.SetBio(userId, body.Bio)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, message.BioChanged)
+}
+
+// SetExcludedAllergens Swagger Documentation
+// @Summary Set Excluded Allergens
+// @Security ApiKeyAuth
+// @Tags profile
+// @Description Set allergens to hide recipes with them from recipes list and random recipe. Recipes with unknown allergens are hidden from public recipes too
+// @Accept json
+// @Produce json
+// @Param input body request_body.ExcludedAllergens true "Excluded allergens"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/profile/allergens [put]
+func (r *ProfileHandler) SetExcludedAllergens(c *gin.Context) {
+	userId, err := r.authMiddleware.GetUserId(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	var body request_body.ExcludedAllergens
+	if err := c.BindJSON(&body); err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+
+	if err := body.Validate(); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	err = r.service.SetExcludedAllergens(userId, body.Allergens)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, message.AllergensSet)
+}
+
 // UploadAvatar Swagger Documentation
 // @Summary Upload avatar
 // @Security ApiKeyAuth
 // @Tags profile
-// @Description Upload profile avatar
+// @Description Upload profile avatar. Avatar is re-encoded to JPEG without metadata and gets thumbnails
 // @Accept mpfd
 // @Produce json
 // @Param file formData file true "Avatar File"
@@ -160,12 +257,16 @@ func (r *ProfileHandler) UploadAvatar(c *gin.Context) {
 		return
 	}
 
-	file, err := r.fileMiddleware.GetFileWithMaxSize(c, maxAvatarSize)
+	file, err := r.fileMiddleware.GetAvatar(c)
 	if err != nil {
 		response.Failure(c, err)
 		return
 	}
 
+	if file.IsUndecodableImage() {
+		response.Failure(c, failure.UnsupportedImage)
+		return
+	}
 	if !file.IsImage() {
 		response.Failure(c, failure.UnsupportedFileType)
 		return
@@ -13,7 +13,8 @@ import (
 )
 
 const (
-	ParamRecipeId = "recipe_id"
+	ParamRecipeId  = "recipe_id"
+	ParamLinkToken = "token"
 
 	queryAuthorId    = "author_id"
 	queryOwned       = "owned"
@@ -21,6 +22,10 @@ const (
 	querySearch      = "search"
 	querySortBy      = "sort_by"
 	queryLanguages   = "language"
+	queryTags        = "tag"
+	queryExcludeTags = "exclude_tag"
+	queryFacets      = "facets"
+	queryCollections = "collections"
 	queryPage        = "page"
 	queryPageSize    = "page_size"
 	queryMinTime     = "min_time"
@@ -29,6 +34,10 @@ const (
 	queryMaxServings = "max_servings"
 	queryMinCalories = "min_calories"
 	queryMaxCalories = "max_calories"
+	queryLinkToken   = "link_token"
+
+	queryCategoryId           = "category_id"
+	queryIncludeSubcategories = "include_subcategories"
 )
 
 type RecipeHandler struct {
@@ -54,8 +63,11 @@ func NewRecipeCrudHandler(middleware middleware.AuthMiddleware, service service.
 // @Param owned query bool false "Get only those recipes that were created by user"
 // @Param saved query bool false "Get only those recipes that saved to user recipe book"
 // @Param search query string false "Search recipes with specified name"
-// @Param sort_by query string false "Sorting. Acceptable values: 'creation_timestamp', 'update_timestamp', 'likes', 'time', 'servings', 'calories'"
+// @Param sort_by query string false "Sorting. Acceptable values: 'creation_timestamp', 'update_timestamp', 'likes', 'trending', 'time', 'servings', 'calories'"
 // @Param language query []string false "Recipe language codes"
+// @Param tag query []string false "Get only those recipes that have all specified tags"
+// @Param exclude_tag query []string false "Exclude recipes that have any of specified tags"
+// @Param facets query bool false "Return recipes with tag facet counts for the whole query"
 // @Param page query string false "Page of the result"
 // @Param page_size query string false "Page size of the result. Maximum is 50"
 // @Param min_time query string false "Minimal recipe cooking time"
@@ -64,7 +76,10 @@ func NewRecipeCrudHandler(middleware middleware.AuthMiddleware, service service.
 // @Param max_servings query string false "Maximum recipe servings"
 // @Param min_calories query string false "Minimal recipe calories"
 // @Param max_calories query string false "Maximum recipe calories"
+// @Param category_id query int false "Get only those recipes that are in user category"
+// @Param include_subcategories query bool false "Include recipes of all subcategories of category"
 // @Success 200 {object} []response_body.RecipeInfo
+// @Success 200 {object} response_body.RecipesWithFacets
 // @Failure 400 {object} response_body.Error
 // @Router /v1/recipes [get]
 func (r *RecipeHandler) GetRecipes(c *gin.Context) {
@@ -86,7 +101,77 @@ func (r *RecipeHandler) GetRecipes(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, response_body.NewRecipes(recipes))
+	if !query.WithFacets {
+		response.Success(c, response_body.NewRecipes(recipes))
+		return
+	}
+
+	facets, err := r.service.GetRecipesTagFacets(query.Entity(), userId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewRecipesWithFacets(recipes, facets))
+}
+
+// GetFeed Swagger Documentation
+// @Summary Get Feed
+// @Security ApiKeyAuth
+// @Tags recipes
+// @Description Get new public recipes of followed authors
+// @Accept json
+// @Produce json
+// @Param collections query bool false "Also return recipes of followed collections"
+// @Param search query string false "Search recipes with specified name"
+// @Param sort_by query string false "Sorting. Acceptable values: 'creation_timestamp', 'update_timestamp', 'likes', 'trending', 'time', 'servings', 'calories'"
+// @Param language query []string false "Recipe language codes"
+// @Param tag query []string false "Get only those recipes that have all specified tags"
+// @Param exclude_tag query []string false "Exclude recipes that have any of specified tags"
+// @Param facets query bool false "Return recipes with tag facet counts for the whole query"
+// @Param page query string false "Page of the result"
+// @Param page_size query string false "Page size of the result. Maximum is 50"
+// @Param min_time query string false "Minimal recipe cooking time"
+// @Param max_time query string false "Maximum recipe cooking time"
+// @Param min_servings query string false "Minimal recipe servings"
+// @Param max_servings query string false "Maximum recipe servings"
+// @Param min_calories query string false "Minimal recipe calories"
+// @Param max_calories query string false "Maximum recipe calories"
+// @Success 200 {object} []response_body.RecipeInfo
+// @Success 200 {object} response_body.RecipesWithFacets
+// @Failure 400 {object} response_body.Error
+// @Router /v1/feed [get]
+func (r *RecipeHandler) GetFeed(c *gin.Context) {
+	userId, err := r.middleware.GetUserId(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	query := r.getRecipesQuery(c)
+	if err := query.Validate(userId); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	recipes, err := r.service.GetFeed(query.Entity(), userId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	if !query.WithFacets {
+		response.Success(c, response_body.NewRecipes(recipes))
+		return
+	}
+
+	facets, err := r.service.GetFeedTagFacets(query.Entity(), userId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewRecipesWithFacets(recipes, facets))
 }
 
 // GetRecipe Swagger Documentation
@@ -97,6 +182,7 @@ func (r *RecipeHandler) GetRecipes(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param recipe_id path int true "Recipe ID"
+// @Param link_token query string false "Share link token granting access to private recipe"
 // @Success 200 {object} response_body.Recipe
 // @Failure 400 {object} response_body.Error
 // @Router /v1/recipes/{recipe_id} [get]
@@ -107,7 +193,34 @@ func (r *RecipeHandler) GetRecipe(c *gin.Context) {
 		return
 	}
 
-	recipe, err := r.service.GetRecipe(recipeId, userId)
+	recipe, err := r.service.GetRecipe(recipeId, userId, getLinkToken(c))
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewRecipe(recipe))
+}
+
+// GetSharedRecipe Swagger Documentation
+// @Summary Get Shared Recipe
+// @Security ApiKeyAuth
+// @Tags recipes
+// @Description Get recipe by share link token. Use token as 'link_token' query parameter to save recipe to recipe book
+// @Accept json
+// @Produce json
+// @Param token path string true "Share link token"
+// @Success 200 {object} response_body.Recipe
+// @Failure 400 {object} response_body.Error
+// @Router /v1/shared/{token} [get]
+func (r *RecipeHandler) GetSharedRecipe(c *gin.Context) {
+	userId, err := r.middleware.GetUserId(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	recipe, err := r.service.GetSharedRecipe(c.Param(ParamLinkToken), userId)
 	if err != nil {
 		response.Failure(c, err)
 		return
@@ -156,6 +269,7 @@ func (r *RecipeHandler) GetRandomRecipe(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param recipe_id path int true "Recipe ID"
+// @Param link_token query string false "Share link token granting access to private recipe"
 // @Success 200 {object} response_body.Message
 // @Failure 400 {object} response_body.Error
 // @Router /v1/recipes/{recipe_id}/save [post]
@@ -166,7 +280,7 @@ func (r *RecipeHandler) AddRecipeToRecipeBook(c *gin.Context) {
 		return
 	}
 
-	err = r.service.AddRecipeToRecipeBook(recipeId, userId)
+	err = r.service.AddRecipeToRecipeBook(recipeId, userId, getLinkToken(c))
 	if err != nil {
 		response.Failure(c, err)
 		return
@@ -290,6 +404,7 @@ func (r *RecipeHandler) setRecipeFavourite(c *gin.Context, favourite bool) {
 // @Accept json
 // @Produce json
 // @Param recipe_id path int true "Recipe ID"
+// @Param link_token query string false "Share link token granting access to private recipe"
 // @Success 200 {object} response_body.Message
 // @Failure 400 {object} response_body.Error
 // @Router /v1/recipes/{recipe_id}/likes [put]
@@ -305,6 +420,7 @@ func (r *RecipeHandler) LikeRecipe(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param recipe_id path int true "Recipe ID"
+// @Param link_token query string false "Share link token granting access to private recipe"
 // @Success 200 {object} response_body.Message
 // @Failure 400 {object} response_body.Error
 // @Router /v1/recipes/{recipe_id}/likes [delete]
@@ -312,6 +428,33 @@ func (r *RecipeHandler) UnlikeRecipe(c *gin.Context) {
 	r.setRecipeLiked(c, false)
 }
 
+// MarkRecipeCooked Swagger Documentation
+// @Summary Mark Recipe Cooked
+// @Security ApiKeyAuth
+// @Tags recipes
+// @Description Record recipe cooking for achievements and cooking streak. Recipe cooking is recorded once a day
+// @Accept json
+// @Produce json
+// @Param recipe_id path int true "Recipe ID"
+// @Param link_token query string false "Share link token granting access to private recipe"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/recipes/{recipe_id}/cooked [post]
+func (r *RecipeHandler) MarkRecipeCooked(c *gin.Context) {
+	userId, recipeId, err := getUserAndRecipeIds(c, r.middleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	if err = r.service.MarkRecipeCooked(recipeId, userId, getLinkToken(c)); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, message.RecipeCooked)
+}
+
 func (r *RecipeHandler) setRecipeLiked(c *gin.Context, liked bool) {
 	userId, recipeId, err := getUserAndRecipeIds(c, r.middleware)
 	if err != nil {
@@ -319,7 +462,7 @@ func (r *RecipeHandler) setRecipeLiked(c *gin.Context, liked bool) {
 		return
 	}
 
-	err = r.service.SetRecipeLikeStatus(recipeId, liked, userId)
+	err = r.service.SetRecipeLikeStatus(recipeId, liked, userId, getLinkToken(c))
 	if err != nil {
 		response.Failure(c, err)
 		return
@@ -333,7 +476,7 @@ func (r *RecipeHandler) getRecipesQuery(c *gin.Context) *request_body.RecipesQue
 
 	if query, ok := c.GetQuery(queryAuthorId); ok {
 		if authorId, err := strconv.Atoi(query); err == nil {
-			*params.AuthorId = authorId
+			params.AuthorId = &authorId
 		}
 	}
 
@@ -346,7 +489,7 @@ func (r *RecipeHandler) getRecipesQuery(c *gin.Context) *request_body.RecipesQue
 	}
 
 	if search, ok := c.GetQuery(querySearch); ok {
-		*params.Search = search
+		params.Search = &search
 	}
 
 	if sortBy, ok := c.GetQuery(querySortBy); ok {
@@ -366,48 +509,81 @@ func (r *RecipeHandler) getRecipesQuery(c *gin.Context) *request_body.RecipesQue
 	}
 
 	if languages, ok := c.GetQueryArray(queryLanguages); ok {
-		*params.Languages = languages
+		params.Languages = &languages
+	}
+
+	if tags, ok := c.GetQueryArray(queryTags); ok {
+		params.Tags = &tags
+	}
+
+	if excludedTags, ok := c.GetQueryArray(queryExcludeTags); ok {
+		params.ExcludeTags = &excludedTags
+	}
+
+	if facetsQuery, ok := c.GetQuery(queryFacets); ok {
+		params.WithFacets = facetsQuery == "true"
+	}
+
+	if collectionsQuery, ok := c.GetQuery(queryCollections); ok {
+		params.Collections = collectionsQuery == "true"
+	}
+
+	if query, ok := c.GetQuery(queryCategoryId); ok {
+		if categoryId, err := strconv.Atoi(query); err == nil {
+			params.CategoryId = &categoryId
+		}
+	}
+
+	if query, ok := c.GetQuery(queryIncludeSubcategories); ok {
+		params.IncludeSubcategories = query == "true"
 	}
 
 	if query, ok := c.GetQuery(queryMinTime); ok {
 		if minTime, err := strconv.Atoi(query); err == nil {
-			*params.MinTime = minTime
+			params.MinTime = &minTime
 		}
 	}
 
 	if query, ok := c.GetQuery(queryMaxTime); ok {
 		if maxTime, err := strconv.Atoi(query); err == nil {
-			*params.MaxTime = maxTime
+			params.MaxTime = &maxTime
 		}
 	}
 
 	if query, ok := c.GetQuery(queryMinServings); ok {
 		if minServings, err := strconv.Atoi(query); err == nil {
-			*params.MinServings = minServings
+			params.MinServings = &minServings
 		}
 	}
 
 	if query, ok := c.GetQuery(queryMaxServings); ok {
 		if maxServings, err := strconv.Atoi(query); err == nil {
-			*params.MaxServings = maxServings
+			params.MaxServings = &maxServings
 		}
 	}
 
 	if query, ok := c.GetQuery(queryMinCalories); ok {
 		if minCalories, err := strconv.Atoi(query); err == nil {
-			*params.MinCalories = minCalories
+			params.MinCalories = &minCalories
 		}
 	}
 
 	if query, ok := c.GetQuery(queryMaxCalories); ok {
 		if maxCalories, err := strconv.Atoi(query); err == nil {
-			*params.MaxCalories = maxCalories
+			params.MaxCalories = &maxCalories
 		}
 	}
 
 	return &params
 }
 
+func getLinkToken(c *gin.Context) *string {
+	if token, ok := c.GetQuery(queryLinkToken); ok && token != "" {
+		return &token
+	}
+	return nil
+}
+
 func getUserAndRecipeIds(c *gin.Context, middleware middleware.AuthMiddleware) (int, int, error) {
 	userId, err := middleware.GetUserId(c)
 	if err != nil {
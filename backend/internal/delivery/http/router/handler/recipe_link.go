@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mephistolie/chefbook-server/internal/app/dependencies/service"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware/response"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/request_body"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body/message"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"io"
+	"strconv"
+)
+
+const (
+	ParamLinkId = "link_id"
+)
+
+type RecipeLinkHandler struct {
+	middleware middleware.AuthMiddleware
+	service    service.RecipeLink
+}
+
+func NewRecipeLinkHandler(middleware middleware.AuthMiddleware, service service.RecipeLink) *RecipeLinkHandler {
+	return &RecipeLinkHandler{
+		middleware: middleware,
+		service:    service,
+	}
+}
+
+// CreateRecipeLink Swagger Documentation
+// @Summary Create Recipe Share Link
+// @Security ApiKeyAuth
+// @Tags recipes
+// @Description Create revocable share link token for recipe. Link never expires if expiration time isn't set
+// @Accept json
+// @Produce json
+// @Param recipe_id path int true "Recipe ID"
+// @Param input body request_body.RecipeLinkInput false "Link expiration time"
+// @Success 200 {object} response_body.RecipeLink
+// @Failure 400 {object} response_body.Error
+// @Router /v1/recipes/{recipe_id}/links [post]
+func (r *RecipeLinkHandler) CreateRecipeLink(c *gin.Context) {
+	userId, recipeId, err := getUserAndRecipeIds(c, r.middleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	var body request_body.RecipeLinkInput
+	if err := c.ShouldBindJSON(&body); err != nil && err != io.EOF {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+	if err := body.Validate(); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	link, err := r.service.CreateRecipeLink(recipeId, body.Entity(), userId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewRecipeLink(link))
+}
+
+// GetRecipeLinks Swagger Documentation
+// @Summary Get Recipe Share Links
+// @Security ApiKeyAuth
+// @Tags recipes
+// @Description Get share links of owned recipe
+// @Accept json
+// @Produce json
+// @Param recipe_id path int true "Recipe ID"
+// @Success 200 {object} []response_body.RecipeLink
+// @Failure 400 {object} response_body.Error
+// @Router /v1/recipes/{recipe_id}/links [get]
+func (r *RecipeLinkHandler) GetRecipeLinks(c *gin.Context) {
+	userId, recipeId, err := getUserAndRecipeIds(c, r.middleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	links, err := r.service.GetRecipeLinks(recipeId, userId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewRecipeLinks(links))
+}
+
+// DeleteRecipeLink Swagger Documentation
+// @Summary Revoke Recipe Share Link
+// @Security ApiKeyAuth
+// @Tags recipes
+// @Description Revoke share link. Users who saved private recipe by this link lose access to it
+// @Accept json
+// @Produce json
+// @Param recipe_id path int true "Recipe ID"
+// @Param link_id path int true "Link ID"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/recipes/{recipe_id}/links/{link_id} [delete]
+func (r *RecipeLinkHandler) DeleteRecipeLink(c *gin.Context) {
+	userId, recipeId, err := getUserAndRecipeIds(c, r.middleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	linkId, err := strconv.Atoi(c.Param(ParamLinkId))
+	if err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+
+	if err := r.service.DeleteRecipeLink(recipeId, linkId, userId); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, message.RecipeLinkDeleted)
+}
@@ -98,7 +98,7 @@ func (r *OwnedRecipeHandler) UpdateRecipe(c *gin.Context) {
 	}
 
 
-	if err := r.service.UpdateRecipe(body.Entity(), recipeId, userId); err != nil {
+	if err := r.service.UpdateRecipe(c.Request.Context(), body.Entity(), recipeId, userId); err != nil {
 		response.Failure(c, err)
 		return
 	}
@@ -5,13 +5,16 @@ import (
 	"github.com/mephistolie/chefbook-server/internal/app/dependencies/service"
 	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware"
 	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware/response"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/request_body"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body"
 	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body/message"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"net/http"
+	"strconv"
 )
 
 const (
 	ParamPictureId = "picture_id"
-
-	maxRecipePictureSize = 1 << 20
 )
 
 type RecipePictureHandler struct {
@@ -32,11 +35,12 @@ func NewRecipePictureHandler(authMiddleware middleware.AuthMiddleware, fileMiddl
 // @Summary Get Recipe Pictures
 // @Security ApiKeyAuth
 // @Tags recipe-pictures
-// @Description Get recipe pictures links
+// @Description Get recipe gallery in its order. Links to pictures of private and shared recipes are signed and expire
 // @Accept json
 // @Produce json
 // @Param recipe_id path int true "Recipe ID"
-// @Success 200 {object} []string
+// @Param link_token query string false "Share link token for private recipe"
+// @Success 200 {object} []response_body.RecipePicture
 // @Failure 400 {object} response_body.Error
 // @Router /v1/recipes/{recipe_id}/pictures [get]
 func (r *RecipePictureHandler) GetRecipePictures(c *gin.Context) {
@@ -46,25 +50,61 @@ func (r *RecipePictureHandler) GetRecipePictures(c *gin.Context) {
 		return
 	}
 
-	pictures, err := r.service.GetRecipePictures(c.Request.Context(), recipeId, userId)
+	pictures, err := r.service.GetRecipePictures(c.Request.Context(), recipeId, userId, getLinkToken(c))
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewRecipePictures(pictures))
+}
+
+// GetRecipePicture Swagger Documentation
+// @Summary Get Recipe Picture
+// @Security ApiKeyAuth
+// @Tags recipe-pictures
+// @Description Redirect to recipe picture. Pictures of private and shared recipes are available by signed expiring links
+// @Accept json
+// @Produce json
+// @Param recipe_id path int true "Recipe ID"
+// @Param picture_id path int true "Picture ID"
+// @Param link_token query string false "Share link token for private recipe"
+// @Success 302
+// @Failure 400 {object} response_body.Error
+// @Router /v1/recipes/{recipe_id}/pictures/{picture_id} [get]
+func (r *RecipePictureHandler) GetRecipePicture(c *gin.Context) {
+	userId, recipeId, err := getUserAndRecipeIds(c, r.authMiddleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	pictureId, err := strconv.Atoi(c.Param(ParamPictureId))
+	if err != nil {
+		response.Failure(c, failure.PictureNotFound)
+		return
+	}
+
+	link, err := r.service.GetRecipePicture(c.Request.Context(), recipeId, userId, pictureId, getLinkToken(c))
 	if err != nil {
 		response.Failure(c, err)
 		return
 	}
 
-	response.Success(c, pictures)
+	c.Redirect(http.StatusFound, link)
 }
 
 // UploadRecipePicture Swagger Documentation
 // @Summary Upload Recipe Picture
 // @Security ApiKeyAuth
 // @Tags recipe-pictures
-// @Description Upload recipe picture as usual or encrypted file
+// @Description Upload recipe picture as usual or encrypted file. Usual pictures are re-encoded to JPEG without metadata and get thumbnails.
+// @Description Picture is added to the end of recipe gallery
 // @Accept mpfd
 // @Produce json
 // @Param recipe_id path int true "Recipe ID"
 // @Param file formData file true "Picture File"
-// @Success 200 {object} response_body.Link
+// @Success 200 {object} response_body.RecipePicture
 // @Failure 400 {object} response_body.Error
 // @Router /v1/recipes/{recipe_id}/pictures [post]
 func (r *RecipePictureHandler) UploadRecipePicture(c *gin.Context) {
@@ -74,33 +114,183 @@ func (r *RecipePictureHandler) UploadRecipePicture(c *gin.Context) {
 		return
 	}
 
-	file, err := r.fileMiddleware.GetFileWithMaxSize(c, maxRecipePictureSize)
+	file, err := r.fileMiddleware.GetRecipePicture(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	picture, err := r.service.UploadRecipePicture(c.Request.Context(), recipeId, userId, file)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewRecipePicture(picture))
+}
+
+// GetRecipePictureUploadLink Swagger Documentation
+// @Summary Get Recipe Picture Upload Link
+// @Security ApiKeyAuth
+// @Tags recipe-pictures
+// @Description Get presigned link for direct picture upload to storage. Send PUT request to link with returned headers
+// @Description and confirm upload after that. Link expires after several minutes
+// @Accept json
+// @Produce json
+// @Param recipe_id path int true "Recipe ID"
+// @Param input body request_body.UploadInput true "File parameters"
+// @Success 200 {object} response_body.UploadLink
+// @Failure 400 {object} response_body.Error
+// @Router /v1/recipes/{recipe_id}/pictures/upload-url [post]
+func (r *RecipePictureHandler) GetRecipePictureUploadLink(c *gin.Context) {
+	userId, recipeId, err := getUserAndRecipeIds(c, r.authMiddleware)
 	if err != nil {
 		response.Failure(c, err)
 		return
 	}
 
-	url, err := r.service.UploadRecipePicture(c.Request.Context(), recipeId, userId, file)
+	var body request_body.UploadInput
+	if err := c.BindJSON(&body); err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+
+	link, err := r.service.GetRecipePictureUploadLink(c.Request.Context(), recipeId, userId, body.Entity())
 	if err != nil {
 		response.Failure(c, err)
 		return
 	}
 
-	response.Link(c, url)
+	response.Success(c, response_body.NewUploadLink(link))
+}
+
+// ConfirmRecipePictureUpload Swagger Documentation
+// @Summary Confirm Recipe Picture Upload
+// @Security ApiKeyAuth
+// @Tags recipe-pictures
+// @Description Register picture uploaded by presigned link and add it to the end of recipe gallery
+// @Accept json
+// @Produce json
+// @Param recipe_id path int true "Recipe ID"
+// @Param input body request_body.UploadConfirmation true "Upload"
+// @Success 200 {object} response_body.RecipePicture
+// @Failure 400 {object} response_body.Error
+// @Router /v1/recipes/{recipe_id}/pictures/upload-url/confirm [post]
+func (r *RecipePictureHandler) ConfirmRecipePictureUpload(c *gin.Context) {
+	userId, recipeId, err := getUserAndRecipeIds(c, r.authMiddleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	var body request_body.UploadConfirmation
+	if err := c.BindJSON(&body); err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+
+	picture, err := r.service.ConfirmRecipePictureUpload(c.Request.Context(), recipeId, userId, body.UploadId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewRecipePicture(picture))
+}
+
+// UpdateRecipePicture Swagger Documentation
+// @Summary Update Recipe Picture
+// @Security ApiKeyAuth
+// @Tags recipe-pictures
+// @Description Set picture caption and make it recipe preview. Unsetting preview flag of preview picture resets recipe preview
+// @Accept json
+// @Produce json
+// @Param recipe_id path int true "Recipe ID"
+// @Param picture_id path int true "Picture ID"
+// @Param input body request_body.RecipePictureInput true "Picture"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/recipes/{recipe_id}/pictures/{picture_id} [put]
+func (r *RecipePictureHandler) UpdateRecipePicture(c *gin.Context) {
+	userId, recipeId, err := getUserAndRecipeIds(c, r.authMiddleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	pictureId, err := strconv.Atoi(c.Param(ParamPictureId))
+	if err != nil {
+		response.Failure(c, failure.PictureNotFound)
+		return
+	}
+
+	var body request_body.RecipePictureInput
+	if err := c.BindJSON(&body); err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+	if err := body.Validate(); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	if err = r.service.UpdateRecipePicture(recipeId, userId, pictureId, body.Entity()); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, message.RecipePictureUpdated)
+}
+
+// SetRecipePicturesOrder Swagger Documentation
+// @Summary Set Recipe Pictures Order
+// @Security ApiKeyAuth
+// @Tags recipe-pictures
+// @Description Reorder recipe gallery. Body must contain IDs of all recipe pictures in new order
+// @Accept json
+// @Produce json
+// @Param recipe_id path int true "Recipe ID"
+// @Param input body request_body.RecipePicturesOrderInput true "Picture IDs"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/recipes/{recipe_id}/pictures/order [put]
+func (r *RecipePictureHandler) SetRecipePicturesOrder(c *gin.Context) {
+	userId, recipeId, err := getUserAndRecipeIds(c, r.authMiddleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	var body request_body.RecipePicturesOrderInput
+	if err := c.BindJSON(&body); err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+	if err := body.Validate(); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	if err = r.service.SetRecipePicturesOrder(recipeId, userId, body.Pictures); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, message.RecipePicturesOrderUpdated)
 }
 
 // DeleteRecipePicture Swagger Documentation
 // @Summary Delete Recipe Picture
 // @Security ApiKeyAuth
 // @Tags recipe-pictures
-// @Description Delete recipe picture by name
-// @Accept mpfd
+// @Description Delete picture from recipe gallery. Picture is reset from recipe preview if it was used as it
+// @Accept json
 // @Produce json
 // @Param recipe_id path int true "Recipe ID"
-// @Param picture_name path int true "Picture Name"
+// @Param picture_id path int true "Picture ID"
 // @Success 200 {object} response_body.Message
 // @Failure 400 {object} response_body.Error
-// @Router /v1/recipes/{recipe_id}/pictures/{picture_name} [delete]
+// @Router /v1/recipes/{recipe_id}/pictures/{picture_id} [delete]
 func (r *RecipePictureHandler) DeleteRecipePicture(c *gin.Context) {
 	userId, recipeId, err := getUserAndRecipeIds(c, r.authMiddleware)
 	if err != nil {
@@ -108,9 +298,13 @@ func (r *RecipePictureHandler) DeleteRecipePicture(c *gin.Context) {
 		return
 	}
 
-	pictureName := c.Param(ParamPictureId)
+	pictureId, err := strconv.Atoi(c.Param(ParamPictureId))
+	if err != nil {
+		response.Failure(c, failure.PictureNotFound)
+		return
+	}
 
-	err = r.service.DeleteRecipePicture(c.Request.Context(), recipeId, userId, pictureName)
+	err = r.service.DeleteRecipePicture(c.Request.Context(), recipeId, userId, pictureId)
 	if err != nil {
 		response.Failure(c, err)
 		return
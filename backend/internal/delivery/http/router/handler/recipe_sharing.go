@@ -6,12 +6,19 @@ import (
 	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware"
 	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware/response"
 	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/common_body"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/request_body"
 	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body"
 	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body/message"
 	"github.com/mephistolie/chefbook-server/internal/entity/failure"
 	"strconv"
 )
 
+const (
+	ParamKeyRequestId = "request_id"
+
+	queryStatus = "status"
+)
+
 type RecipeSharingHandler struct {
 	authMiddleware middleware.AuthMiddleware
 	fileMiddleware middleware.FileMiddleware
@@ -152,11 +159,12 @@ func (r *RecipeSharingHandler) DeleteUserPublicKey(c *gin.Context) {
 // @Summary Get User Recipe Private Key
 // @Security ApiKeyAuth
 // @Tags recipe-sharing
-// @Description Get recipe key encrypted by user public key
+// @Description Get recipe key encrypted by user public key. Key set by owner manually is returned as link,
+// @Description key from approved key request is returned as is
 // @Accept json
 // @Produce json
 // @Param recipe_id path int true "Recipe ID"
-// @Success 200 {object} common_body.RecipeOwnerPrivateKey
+// @Success 200 {object} response_body.RecipeUserKey
 // @Failure 400 {object} response_body.Error
 // @Router /v1/recipes/{recipe_id}/users/key [get]
 func (r *RecipeSharingHandler) GetUserRecipeKey(c *gin.Context) {
@@ -166,14 +174,13 @@ func (r *RecipeSharingHandler) GetUserRecipeKey(c *gin.Context) {
 		return
 	}
 
-	var body common_body.RecipeOwnerPrivateKey
-	body.PrivateKey, err = r.service.GetOwnerPrivateKeyForUser(recipeId, userId)
+	key, err := r.service.GetOwnerPrivateKeyForUser(recipeId, userId)
 	if err != nil {
 		response.Failure(c, err)
 		return
 	}
 
-	response.Success(c, body)
+	response.Success(c, response_body.NewRecipeUserKey(key))
 }
 
 // SetOwnerPrivateKey Swagger Documentation
@@ -284,3 +291,218 @@ func (r *RecipeSharingHandler) DeleteUserAccess(c *gin.Context) {
 
 	response.Message(c, message.KeyDeleted)
 }
+
+// RequestRecipeKey Swagger Documentation
+// @Summary Request Recipe Key
+// @Security ApiKeyAuth
+// @Tags recipe-sharing
+// @Description Ask owner of encrypted recipe for its key. Owner approves request by uploading recipe key encrypted with passed public key
+// @Accept json
+// @Produce json
+// @Param recipe_id path int true "Recipe ID"
+// @Param input body common_body.RecipeUserPublicKey true "Key"
+// @Param link_token query string false "Share link token granting access to private recipe"
+// @Success 200 {object} response_body.Id
+// @Failure 400 {object} response_body.Error
+// @Router /v1/recipes/{recipe_id}/key-requests [post]
+func (r *RecipeSharingHandler) RequestRecipeKey(c *gin.Context) {
+	userId, recipeId, err := getUserAndRecipeIds(c, r.authMiddleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	var body common_body.RecipeUserPublicKey
+	if err := c.BindJSON(&body); err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+
+	requestId, err := r.service.RequestRecipeKey(recipeId, userId, body.PublicKey, getLinkToken(c))
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.NewId(c, requestId, message.KeyRequested)
+}
+
+// GetRecipeKeyRequests Swagger Documentation
+// @Summary Get Recipe Key Requests
+// @Security ApiKeyAuth
+// @Tags recipe-sharing
+// @Description Get key requests for owned encrypted recipe
+// @Accept json
+// @Produce json
+// @Param recipe_id path int true "Recipe ID"
+// @Param status query string false "Request status. Acceptable values: 'pending', 'approved', 'rejected', 'cancelled'"
+// @Success 200 {object} []response_body.RecipeKeyRequest
+// @Failure 400 {object} response_body.Error
+// @Router /v1/recipes/{recipe_id}/key-requests [get]
+func (r *RecipeSharingHandler) GetRecipeKeyRequests(c *gin.Context) {
+	userId, recipeId, err := getUserAndRecipeIds(c, r.authMiddleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	status, err := getKeyRequestStatus(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	requests, err := r.service.GetRecipeKeyRequests(recipeId, userId, status)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewRecipeKeyRequests(requests))
+}
+
+// GetUserKeyRequests Swagger Documentation
+// @Summary Get User Key Requests
+// @Security ApiKeyAuth
+// @Tags recipe-sharing
+// @Description Get key requests sent by user
+// @Accept json
+// @Produce json
+// @Param status query string false "Request status. Acceptable values: 'pending', 'approved', 'rejected', 'cancelled'"
+// @Success 200 {object} []response_body.RecipeKeyRequest
+// @Failure 400 {object} response_body.Error
+// @Router /v1/profile/key-requests [get]
+func (r *RecipeSharingHandler) GetUserKeyRequests(c *gin.Context) {
+	userId, err := r.authMiddleware.GetUserId(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	status, err := getKeyRequestStatus(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	requests, err := r.service.GetUserKeyRequests(userId, status)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewRecipeKeyRequests(requests))
+}
+
+// ApproveKeyRequest Swagger Documentation
+// @Summary Approve Key Request
+// @Security ApiKeyAuth
+// @Tags recipe-sharing
+// @Description Approve key request with recipe key encrypted by requester public key
+// @Accept json
+// @Produce json
+// @Param recipe_id path int true "Recipe ID"
+// @Param request_id path int true "Key Request ID"
+// @Param input body common_body.RecipeOwnerPrivateKey true "Key"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/recipes/{recipe_id}/key-requests/{request_id}/approve [post]
+func (r *RecipeSharingHandler) ApproveKeyRequest(c *gin.Context) {
+	userId, recipeId, requestId, err := getUserRecipeAndKeyRequestIds(c, r.authMiddleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	var body common_body.RecipeOwnerPrivateKey
+	if err := c.BindJSON(&body); err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+
+	if err := r.service.ApproveKeyRequest(recipeId, requestId, userId, body.PrivateKey); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, message.KeyRequestApproved)
+}
+
+// RejectKeyRequest Swagger Documentation
+// @Summary Reject Key Request
+// @Security ApiKeyAuth
+// @Tags recipe-sharing
+// @Description Reject key request
+// @Accept json
+// @Produce json
+// @Param recipe_id path int true "Recipe ID"
+// @Param request_id path int true "Key Request ID"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/recipes/{recipe_id}/key-requests/{request_id}/reject [post]
+func (r *RecipeSharingHandler) RejectKeyRequest(c *gin.Context) {
+	userId, recipeId, requestId, err := getUserRecipeAndKeyRequestIds(c, r.authMiddleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	if err := r.service.RejectKeyRequest(recipeId, requestId, userId); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, message.KeyRequestRejected)
+}
+
+// CancelKeyRequest Swagger Documentation
+// @Summary Cancel Key Request
+// @Security ApiKeyAuth
+// @Tags recipe-sharing
+// @Description Cancel pending key request sent by user
+// @Accept json
+// @Produce json
+// @Param recipe_id path int true "Recipe ID"
+// @Param request_id path int true "Key Request ID"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/recipes/{recipe_id}/key-requests/{request_id} [delete]
+func (r *RecipeSharingHandler) CancelKeyRequest(c *gin.Context) {
+	userId, recipeId, requestId, err := getUserRecipeAndKeyRequestIds(c, r.authMiddleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	if err := r.service.CancelKeyRequest(recipeId, requestId, userId); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, message.KeyRequestCancelled)
+}
+
+func getKeyRequestStatus(c *gin.Context) (*string, error) {
+	status, ok := c.GetQuery(queryStatus)
+	if !ok || status == "" {
+		return nil, nil
+	}
+	if err := request_body.ValidateKeyRequestStatus(&status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func getUserRecipeAndKeyRequestIds(c *gin.Context, middleware middleware.AuthMiddleware) (int, int, int, error) {
+	userId, recipeId, err := getUserAndRecipeIds(c, middleware)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	requestId, err := strconv.Atoi(c.Param(ParamKeyRequestId))
+	if err != nil {
+		return 0, 0, 0, failure.InvalidBody
+	}
+
+	return userId, recipeId, requestId, nil
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mephistolie/chefbook-server/internal/app/dependencies/service"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware/response"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body"
+	"strconv"
+)
+
+const (
+	defaultRecommendationsCount = 20
+	maxRecommendationsCount     = 50
+)
+
+type RecommendationHandler struct {
+	middleware middleware.AuthMiddleware
+	service    service.Recommendation
+}
+
+func NewRecommendationHandler(middleware middleware.AuthMiddleware, service service.Recommendation) *RecommendationHandler {
+	return &RecommendationHandler{
+		middleware: middleware,
+		service:    service,
+	}
+}
+
+// GetRecommendedRecipes Swagger Documentation
+// @Summary Get Recommended Recipes
+// @Security ApiKeyAuth
+// @Tags recipes
+// @Description Get public recipes recommended for user by likes, saved recipes, favourites and categories
+// @Accept json
+// @Produce json
+// @Param language query []string false "Recipe language codes"
+// @Param page_size query string false "Recipes count. Maximum is 50"
+// @Success 200 {object} []response_body.RecipeInfo
+// @Failure 400 {object} response_body.Error
+// @Router /v1/recipes/recommended [get]
+func (r *RecommendationHandler) GetRecommendedRecipes(c *gin.Context) {
+	userId, err := r.middleware.GetUserId(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	var languages *[]string = nil
+	if parsedLanguages, ok := c.GetQueryArray(queryLanguages); ok {
+		languages = &parsedLanguages
+	}
+
+	count := defaultRecommendationsCount
+	if query, ok := c.GetQuery(queryPageSize); ok {
+		if pageSize, err := strconv.Atoi(query); err == nil && pageSize > 0 {
+			count = pageSize
+		}
+	}
+	if count > maxRecommendationsCount {
+		count = maxRecommendationsCount
+	}
+
+	recipes, err := r.service.GetRecommendedRecipes(languages, count, userId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewRecipes(recipes))
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mephistolie/chefbook-server/internal/app/dependencies/service"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware/response"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body"
+)
+
+type ReferralHandler struct {
+	middleware middleware.AuthMiddleware
+	service    service.Referral
+}
+
+func NewReferralHandler(middleware middleware.AuthMiddleware, service service.Referral) *ReferralHandler {
+	return &ReferralHandler{
+		middleware: middleware,
+		service:    service,
+	}
+}
+
+// GetReferrals Swagger Documentation
+// @Summary Get Referrals
+// @Security ApiKeyAuth
+// @Tags profile
+// @Description Get user referral code and users invited with it, newest first.
+// @Description Statuses: 'pending' until invitee activation, 'rewarded', 'limit_reached' if referrer rewards limit was reached
+// @Accept json
+// @Produce json
+// @Success 200 {object} response_body.Referrals
+// @Failure 400 {object} response_body.Error
+// @Router /v1/profile/referrals [get]
+func (r *ReferralHandler) GetReferrals(c *gin.Context) {
+	userId, err := r.middleware.GetUserId(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	referrals, err := r.service.GetReferrals(userId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewReferrals(referrals))
+}
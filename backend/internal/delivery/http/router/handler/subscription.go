@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mephistolie/chefbook-server/internal/app/dependencies/service"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware/response"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/request_body"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body/message"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+)
+
+const (
+	ParamSubscriptionProvider = "provider"
+
+	queryWebhookToken = "token"
+)
+
+type SubscriptionHandler struct {
+	middleware          middleware.AuthMiddleware
+	entitlementService  service.Entitlement
+	subscriptionService service.Subscription
+}
+
+func NewSubscriptionHandler(middleware middleware.AuthMiddleware, entitlementService service.Entitlement,
+	subscriptionService service.Subscription) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		middleware:          middleware,
+		entitlementService:  entitlementService,
+		subscriptionService: subscriptionService,
+	}
+}
+
+// GetEntitlements Swagger Documentation
+// @Summary Get Entitlements
+// @Security ApiKeyAuth
+// @Tags profile
+// @Description Get features and limits available to user by its tier
+// @Accept json
+// @Produce json
+// @Success 200 {object} response_body.Entitlements
+// @Failure 400 {object} response_body.Error
+// @Router /v1/profile/entitlements [get]
+func (r *SubscriptionHandler) GetEntitlements(c *gin.Context) {
+	userId, err := r.middleware.GetUserId(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	entitlements, err := r.entitlementService.GetEntitlements(userId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewEntitlements(entitlements))
+}
+
+// GetSubscriptions Swagger Documentation
+// @Summary Get Subscriptions
+// @Security ApiKeyAuth
+// @Tags profile
+// @Description Get store subscriptions linked to profile
+// @Accept json
+// @Produce json
+// @Success 200 {object} []response_body.Subscription
+// @Failure 400 {object} response_body.Error
+// @Router /v1/profile/subscriptions [get]
+func (r *SubscriptionHandler) GetSubscriptions(c *gin.Context) {
+	userId, err := r.middleware.GetUserId(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	subscriptions, err := r.subscriptionService.GetSubscriptions(userId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewSubscriptions(subscriptions))
+}
+
+// VerifyPurchase Swagger Documentation
+// @Summary Verify Purchase
+// @Security ApiKeyAuth
+// @Tags profile
+// @Description Verify store purchase receipt and link subscription to profile. Acceptable providers: 'app_store', 'play', 'fake'.
+// @Description App Store receipt is base64 encoded app receipt, Google Play receipt is purchase token
+// @Accept json
+// @Produce json
+// @Param input body request_body.PurchaseReceipt true "Purchase receipt"
+// @Success 200 {object} response_body.Subscription
+// @Failure 400 {object} response_body.Error
+// @Router /v1/profile/subscriptions [post]
+func (r *SubscriptionHandler) VerifyPurchase(c *gin.Context) {
+	userId, err := r.middleware.GetUserId(c)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	var body request_body.PurchaseReceipt
+	if err := c.BindJSON(&body); err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+
+	subscription, err := r.subscriptionService.VerifyPurchase(c.Request.Context(), body.Entity(), userId)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewSubscription(subscription))
+}
+
+// HandleNotification Swagger Documentation
+// @Summary Handle Store Notification
+// @Tags subscriptions
+// @Description Webhook for App Store server notifications and Google Play real-time developer notifications.
+// @Description Subscription is verified by store after every notification
+// @Accept json
+// @Produce json
+// @Param provider path string true "Subscription provider"
+// @Param token query string true "Webhook token"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/subscriptions/webhooks/{provider} [post]
+func (r *SubscriptionHandler) HandleNotification(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+
+	err = r.subscriptionService.HandleNotification(c.Request.Context(), c.Param(ParamSubscriptionProvider),
+		c.Query(queryWebhookToken), body)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, message.SubscriptionNotificationHandled)
+}
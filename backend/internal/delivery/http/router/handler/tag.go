@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mephistolie/chefbook-server/internal/app/dependencies/service"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware/response"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/request_body"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body"
+	"github.com/mephistolie/chefbook-server/internal/delivery/http/presentation/response_body/message"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+)
+
+type TagHandler struct {
+	middleware middleware.AuthMiddleware
+	service    service.Tag
+}
+
+func NewTagHandler(middleware middleware.AuthMiddleware, service service.Tag) *TagHandler {
+	return &TagHandler{
+		middleware: middleware,
+		service:    service,
+	}
+}
+
+// GetTags Swagger Documentation
+// @Summary Get Tags
+// @Security ApiKeyAuth
+// @Tags tags
+// @Description Get available recipe tags: cuisines, courses, diets and occasions
+// @Accept json
+// @Produce json
+// @Success 200 {object} []response_body.Tag
+// @Failure 400 {object} response_body.Error
+// @Router /v1/tags [get]
+func (r *TagHandler) GetTags(c *gin.Context) {
+	tags, err := r.service.GetTags()
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Success(c, response_body.NewTags(tags))
+}
+
+// SetRecipeTags Swagger Documentation
+// @Summary Set Recipe Tags
+// @Security ApiKeyAuth
+// @Tags recipes
+// @Description Set tags for owned recipe. Maximum is 10
+// @Accept json
+// @Produce json
+// @Param recipe_id path int true "Recipe ID"
+// @Param tags body request_body.RecipeTagsInput true "Recipe tags"
+// @Success 200 {object} response_body.Message
+// @Failure 400 {object} response_body.Error
+// @Router /v1/recipes/{recipe_id}/tags [put]
+func (r *TagHandler) SetRecipeTags(c *gin.Context) {
+	userId, recipeId, err := getUserAndRecipeIds(c, r.middleware)
+	if err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	var body request_body.RecipeTagsInput
+	if err := c.BindJSON(&body); err != nil {
+		response.Failure(c, failure.InvalidBody)
+		return
+	}
+	if err := body.Validate(); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	if err := r.service.SetRecipeTags(recipeId, body.Tags, userId); err != nil {
+		response.Failure(c, err)
+		return
+	}
+
+	response.Message(c, message.TagsUpdated)
+}
@@ -1,16 +1,19 @@
 package router
 
 import (
+	"fmt"
 	"github.com/gin-gonic/gin"
 	_ "github.com/mephistolie/chefbook-server/docs"
 	"github.com/mephistolie/chefbook-server/internal/app/dependencies/service"
 	"github.com/mephistolie/chefbook-server/internal/config"
 	"github.com/mephistolie/chefbook-server/internal/delivery/http/middleware"
 	"github.com/mephistolie/chefbook-server/internal/delivery/http/router/v1"
+	"github.com/mephistolie/chefbook-server/internal/repository/local"
 	"github.com/mephistolie/chefbook-server/pkg/auth"
 	"github.com/mephistolie/chefbook-server/pkg/limiter"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"net/http"
 )
 
 type Router struct {
@@ -19,9 +22,9 @@ type Router struct {
 	fileMiddleware middleware.FileMiddleware
 }
 
-func NewRouter(services *service.Service, tokenManager auth.TokenManager) *Router {
+func NewRouter(services *service.Service, tokenManager auth.TokenManager, uploadLimits config.UploadsConfig) *Router {
 	authMiddleware := middleware.NewAuth(tokenManager)
-	fileMiddleware := middleware.NewFile()
+	fileMiddleware := middleware.NewFile(uploadLimits)
 	return &Router{
 		services:       services,
 		authMiddleware: *authMiddleware,
@@ -40,11 +43,25 @@ func (r *Router) Init(cfg *config.Config) *gin.Engine {
 		limiter.Limit(cfg.Limiter.RPS, cfg.Limiter.Burst, cfg.Limiter.TTL),
 	)
 
+	if cfg.Storage.Driver == config.StorageDriverLocal {
+		initLocalStorage(router, cfg.Storage.Local)
+	}
+
 	r.initAPI(router)
 
 	return router
 }
 
+// initLocalStorage serves files of local storage driver: public ones statically, private ones by signed links
+func initLocalStorage(router *gin.Engine, cfg config.LocalStorageConfig) {
+	files := local.NewLocalFileManager(cfg.Path, cfg.BaseUrl(), "", cfg.SigningKey)
+	publicRoute := fmt.Sprintf("%s/%s", cfg.Route, local.PublicRoute)
+	privateRoute := fmt.Sprintf("%s/%s", cfg.Route, local.PrivateRoute)
+
+	router.Static(publicRoute, files.PublicDir())
+	router.Any(privateRoute+"/*path", gin.WrapH(http.StripPrefix(privateRoute, local.NewFileServer(files))))
+}
+
 func (r *Router) initAPI(router *gin.Engine) {
 	handlerV1 := v1.NewV1Router(r.services, r.authMiddleware, r.fileMiddleware)
 	api := router.Group("/")
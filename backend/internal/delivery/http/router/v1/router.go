@@ -11,13 +11,24 @@ import (
 type v1Handler struct {
 	auth            *handler.AuthHandler
 	profile         *handler.ProfileHandler
+	follow          *handler.FollowHandler
 	encryption      *handler.EncryptionHandler
 	recipe          *handler.RecipeHandler
+	recommendation  *handler.RecommendationHandler
 	recipeOwnership *handler.OwnedRecipeHandler
 	recipePicture   *handler.RecipePictureHandler
 	recipeSharing   *handler.RecipeSharingHandler
+	recipeLink      *handler.RecipeLinkHandler
 	category        *handler.CategoriesHandler
+	collection      *handler.CollectionHandler
+	tag             *handler.TagHandler
+	allergen        *handler.AllergenHandler
+	nutrition       *handler.NutritionHandler
 	shoppingList    *handler.ShoppingListHandler
+	subscription    *handler.SubscriptionHandler
+	broccoin        *handler.BroccoinHandler
+	referral        *handler.ReferralHandler
+	achievement     *handler.AchievementHandler
 }
 
 type v1Router struct {
@@ -29,13 +40,24 @@ func NewV1Router(services *service.Service, authMiddleware middleware.AuthMiddle
 	routesHandler := v1Handler{
 		auth:            handler.NewAuthHandler(services.Auth),
 		profile:         handler.NewProfileHandler(authMiddleware, fileMiddleware, services.Profile),
+		follow:          handler.NewFollowHandler(authMiddleware, services.Follow),
 		encryption:      handler.NewEncryptionHandler(authMiddleware, fileMiddleware, services.Encryption),
 		recipe:          handler.NewRecipeCrudHandler(authMiddleware, services.Recipe),
+		recommendation:  handler.NewRecommendationHandler(authMiddleware, services.Recommendation),
 		recipeOwnership: handler.NewOwnedRecipeHandler(authMiddleware, services.RecipeOwnership),
 		recipePicture:   handler.NewRecipePictureHandler(authMiddleware, fileMiddleware, services.RecipePicture),
 		recipeSharing:   handler.NewRecipeSharingHandler(authMiddleware, fileMiddleware, services.RecipeSharing),
+		recipeLink:      handler.NewRecipeLinkHandler(authMiddleware, services.RecipeLink),
 		category:        handler.NewCategoryHandler(authMiddleware, services.Category),
+		collection:      handler.NewCollectionHandler(authMiddleware, services.Collection),
+		tag:             handler.NewTagHandler(authMiddleware, services.Tag),
+		allergen:        handler.NewAllergenHandler(services.Allergen),
+		nutrition:       handler.NewNutritionHandler(authMiddleware, services.Nutrition),
 		shoppingList:    handler.NewShoppingListHandler(authMiddleware, services.ShoppingList),
+		subscription:    handler.NewSubscriptionHandler(authMiddleware, services.Entitlement, services.Subscription),
+		broccoin:        handler.NewBroccoinHandler(authMiddleware, services.Broccoin),
+		referral:        handler.NewReferralHandler(authMiddleware, services.Referral),
+		achievement:     handler.NewAchievementHandler(authMiddleware, services.Achievement),
 	}
 
 	return &v1Router{
@@ -49,9 +71,17 @@ func (r *v1Router) Init(api *gin.RouterGroup) {
 	{
 		r.initAuthRoutes(v1)
 		r.initProfileRoutes(v1)
+		r.initUsersRoutes(v1)
+		r.initFeedRoutes(v1)
 		r.initRecipesRoutes(v1)
+		r.initSharedRoutes(v1)
 		r.initCategoriesRoutes(v1)
+		r.initCollectionsRoutes(v1)
+		r.initTagsRoutes(v1)
+		r.initAllergensRoutes(v1)
+		r.initNutritionRoutes(v1)
 		r.initShoppingListRoutes(v1)
+		r.initSubscriptionsRoutes(v1)
 	}
 }
 
@@ -72,12 +102,43 @@ func (r *v1Router) initProfileRoutes(api *gin.RouterGroup) {
 		profileGroup.GET("", r.handler.profile.GetProfileInfo)
 		profileGroup.PUT("/password", r.handler.profile.ChangePassword)
 		profileGroup.PUT("/username", r.handler.profile.SetUsername)
+		profileGroup.PUT("/bio", r.handler.profile.SetBio)
+		profileGroup.PUT("/allergens", r.handler.profile.SetExcludedAllergens)
 		profileGroup.POST("/avatar", r.handler.profile.UploadAvatar)
 		profileGroup.DELETE("/avatar", r.handler.profile.DeleteAvatar)
 
 		profileGroup.GET("/key", r.handler.encryption.GetUserKey)
 		profileGroup.POST("/key", r.handler.encryption.UploadUserKey)
 		profileGroup.DELETE("/key", r.handler.encryption.DeleteUserKey)
+		profileGroup.GET("/key-requests", r.handler.recipeSharing.GetUserKeyRequests)
+
+		profileGroup.GET("/entitlements", r.handler.subscription.GetEntitlements)
+		profileGroup.GET("/subscriptions", r.handler.subscription.GetSubscriptions)
+		profileGroup.POST("/subscriptions", r.handler.subscription.VerifyPurchase)
+
+		profileGroup.GET("/broccoins/transactions", r.handler.broccoin.GetBroccoinTransactions)
+		profileGroup.POST("/broccoins/spend", r.handler.broccoin.SpendBroccoins)
+
+		profileGroup.GET("/referrals", r.handler.referral.GetReferrals)
+		profileGroup.GET("/achievements", r.handler.achievement.GetAchievements)
+	}
+}
+
+func (r *v1Router) initUsersRoutes(api *gin.RouterGroup) {
+	usersGroup := api.Group("/users", r.middleware.CheckUserIdentity)
+	{
+		usersGroup.GET(fmt.Sprintf("/:%s", handler.ParamUserId), r.handler.profile.GetPublicProfile)
+		usersGroup.PUT(fmt.Sprintf("/:%s/follow", handler.ParamUserId), r.handler.follow.FollowUser)
+		usersGroup.DELETE(fmt.Sprintf("/:%s/follow", handler.ParamUserId), r.handler.follow.UnfollowUser)
+		usersGroup.GET(fmt.Sprintf("/:%s/followers", handler.ParamUserId), r.handler.follow.GetFollowers)
+		usersGroup.GET(fmt.Sprintf("/:%s/following", handler.ParamUserId), r.handler.follow.GetFollowing)
+	}
+}
+
+func (r *v1Router) initFeedRoutes(api *gin.RouterGroup) {
+	feedGroup := api.Group("/feed", r.middleware.CheckUserIdentity)
+	{
+		feedGroup.GET("", r.handler.recipe.GetFeed)
 	}
 }
 
@@ -86,6 +147,7 @@ func (r *v1Router) initRecipesRoutes(api *gin.RouterGroup) {
 	{
 		recipesGroup.GET("", r.handler.recipe.GetRecipes)
 		recipesGroup.GET("/random", r.handler.recipe.GetRandomRecipe)
+		recipesGroup.GET("/recommended", r.handler.recommendation.GetRecommendedRecipes)
 
 		recipesGroup.POST("", r.handler.recipeOwnership.CreateRecipe)
 		recipesGroup.GET(fmt.Sprintf("/:%s", handler.ParamRecipeId), r.handler.recipe.GetRecipe)
@@ -95,15 +157,27 @@ func (r *v1Router) initRecipesRoutes(api *gin.RouterGroup) {
 		recipesGroup.POST(fmt.Sprintf("/:%s/save", handler.ParamRecipeId), r.handler.recipe.AddRecipeToRecipeBook)
 		recipesGroup.DELETE(fmt.Sprintf("/:%s/save", handler.ParamRecipeId), r.handler.recipe.RemoveFromRecipeBook)
 		recipesGroup.PUT(fmt.Sprintf("/:%s/categories", handler.ParamRecipeId), r.handler.recipe.SetRecipeCategories)
+		recipesGroup.PUT(fmt.Sprintf("/:%s/tags", handler.ParamRecipeId), r.handler.tag.SetRecipeTags)
+		recipesGroup.GET(fmt.Sprintf("/:%s/nutrition", handler.ParamRecipeId), r.handler.nutrition.GetRecipeNutrition)
 		recipesGroup.PUT(fmt.Sprintf("/:%s/favourite", handler.ParamRecipeId), r.handler.recipe.MarkRecipeFavourite)
 		recipesGroup.DELETE(fmt.Sprintf("/:%s/favourite", handler.ParamRecipeId), r.handler.recipe.UnmarkRecipeFavourite)
 		recipesGroup.PUT(fmt.Sprintf("/:%s/likes", handler.ParamRecipeId), r.handler.recipe.LikeRecipe)
 		recipesGroup.DELETE(fmt.Sprintf("/:%s/likes", handler.ParamRecipeId), r.handler.recipe.UnlikeRecipe)
+		recipesGroup.POST(fmt.Sprintf("/:%s/cooked", handler.ParamRecipeId), r.handler.recipe.MarkRecipeCooked)
 
 		recipesGroup.GET(fmt.Sprintf("/:%s/pictures", handler.ParamRecipeId), r.handler.recipePicture.GetRecipePictures)
 		recipesGroup.POST(fmt.Sprintf("/:%s/pictures", handler.ParamRecipeId), r.handler.recipePicture.UploadRecipePicture)
+		recipesGroup.POST(fmt.Sprintf("/:%s/pictures/upload-url", handler.ParamRecipeId), r.handler.recipePicture.GetRecipePictureUploadLink)
+		recipesGroup.POST(fmt.Sprintf("/:%s/pictures/upload-url/confirm", handler.ParamRecipeId), r.handler.recipePicture.ConfirmRecipePictureUpload)
+		recipesGroup.PUT(fmt.Sprintf("/:%s/pictures/order", handler.ParamRecipeId), r.handler.recipePicture.SetRecipePicturesOrder)
+		recipesGroup.GET(fmt.Sprintf("/:%s/pictures/:%s", handler.ParamRecipeId, handler.ParamPictureId), r.handler.recipePicture.GetRecipePicture)
+		recipesGroup.PUT(fmt.Sprintf("/:%s/pictures/:%s", handler.ParamRecipeId, handler.ParamPictureId), r.handler.recipePicture.UpdateRecipePicture)
 		recipesGroup.DELETE(fmt.Sprintf("/:%s/pictures/:%s", handler.ParamRecipeId, handler.ParamPictureId), r.handler.recipePicture.DeleteRecipePicture)
 
+		recipesGroup.GET(fmt.Sprintf("/:%s/links", handler.ParamRecipeId), r.handler.recipeLink.GetRecipeLinks)
+		recipesGroup.POST(fmt.Sprintf("/:%s/links", handler.ParamRecipeId), r.handler.recipeLink.CreateRecipeLink)
+		recipesGroup.DELETE(fmt.Sprintf("/:%s/links/:%s", handler.ParamRecipeId, handler.ParamLinkId), r.handler.recipeLink.DeleteRecipeLink)
+
 		recipesGroup.GET(fmt.Sprintf("/:%s/key", handler.ParamRecipeId), r.handler.encryption.GetRecipeKey)
 		recipesGroup.POST(fmt.Sprintf("/:%s/key", handler.ParamRecipeId), r.handler.encryption.UploadRecipeKey)
 		recipesGroup.DELETE(fmt.Sprintf("/:%s/key", handler.ParamRecipeId), r.handler.encryption.DeleteRecipeKey)
@@ -116,6 +190,19 @@ func (r *v1Router) initRecipesRoutes(api *gin.RouterGroup) {
 		recipesGroup.PUT(fmt.Sprintf("/:%s/users/:%s/key", handler.ParamRecipeId, handler.ParamUserId), r.handler.recipeSharing.SetOwnerPrivateKey)
 		recipesGroup.DELETE(fmt.Sprintf("/:%s/users/:%s/key", handler.ParamRecipeId, handler.ParamUserId), r.handler.recipeSharing.DeleteOwnerPrivateKey)
 		recipesGroup.DELETE(fmt.Sprintf("/:%s/users/:%s", handler.ParamRecipeId, handler.ParamUserId), r.handler.recipeSharing.DeleteUserAccess)
+
+		recipesGroup.GET(fmt.Sprintf("/:%s/key-requests", handler.ParamRecipeId), r.handler.recipeSharing.GetRecipeKeyRequests)
+		recipesGroup.POST(fmt.Sprintf("/:%s/key-requests", handler.ParamRecipeId), r.handler.recipeSharing.RequestRecipeKey)
+		recipesGroup.DELETE(fmt.Sprintf("/:%s/key-requests/:%s", handler.ParamRecipeId, handler.ParamKeyRequestId), r.handler.recipeSharing.CancelKeyRequest)
+		recipesGroup.POST(fmt.Sprintf("/:%s/key-requests/:%s/approve", handler.ParamRecipeId, handler.ParamKeyRequestId), r.handler.recipeSharing.ApproveKeyRequest)
+		recipesGroup.POST(fmt.Sprintf("/:%s/key-requests/:%s/reject", handler.ParamRecipeId, handler.ParamKeyRequestId), r.handler.recipeSharing.RejectKeyRequest)
+	}
+}
+
+func (r *v1Router) initSharedRoutes(api *gin.RouterGroup) {
+	sharedGroup := api.Group("/shared", r.middleware.CheckUserIdentity)
+	{
+		sharedGroup.GET(fmt.Sprintf("/:%s", handler.ParamLinkToken), r.handler.recipe.GetSharedRecipe)
 	}
 }
 
@@ -124,9 +211,49 @@ func (r *v1Router) initCategoriesRoutes(api *gin.RouterGroup) {
 	{
 		categoriesGroup.GET("", r.handler.category.GetCategories)
 		categoriesGroup.POST("", r.handler.category.CreateCategory)
+		categoriesGroup.PUT("/order", r.handler.category.SetCategoriesOrder)
 		categoriesGroup.GET(fmt.Sprintf("/:%s", handler.ParamCategoryId), r.handler.category.GetCategory)
 		categoriesGroup.PUT(fmt.Sprintf("/:%s", handler.ParamCategoryId), r.handler.category.UpdateCategory)
 		categoriesGroup.DELETE(fmt.Sprintf("/:%s", handler.ParamCategoryId), r.handler.category.DeleteCategory)
+		categoriesGroup.POST(fmt.Sprintf("/:%s/collection", handler.ParamCategoryId), r.handler.collection.ConvertCategoryToCollection)
+	}
+}
+
+func (r *v1Router) initCollectionsRoutes(api *gin.RouterGroup) {
+	collectionsGroup := api.Group("/collections", r.middleware.CheckUserIdentity)
+	{
+		collectionsGroup.GET("", r.handler.collection.GetCollections)
+		collectionsGroup.POST("", r.handler.collection.CreateCollection)
+		collectionsGroup.GET(fmt.Sprintf("/:%s", handler.ParamCollectionId), r.handler.collection.GetCollection)
+		collectionsGroup.PUT(fmt.Sprintf("/:%s", handler.ParamCollectionId), r.handler.collection.UpdateCollection)
+		collectionsGroup.DELETE(fmt.Sprintf("/:%s", handler.ParamCollectionId), r.handler.collection.DeleteCollection)
+
+		collectionsGroup.PUT(fmt.Sprintf("/:%s/recipes", handler.ParamCollectionId), r.handler.collection.SetCollectionRecipes)
+		collectionsGroup.PUT(fmt.Sprintf("/:%s/follow", handler.ParamCollectionId), r.handler.collection.FollowCollection)
+		collectionsGroup.DELETE(fmt.Sprintf("/:%s/follow", handler.ParamCollectionId), r.handler.collection.UnfollowCollection)
+		collectionsGroup.POST(fmt.Sprintf("/:%s/save", handler.ParamCollectionId), r.handler.collection.SaveCollection)
+		collectionsGroup.DELETE(fmt.Sprintf("/:%s/save", handler.ParamCollectionId), r.handler.collection.RemoveCollectionFromSaved)
+	}
+}
+
+func (r *v1Router) initTagsRoutes(api *gin.RouterGroup) {
+	tagsGroup := api.Group("/tags", r.middleware.CheckUserIdentity)
+	{
+		tagsGroup.GET("", r.handler.tag.GetTags)
+	}
+}
+
+func (r *v1Router) initAllergensRoutes(api *gin.RouterGroup) {
+	allergensGroup := api.Group("/allergens", r.middleware.CheckUserIdentity)
+	{
+		allergensGroup.GET("", r.handler.allergen.GetAllergens)
+	}
+}
+
+func (r *v1Router) initNutritionRoutes(api *gin.RouterGroup) {
+	nutritionGroup := api.Group("/nutrition", r.middleware.CheckUserIdentity)
+	{
+		nutritionGroup.POST("", r.handler.nutrition.CalculateNutrition)
 	}
 }
 
@@ -138,3 +265,10 @@ func (r *v1Router) initShoppingListRoutes(api *gin.RouterGroup) {
 		shoppingListGroup.PUT("", r.handler.shoppingList.AddToShoppingList)
 	}
 }
+
+func (r *v1Router) initSubscriptionsRoutes(api *gin.RouterGroup) {
+	subscriptionsGroup := api.Group("/subscriptions")
+	{
+		subscriptionsGroup.POST(fmt.Sprintf("/webhooks/:%s", handler.ParamSubscriptionProvider), r.handler.subscription.HandleNotification)
+	}
+}
@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+const (
+	AchievementFirstPublicRecipe = "first_public_recipe"
+	AchievementFirstRecipeSaved  = "first_recipe_saved"
+	AchievementLikesReceived     = "likes_received_100"
+	AchievementRecipesCooked     = "recipes_cooked_10"
+	AchievementCookingStreak     = "cooking_streak_7"
+)
+
+// Achievement events are domain actions after which achievements of user are evaluated
+const (
+	AchievementEventRecipePublished = "recipe_published"
+	AchievementEventRecipeLiked     = "recipe_liked"
+	AchievementEventRecipeSaved     = "recipe_saved"
+	AchievementEventRecipeCooked    = "recipe_cooked"
+)
+
+type Achievement struct {
+	Code            string
+	Progress        int
+	Target          int
+	Reward          int
+	UnlockTimestamp *time.Time
+}
+
+type UnlockedAchievement struct {
+	Code            string
+	UnlockTimestamp time.Time
+}
+
+// CookingStreak is count of consecutive days with cooked recipes. Current streak isn't broken until the end of next day
+type CookingStreak struct {
+	Current int
+	Longest int
+}
+
+type Achievements struct {
+	Achievements  []Achievement
+	CookingStreak CookingStreak
+}
@@ -0,0 +1,7 @@
+package entity
+
+const (
+	AllergensStatusSafe     = "safe"
+	AllergensStatusContains = "contains"
+	AllergensStatusUnknown  = "unknown"
+)
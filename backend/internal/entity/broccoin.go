@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+const (
+	BroccoinReasonOpeningBalance  = "opening_balance"
+	BroccoinReasonMigrationBonus  = "migration_bonus"
+	BroccoinReasonLikeReceived    = "like_received"
+	BroccoinReasonDailyLogin      = "daily_login"
+	BroccoinReasonPremiumPurchase = "premium_purchase"
+	BroccoinReasonReferral        = "referral"
+	BroccoinReasonAchievement     = "achievement"
+)
+
+// BroccoinTransaction is credit (positive amount) or debit (negative amount) of user broccoins.
+// Balance is user balance right after transaction
+type BroccoinTransaction struct {
+	Id                int
+	UserId            int
+	Amount            int
+	Balance           int
+	Reason            string
+	Reference         *string
+	CreationTimestamp time.Time
+}
+
+// BroccoinTransactionInput is transaction to apply. Transactions with the same user, reason and reference are applied once
+type BroccoinTransactionInput struct {
+	UserId    int
+	Amount    int
+	Reason    string
+	Reference *string
+}
+
+type BroccoinTransactionsQuery struct {
+	Page     int
+	PageSize int
+}
+
+type BroccoinParams struct {
+	LikeReward       int
+	DailyLoginReward int
+	PremiumDayPrice  int
+}
+
+type PremiumPurchase struct {
+	Transaction    BroccoinTransaction
+	PremiumEndDate time.Time
+}
@@ -1,13 +1,22 @@
 package entity
 
 type Category struct {
-	Id     int
-	Name   string
-	Cover  *string
-	UserId int
+	Id       int
+	Name     string
+	Cover    *string
+	ParentId *int
+	Position int
+	UserId   int
 }
 
 type CategoryInput struct {
-	Name  string  `json:"name"`
-	Cover *string `json:"cover" binding:"max=20"`
-}
\ No newline at end of file
+	Name     string  `json:"name"`
+	Cover    *string `json:"cover" binding:"max=20"`
+	ParentId *int    `json:"parent_id"`
+}
+
+// CategoryPosition places category under parent. Categories are ordered by positions among siblings
+type CategoryPosition struct {
+	Id       int
+	ParentId *int
+}
@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+const (
+	SortingFollowers = "followers"
+)
+
+type Collection struct {
+	Id          int
+	Name        string
+	Description *string
+	OwnerId     int
+	OwnerName   string
+	Owned       bool
+	Visibility  string
+
+	RecipesCount int
+	Followers    int
+	IsFollowed   bool
+	IsSaved      bool
+
+	CreationTimestamp time.Time
+	UpdateTimestamp   time.Time
+
+	Recipes []RecipeInfo
+}
+
+type CollectionInput struct {
+	Name        string
+	Description *string
+	Visibility  string
+}
+
+type CollectionsQuery struct {
+	AuthorId *int
+	Saved    bool
+	Followed bool
+	Search   *string
+	Page     int
+	PageSize int
+	SortBy   string
+}
@@ -5,9 +5,10 @@ const (
 )
 
 type CookingItem struct {
-	Text     string
-	Link     *string
-	Time     *int16
-	Pictures *[]string
-	Type     string
+	Text       string
+	Link       *string
+	Time       *int16
+	Pictures   *[]string
+	PictureIds *[]int
+	Type       string
 }
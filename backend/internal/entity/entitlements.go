@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// TierEntitlements are features and limits unlocked by user tier
+type TierEntitlements struct {
+	Encryption     bool
+	StorageQuota   StorageQuota
+	MaxCollections int
+}
+
+// Entitlements are features and limits available to user at the moment
+type Entitlements struct {
+	IsPremium      bool
+	PremiumEndDate *time.Time
+	TierEntitlements
+}
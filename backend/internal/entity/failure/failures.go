@@ -11,9 +11,17 @@ var (
 	InvalidBody         = errors.New("invalid request body")
 	InvalidFileSize     = errors.New("invalid file size")
 	UnsupportedFileType = errors.New("unsupported file type")
+	UnsupportedImage    = errors.New("HEIC, HEIF and AVIF pictures aren't supported; convert picture to JPEG, PNG or WebP")
+	TooLargeImage       = errors.New("image resolution is too high")
 	UnableUploadFile    = errors.New("unable to upload file")
 	UnableDeleteFile    = errors.New("unable delete file")
+	PictureNotFound     = errors.New("picture not found")
+	TooLongCaption      = errors.New("too long picture caption; maximum is 500 symbols")
+	InvalidPictureOrder = errors.New("pictures order must contain every recipe picture exactly once")
+	UploadNotFound      = errors.New("uploaded file not found; upload file by link before confirmation")
+	QuotaExceeded       = errors.New("storage quota exceeded; delete some files or get premium")
 	AccessDenied        = errors.New("access denied")
+	PremiumRequired     = errors.New("feature is available only for premium users")
 
 	UnableSendEmail       = errors.New("unable to send email")
 	UserAlreadyExists     = errors.New("user with such email already exists")
@@ -37,8 +45,16 @@ var (
 
 	UnableSetAvatar = errors.New("unable set avatar")
 
+	UnableFollowYourself = errors.New("unable to follow yourself")
+	UnableFollowUser     = errors.New("unable to follow user")
+
 	NoKey = errors.New("encrypted key not found")
 
+	RecipeNotEncrypted       = errors.New("recipe isn't encrypted")
+	KeyRequestAlreadyExists  = errors.New("key request for this recipe is already pending")
+	KeyRequestNotFound       = errors.New("key request not found")
+	KeyRequestAlreadyHandled = errors.New("key request is already approved, rejected or cancelled")
+
 	EmptyRecipeName           = errors.New("empty recipe name")
 	EmptyIngredients          = errors.New("no ingredients")
 	EmptyCooking              = errors.New("no cooking")
@@ -48,6 +64,7 @@ var (
 	InvalidIngredientItemType = errors.New("invalid ingredient type")
 	InvalidCookingItemType    = errors.New("invalid cooking step type")
 	InvalidEncryptionType     = errors.New("recipe input doesn't match its encryption state")
+	InvalidCookingPicture     = errors.New("cooking step references picture which isn't in recipe gallery")
 
 	NotOwner              = errors.New("you aren't owner of this recipe")
 	UnableCreateRecipe    = errors.New("unable to create recipe")
@@ -57,8 +74,42 @@ var (
 	RecipeNotInRecipeBook = errors.New("recipe isn't in recipe book")
 	UnableGetRandomRecipe = errors.New("unable to found random recipe with request parameters")
 
-	UnableAddCategory = errors.New("unable to add category")
-	CategoryNotFound  = errors.New("category not found")
+	RecipeLinkNotFound = errors.New("share link not found or expired")
+
+	InvalidTag  = errors.New("invalid tag")
+	TooManyTags = errors.New("too many tags; maximum is 10")
+	TagNotFound = errors.New("tag not found")
+
+	InvalidAllergen = errors.New("unsupported allergen")
+
+	FoodNotFound             = errors.New("food not found")
+	UnableCalculateNutrition = errors.New("unable to calculate nutrition of encrypted recipe")
+	InvalidFoodsFile         = errors.New("invalid foods file")
+
+	UnableAddCategory      = errors.New("unable to add category")
+	CategoryNotFound       = errors.New("category not found")
+	CategoryCycle          = errors.New("category can't be nested into itself or its subcategory")
+	InvalidCategoriesOrder = errors.New("categories order must contain every category exactly once")
+
+	UnableAddCollection            = errors.New("unable to add collection")
+	CollectionNotFound             = errors.New("collection not found")
+	TooManyCollectionRecipes       = errors.New("too many recipes in collection; maximum is 500")
+	RecipeUnavailableForCollection = errors.New("only public recipes and your own shared recipes can be added to collection")
+	UnableFollowOwnCollection      = errors.New("unable to follow or save your own collection")
+	TooManyCollections             = errors.New("collections limit reached; delete some collections or get premium")
+
+	UnsupportedSubscriptionProvider = errors.New("unsupported subscription provider")
+	InvalidReceipt                  = errors.New("purchase receipt is invalid or doesn't contain subscription")
+	UnableVerifyReceipt             = errors.New("unable to verify purchase receipt; try again later")
+	SubscriptionOwnedByAnotherUser  = errors.New("subscription is already linked to another profile")
+	SubscriptionNotFound            = errors.New("subscription not found")
+
+	NotEnoughBroccoins        = errors.New("not enough broccoins")
+	BroccoinTransactionExists = errors.New("broccoin transaction has already been applied")
+
+	InvalidReferralCode = errors.New("invalid referral code")
+	ReferralCodeExists  = errors.New("referral code is already taken")
+	ReferralNotFound    = errors.New("pending referral not found")
 
 	ShoppingListNotFound = errors.New("shopping list not found")
 )
@@ -1,17 +1,36 @@
 package entity
 
 import (
-	"bytes"
+	"io"
 )
 
+const (
+	ContentTypeJpeg = "image/jpeg"
+	ContentTypePng  = "image/png"
+	ContentTypeWebP = "image/webp"
+	ContentTypeHeic = "image/heic"
+	ContentTypeHeif = "image/heif"
+	ContentTypeAvif = "image/avif"
+)
+
+// imageTypes are formats which can be decoded by image processor
 var imageTypes = map[string]interface{}{
-	"image/jpeg": nil,
-	"image/png":  nil,
+	ContentTypeJpeg: nil,
+	ContentTypePng:  nil,
+	ContentTypeWebP: nil,
+}
+
+// undecodableImageTypes are recognized picture formats which image processor can't decode yet
+var undecodableImageTypes = map[string]interface{}{
+	ContentTypeHeic: nil,
+	ContentTypeHeif: nil,
+	ContentTypeAvif: nil,
 }
 
+// MultipartFile is uploaded file. Size is -1 when file is streamed and its size is unknown
 type MultipartFile struct {
 	Name        string
-	Content     *bytes.Reader
+	Content     io.Reader
 	Size        int64
 	ContentType string
 }
@@ -19,4 +38,9 @@ type MultipartFile struct {
 func (h *MultipartFile) IsImage() bool {
 	_, ok := imageTypes[h.ContentType]
 	return ok
-}
\ No newline at end of file
+}
+
+func (h *MultipartFile) IsUndecodableImage() bool {
+	_, ok := undecodableImageTypes[h.ContentType]
+	return ok
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+const (
+	KeyRequestPending   = "pending"
+	KeyRequestApproved  = "approved"
+	KeyRequestRejected  = "rejected"
+	KeyRequestCancelled = "cancelled"
+)
+
+type RecipeKeyRequest struct {
+	Id                int
+	RecipeId          int
+	RecipeName        string
+	UserId            int
+	Username          *string
+	UserKey           string
+	RecipeKey         *string
+	Status            string
+	CreationTimestamp time.Time
+	UpdateTimestamp   time.Time
+}
+
+// RecipeUserKey is recipe key encrypted for user. Keys exchanged manually are stored as links to files,
+// keys from approved requests are stored in requests as is
+type RecipeUserKey struct {
+	Link        *string
+	ApprovedKey *string
+}
+
+type KeyRequestResolvedEmailInput struct {
+	Email      string
+	RecipeName string
+	IsApproved bool
+}
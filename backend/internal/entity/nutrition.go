@@ -0,0 +1,50 @@
+package entity
+
+// Food nutrients are specified per 100 grams
+type Food struct {
+	Id            int
+	Source        string
+	ExternalId    string
+	Name          string
+	Language      string
+	Calories      float64
+	Protein       float64
+	Fats          float64
+	Carbohydrates float64
+}
+
+type FoodMatch struct {
+	Food       Food
+	Confidence float64
+}
+
+type FoodsCsvFormat struct {
+	Source              string
+	Language            string
+	Delimiter           rune
+	IdColumn            string
+	NameColumn          string
+	CaloriesColumn      string
+	ProteinColumn       string
+	FatsColumn          string
+	CarbohydratesColumn string
+}
+
+type IngredientNutrition struct {
+	Text       string
+	Food       *string
+	Grams      *float64
+	Calories   *float64
+	Confidence float64
+}
+
+type Nutrition struct {
+	Calories       *int16
+	Macronutrients Macronutrients
+	Confidence     float64
+	Ingredients    []IngredientNutrition
+}
+
+type NutritionParams struct {
+	MinConfidence float64
+}
@@ -8,13 +8,17 @@ type Profile struct {
 	Id                int
 	Email             string
 	Username          *string
+	Bio               *string
 	CreationTimestamp time.Time
 	Password          string
 	IsActivated       bool
 	Avatar            *string
+	AvatarThumbnails  map[int]string
 	PremiumEndDate    *time.Time
 	Broccoins         int
 	IsBlocked         bool
+	ExcludedAllergens []string
+	StorageUsage      *StorageUsage
 }
 
 type ProfileInfo struct {
@@ -25,3 +29,17 @@ type ProfileInfo struct {
 	PremiumEndDate    *time.Time
 	Broccoins         int
 }
+
+type PublicProfile struct {
+	Id                int
+	Username          *string
+	Bio               *string
+	CreationTimestamp time.Time
+	Avatar            *string
+	AvatarThumbnails  map[int]string
+	RecipesCount      int
+	LikesCount        int
+	FollowersCount    int
+	FollowingCount    int
+	IsFollowed        bool
+}
@@ -34,6 +34,8 @@ type Recipe struct {
 
 	Ingredients []IngredientItem
 	Cooking     []CookingItem
+
+	Allergens *[]string
 }
 
 type UserRecipe struct {
@@ -49,10 +51,13 @@ type UserRecipe struct {
 	Description *string
 	Preview     *string
 
+	PreviewThumbnails map[int]string
+
 	CreationTimestamp time.Time
 	UpdateTimestamp   time.Time
 
 	Categories  []Category
+	Tags        []Tag
 	IsFavourite bool
 	IsLiked     bool
 
@@ -64,6 +69,8 @@ type UserRecipe struct {
 
 	Ingredients []IngredientItem
 	Cooking     []CookingItem
+
+	Allergens *[]string
 }
 
 type RecipeInfo struct {
@@ -78,10 +85,13 @@ type RecipeInfo struct {
 	Language    string
 	Preview     *string
 
+	PreviewThumbnails map[int]string
+
 	CreationTimestamp time.Time
 	UpdateTimestamp   time.Time
 
 	Categories  []Category
+	Tags        []Tag
 	IsFavourite bool
 	IsLiked     bool
 
@@ -89,6 +99,8 @@ type RecipeInfo struct {
 	Time     *int16
 
 	Calories *int16
+
+	Allergens *[]string
 }
 
 type RecipeInput struct {
@@ -107,4 +119,6 @@ type RecipeInput struct {
 
 	Ingredients []IngredientItem
 	Cooking     []CookingItem
+
+	Allergens *[]string
 }
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+type RecipeLink struct {
+	Id                int
+	Token             string
+	RecipeId          int
+	ExpiresAt         *time.Time
+	CreationTimestamp time.Time
+}
+
+type RecipeLinkInput struct {
+	ExpiresAt *time.Time
+}
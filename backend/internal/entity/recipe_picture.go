@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+type RecipePicture struct {
+	Id                int
+	RecipeId          int
+	Link              string
+	Thumbnails        map[int]string
+	Caption           *string
+	Position          int
+	IsPreview         bool
+	CreationTimestamp time.Time
+}
+
+type RecipePictureInput struct {
+	Caption   *string
+	IsPreview bool
+}
@@ -4,23 +4,34 @@ const (
 	SortingCreationTimestamp = "creation_timestamp"
 	SortingUpdateTimestamp   = "update_timestamp"
 	SortingLikes             = "likes"
+	SortingTrending          = "trending"
 	SortingTime              = "time"
 	SortingServings          = "servings"
 	SortingCalories          = "calories"
 )
 
 type RecipesQuery struct {
-	AuthorId    *int
-	Saved       bool
-	Search      *string
-	Page        int
-	PageSize    int
-	SortBy      string
-	MinTime     *int
-	MaxTime     *int
-	MinCalories *int
-	MaxCalories *int
-	MinServings *int
-	MaxServings *int
-	Languages   *[]string
+	AuthorId *int
+	Saved    bool
+	Followed bool
+	// FollowedCollections adds recipes of followed collections to recipes of followed authors
+	FollowedCollections bool
+	Search              *string
+	Page                int
+	PageSize            int
+	SortBy              string
+	MinTime             *int
+	MaxTime             *int
+	MinCalories         *int
+	MaxCalories         *int
+	MinServings         *int
+	MaxServings         *int
+	Languages           *[]string
+	Tags                *[]string
+	ExcludeTags         *[]string
+
+	CategoryId           *int
+	IncludeSubcategories bool
+
+	ExcludedAllergens []string
 }
@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+const (
+	ReferralStatusPending      = "pending"
+	ReferralStatusRewarded     = "rewarded"
+	ReferralStatusLimitReached = "limit_reached"
+)
+
+type Referral struct {
+	ReferrerId          int
+	InviteeId           int
+	InviteeName         *string
+	Status              string
+	CreationTimestamp   time.Time
+	ActivationTimestamp *time.Time
+}
+
+type Referrals struct {
+	Code      string
+	Referrals []Referral
+}
+
+// ReferralReward is reward credited after invitee activation
+type ReferralReward struct {
+	Broccoins   int
+	PremiumDays int
+}
+
+// ReferralLimits protect from abuse. Referral activated over reward limits isn't rewarded on both sides,
+// and referrer can't have too many pending invitees. Zero limit disables check
+type ReferralLimits struct {
+	MaxRewards        int
+	MaxDailyRewards   int
+	MaxPendingInvites int
+}
+
+type ReferralParams struct {
+	ReferrerReward ReferralReward
+	InviteeReward  ReferralReward
+	Limits         ReferralLimits
+}
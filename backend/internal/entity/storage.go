@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+type StoredFile struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// StorageReferences are object keys or legacy links of files referenced by database
+type StorageReferences struct {
+	Files []string
+}
+
+type GarbageCollectionReport struct {
+	DryRun         bool
+	ScannedFiles   int
+	OrphanedFiles  []string
+	DeletedFiles   int
+	ReclaimedBytes int64
+}
+
+// UserFile is stored file counted in storage usage of its owner. Size of picture includes its thumbnails
+type UserFile struct {
+	Key      string
+	UserId   int
+	RecipeId *int
+	Size     int64
+}
+
+type StorageQuota struct {
+	MaxBytes int64
+	MaxFiles int
+}
+
+type StorageUsage struct {
+	Bytes    int64
+	Files    int
+	MaxBytes int64
+	MaxFiles int
+}
@@ -0,0 +1,65 @@
+package entity
+
+import "time"
+
+const (
+	SubscriptionProviderAppStore = "app_store"
+	SubscriptionProviderPlay     = "play"
+	SubscriptionProviderFake     = "fake"
+
+	SubscriptionStatusActive      = "active"
+	SubscriptionStatusGracePeriod = "grace_period"
+	SubscriptionStatusExpired     = "expired"
+	SubscriptionStatusRevoked     = "revoked"
+)
+
+type Subscription struct {
+	Id                    int
+	UserId                int
+	Provider              string
+	ProductId             string
+	OriginalTransactionId string
+	Receipt               string
+	Status                string
+	ExpiresAt             time.Time
+	AutoRenew             bool
+	CreationTimestamp     time.Time
+	UpdateTimestamp       time.Time
+}
+
+// SubscriptionState is subscription state verified by store
+type SubscriptionState struct {
+	ProductId             string
+	OriginalTransactionId string
+	Receipt               string
+	Status                string
+	ExpiresAt             time.Time
+	AutoRenew             bool
+}
+
+type PurchaseReceipt struct {
+	Provider  string
+	ProductId string
+	Receipt   string
+}
+
+// SubscriptionNotification identifies subscription changed by store. Notification content isn't trusted,
+// subscription state is always verified by store
+type SubscriptionNotification struct {
+	OriginalTransactionId *string
+	ProductId             *string
+	Receipt               *string
+}
+
+// IsEntitled reports whether subscription grants premium
+func (s Subscription) IsEntitled() bool {
+	return s.Status == SubscriptionStatusActive || s.Status == SubscriptionStatusGracePeriod
+}
+
+// EntitledUntil returns end of premium granted by subscription. Time passed is returned for not entitled subscription
+func (s Subscription) EntitledUntil(now time.Time) time.Time {
+	if s.IsEntitled() && s.ExpiresAt.After(now) {
+		return s.ExpiresAt
+	}
+	return now
+}
@@ -0,0 +1,18 @@
+package entity
+
+const (
+	TagTypeCuisine  = "cuisine"
+	TagTypeCourse   = "course"
+	TagTypeDiet     = "diet"
+	TagTypeOccasion = "occasion"
+)
+
+type Tag struct {
+	Id   string
+	Type string
+}
+
+type TagFacet struct {
+	Tag
+	Count int
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+type TrendingParams struct {
+	LikeWeight float64
+	SaveWeight float64
+	ViewWeight float64
+	HalfLife   time.Duration
+	Window     time.Duration
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+type UploadInput struct {
+	ContentType string
+	Size        int64
+}
+
+// UploadLink is presigned link for direct upload to storage. Headers must be sent with upload request as is
+type UploadLink struct {
+	UploadId  string
+	Url       string
+	Headers   map[string]string
+	ExpiresAt time.Time
+}
@@ -0,0 +1,415 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	usersDir      = "users"
+	avatarsDir    = "avatars"
+	keysDir       = "keys"
+	recipesDir    = "recipes"
+	imagesDir     = "images"
+	thumbnailsDir = "thumbnails"
+	uploadsDir    = "uploads"
+
+	// PublicRoute and PrivateRoute are subroutes of storage route which serve public and signed files
+	PublicRoute  = "public"
+	PrivateRoute = "private"
+
+	contentType   = "Content-Type"
+	contentLength = "Content-Length"
+
+	dirPermissions  = 0755
+	filePermissions = 0644
+	sniffLength     = 512
+)
+
+// LocalFileManager stores files on disk. Public files are served by static route, private ones by signed links.
+// File url doesn't depend on its access, like in S3: private files just aren't served by public route
+type LocalFileManager struct {
+	root      string
+	baseUrl   string
+	publicUrl string
+	signer    *signer
+}
+
+// NewLocalFileManager creates local file manager. Public links are built with publicUrl, e.g. CDN host,
+// or with public route of storage if it's empty
+func NewLocalFileManager(root, baseUrl, publicUrl string, signingKey string) *LocalFileManager {
+	baseUrl = strings.TrimSuffix(baseUrl, "/")
+	if publicUrl == "" {
+		publicUrl = fmt.Sprintf("%s/%s", baseUrl, PublicRoute)
+	}
+	return &LocalFileManager{
+		root:      root,
+		baseUrl:   baseUrl,
+		publicUrl: strings.TrimSuffix(publicUrl, "/"),
+		signer:    newSigner(signingKey),
+	}
+}
+
+func (r *LocalFileManager) PublicDir() string {
+	return filepath.Join(r.root, PublicRoute)
+}
+
+// GetFiles lists public and private files of storage
+func (r *LocalFileManager) GetFiles(_ context.Context) ([]entity.StoredFile, error) {
+	var files []entity.StoredFile
+	for _, access := range []string{PublicRoute, PrivateRoute} {
+		root := filepath.Join(r.root, access)
+		err := filepath.WalkDir(root, func(filePath string, entry os.DirEntry, err error) error {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			relativePath, err := filepath.Rel(root, filePath)
+			if err != nil {
+				return err
+			}
+			files = append(files, entity.StoredFile{
+				Key:          filepath.ToSlash(relativePath),
+				Size:         info.Size(),
+				LastModified: info.ModTime(),
+			})
+			return nil
+		})
+		if err != nil {
+			return []entity.StoredFile{}, failure.Unknown
+		}
+	}
+	return files, nil
+}
+
+func (r *LocalFileManager) UploadAvatar(_ context.Context, userId int, input entity.MultipartFile) (string, error) {
+	filePath := fmt.Sprintf("%s/%d/%s/%s", usersDir, userId, avatarsDir, input.Name)
+	if err := r.writeFile(PublicRoute, filePath, input.Content); err != nil {
+		return "", err
+	}
+	return filePath, nil
+}
+
+func (r *LocalFileManager) UploadUserKey(_ context.Context, userId int, input entity.MultipartFile) (string, error) {
+	filePath := fmt.Sprintf("%s/%d/%s/%s", usersDir, userId, keysDir, input.Name)
+	if err := r.writeFile(PrivateRoute, filePath, input.Content); err != nil {
+		return "", err
+	}
+	return filePath, nil
+}
+
+func (r *LocalFileManager) GetRecipePictures(_ context.Context, recipeId int) []string {
+	picturesPath := fmt.Sprintf("%s/%d/%s", recipesDir, recipeId, imagesDir)
+	var objects []string
+	for _, access := range []string{PublicRoute, PrivateRoute} {
+		entries, err := os.ReadDir(r.getPath(access, picturesPath))
+		if err != nil {
+			continue
+		}
+		for _, file := range entries {
+			if !file.IsDir() {
+				objects = append(objects, fmt.Sprintf("%s/%s", picturesPath, file.Name()))
+			}
+		}
+	}
+	return objects
+}
+
+func (r *LocalFileManager) UploadRecipePicture(_ context.Context, recipeId int, input entity.MultipartFile, isPublic bool) (string, error) {
+	filePath := fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, imagesDir, input.Name)
+	if r.exists(filePath) {
+		return filePath, nil
+	}
+
+	if err := r.writeFile(getAccess(isPublic), filePath, input.Content); err != nil {
+		return "", err
+	}
+	return filePath, nil
+}
+
+func (r *LocalFileManager) UploadThumbnail(_ context.Context, objectKey string, size int, input entity.MultipartFile, isPublic bool) error {
+	return r.writeFile(getAccess(isPublic), r.GetThumbnailKey(objectKey, size), input.Content)
+}
+
+func (r *LocalFileManager) GetThumbnailKey(objectKey string, size int) string {
+	return fmt.Sprintf("%s/%s/%d/%s", path.Dir(objectKey), thumbnailsDir, size, path.Base(objectKey))
+}
+
+func (r *LocalFileManager) GetRecipePictureLink(ctx context.Context, recipeId int, objectKey string, ttl time.Duration) (string, error) {
+	if !strings.HasPrefix(objectKey, fmt.Sprintf("%s/%d/%s/", recipesDir, recipeId, imagesDir)) {
+		return r.GetFileLink(objectKey), nil
+	}
+	return r.GetPrivateFileLink(ctx, objectKey, ttl)
+}
+
+func (r *LocalFileManager) GetRecipePictureUploadLink(_ context.Context, recipeId int, uploadId string, input entity.UploadInput, ttl time.Duration) (entity.UploadLink, error) {
+	filePath := fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, uploadsDir, uploadId)
+	expiresAt := time.Now().Add(ttl)
+	size := strconv.FormatInt(input.Size, 10)
+
+	query := url.Values{}
+	query.Set(queryExpires, strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set(queryContentType, input.ContentType)
+	query.Set(querySize, size)
+	query.Set(querySignature, r.signer.sign(http.MethodPut, filePath, expiresAt.Unix(), input.ContentType, size))
+
+	return entity.UploadLink{
+		UploadId:  uploadId,
+		Url:       fmt.Sprintf("%s/%s/%s?%s", r.baseUrl, PrivateRoute, filePath, query.Encode()),
+		Headers:   map[string]string{contentType: input.ContentType, contentLength: size},
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (r *LocalFileManager) GetRecipePictureUpload(_ context.Context, recipeId int, uploadId string) (entity.MultipartFile, error) {
+	filePath := fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, uploadsDir, uploadId)
+	file, err := os.Open(r.getPath(PrivateRoute, filePath))
+	if err != nil {
+		return entity.MultipartFile{}, failure.UploadNotFound
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return entity.MultipartFile{}, failure.UploadNotFound
+	}
+
+	header := make([]byte, sniffLength)
+	n, _ := io.ReadFull(file, header)
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		_ = file.Close()
+		return entity.MultipartFile{}, failure.UploadNotFound
+	}
+
+	return entity.MultipartFile{
+		Name:        uploadId,
+		Content:     file,
+		Size:        info.Size(),
+		ContentType: http.DetectContentType(header[:n]),
+	}, nil
+}
+
+func (r *LocalFileManager) CommitRecipePictureUpload(_ context.Context, recipeId int, uploadId string, isPublic bool) (string, error) {
+	uploadPath := fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, uploadsDir, uploadId)
+	filePath := fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, imagesDir, uploadId)
+
+	upload, err := os.Open(r.getPath(PrivateRoute, uploadPath))
+	if err != nil {
+		return "", failure.UploadNotFound
+	}
+	defer upload.Close()
+
+	if err := r.writeFile(getAccess(isPublic), filePath, upload); err != nil {
+		return "", err
+	}
+	return filePath, nil
+}
+
+func (r *LocalFileManager) DeleteRecipePictureUpload(ctx context.Context, recipeId int, uploadId string) error {
+	return r.DeletePrivateFile(ctx, fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, uploadsDir, uploadId))
+}
+
+func (r *LocalFileManager) SetRecipePicturesAccess(_ context.Context, recipeId int, isPublic bool) error {
+	picturesPath := fmt.Sprintf("%s/%d/%s", recipesDir, recipeId, imagesDir)
+	from, to := PublicRoute, PrivateRoute
+	if isPublic {
+		from, to = PrivateRoute, PublicRoute
+	}
+
+	root := r.getPath(from, picturesPath)
+	return filepath.WalkDir(root, func(filePath string, entry os.DirEntry, err error) error {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return failure.UnableUploadFile
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		relativePath, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return failure.UnableUploadFile
+		}
+		return r.moveFile(from, to, path.Join(picturesPath, filepath.ToSlash(relativePath)))
+	})
+}
+
+func (r *LocalFileManager) DeleteRecipePicture(_ context.Context, recipeId int, pictureName string) error {
+	picturesPath := fmt.Sprintf("%s/%d/%s", recipesDir, recipeId, imagesDir)
+	for _, access := range []string{PublicRoute, PrivateRoute} {
+		sizes, _ := os.ReadDir(r.getPath(access, fmt.Sprintf("%s/%s", picturesPath, thumbnailsDir)))
+		for _, size := range sizes {
+			_ = os.Remove(r.getPath(access, fmt.Sprintf("%s/%s/%s/%s", picturesPath, thumbnailsDir, size.Name(), pictureName)))
+		}
+	}
+	return r.deleteFile(fmt.Sprintf("%s/%s", picturesPath, pictureName))
+}
+
+func (r *LocalFileManager) UploadRecipeKey(_ context.Context, recipeId int, input entity.MultipartFile) (string, error) {
+	filePath := fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, keysDir, input.Name)
+	if err := r.writeFile(PrivateRoute, filePath, input.Content); err != nil {
+		return "", err
+	}
+	return filePath, nil
+}
+
+func (r *LocalFileManager) DeleteFile(_ context.Context, objectKey string) error {
+	return r.deleteFile(objectKey)
+}
+
+func (r *LocalFileManager) GetPrivateFileLink(_ context.Context, objectKey string, ttl time.Duration) (string, error) {
+	expires := time.Now().Add(ttl).Unix()
+
+	query := url.Values{}
+	query.Set(queryExpires, strconv.FormatInt(expires, 10))
+	query.Set(querySignature, r.signer.sign(http.MethodGet, objectKey, expires))
+
+	return fmt.Sprintf("%s/%s/%s?%s", r.baseUrl, PrivateRoute, objectKey, query.Encode()), nil
+}
+
+func (r *LocalFileManager) DeletePrivateFile(_ context.Context, objectKey string) error {
+	if err := os.Remove(r.getPath(PrivateRoute, objectKey)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return failure.UnableDeleteFile
+	}
+	return nil
+}
+
+func (r *LocalFileManager) MakeFilePrivate(_ context.Context, objectKey string) error {
+	return r.moveFile(PublicRoute, PrivateRoute, objectKey)
+}
+
+// GetFileLink returns public link to file. Absolute links stored before object keys are returned as is
+func (r *LocalFileManager) GetFileLink(objectKey string) string {
+	if strings.HasPrefix(objectKey, "http://") || strings.HasPrefix(objectKey, "https://") {
+		return objectKey
+	}
+	return fmt.Sprintf("%s/%s", r.publicUrl, objectKey)
+}
+
+// GetObjectKey returns object key of public or signed link to this storage. Other values are returned as is
+func (r *LocalFileManager) GetObjectKey(link string) string {
+	for _, prefix := range []string{
+		fmt.Sprintf("%s/%s/", r.baseUrl, PublicRoute),
+		fmt.Sprintf("%s/%s/", r.baseUrl, PrivateRoute),
+		r.publicUrl + "/",
+	} {
+		if strings.HasPrefix(link, prefix) {
+			objectKey := strings.TrimPrefix(link, prefix)
+			if query := strings.Index(objectKey, "?"); query >= 0 {
+				objectKey = objectKey[:query]
+			}
+			return objectKey
+		}
+	}
+	return link
+}
+
+func (r *LocalFileManager) writeFile(access, objectKey string, content io.Reader) error {
+	filePath := r.getPath(access, objectKey)
+	if err := os.MkdirAll(filepath.Dir(filePath), dirPermissions); err != nil {
+		return failure.UnableUploadFile
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*")
+	if err != nil {
+		return failure.UnableUploadFile
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = io.Copy(tmpFile, content)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if errors.Is(err, failure.InvalidFileSize) {
+		return failure.InvalidFileSize
+	}
+	if err != nil {
+		return failure.UnableUploadFile
+	}
+
+	if err = os.Chmod(tmpFile.Name(), filePermissions); err != nil {
+		return failure.UnableUploadFile
+	}
+	if err = os.Rename(tmpFile.Name(), filePath); err != nil {
+		return failure.UnableUploadFile
+	}
+	_ = os.Remove(r.getPath(getOppositeAccess(access), objectKey))
+
+	return nil
+}
+
+func (r *LocalFileManager) moveFile(from, to, objectKey string) error {
+	destination := r.getPath(to, objectKey)
+	if err := os.MkdirAll(filepath.Dir(destination), dirPermissions); err != nil {
+		return failure.UnableUploadFile
+	}
+	if err := os.Rename(r.getPath(from, objectKey), destination); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return failure.UnableUploadFile
+	}
+	return nil
+}
+
+func (r *LocalFileManager) deleteFile(objectKey string) error {
+	deleted := false
+	for _, access := range []string{PublicRoute, PrivateRoute} {
+		if err := os.Remove(r.getPath(access, objectKey)); err == nil {
+			deleted = true
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return failure.UnableDeleteFile
+		}
+	}
+	if !deleted {
+		return failure.UnableDeleteFile
+	}
+	return nil
+}
+
+func (r *LocalFileManager) exists(objectKey string) bool {
+	for _, access := range []string{PublicRoute, PrivateRoute} {
+		if _, err := os.Stat(r.getPath(access, objectKey)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// getPath returns path of object on disk. Object key is cleaned as absolute path, so it can't leave storage directory
+func (r *LocalFileManager) getPath(access, objectKey string) string {
+	return filepath.Join(r.root, access, filepath.FromSlash(path.Clean("/"+objectKey)))
+}
+
+func getAccess(isPublic bool) string {
+	if isPublic {
+		return PublicRoute
+	}
+	return PrivateRoute
+}
+
+func getOppositeAccess(access string) string {
+	if access == PublicRoute {
+		return PrivateRoute
+	}
+	return PublicRoute
+}
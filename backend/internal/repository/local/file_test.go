@@ -0,0 +1,26 @@
+package local_test
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/repository/local"
+	"github.com/mephistolie/chefbook-server/internal/repository/storagetest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestLocalFileManager serves storage like router does: public directory as is and private files by signed links
+func TestLocalFileManager(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	files := local.NewLocalFileManager(t.TempDir(), server.URL, "", "storagetest")
+	publicRoute, privateRoute := "/"+local.PublicRoute, "/"+local.PrivateRoute
+	mux.Handle(publicRoute+"/", http.StripPrefix(publicRoute, http.FileServer(http.Dir(files.PublicDir()))))
+	mux.Handle(privateRoute+"/", http.StripPrefix(privateRoute, local.NewFileServer(files)))
+
+	storagetest.Run(t, storagetest.Storage{
+		Files:          files,
+		EnforcesAccess: true,
+	})
+}
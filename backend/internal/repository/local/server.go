@@ -0,0 +1,113 @@
+package local
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	queryExpires     = "expires"
+	queryContentType = "content_type"
+	querySize        = "size"
+	querySignature   = "signature"
+)
+
+type signer struct {
+	key []byte
+}
+
+func newSigner(key string) *signer {
+	return &signer{key: []byte(key)}
+}
+
+func (s *signer) sign(method, objectKey string, expires int64, params ...string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(strings.Join(append([]string{method, objectKey, strconv.FormatInt(expires, 10)}, params...), "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *signer) verify(signature, method, objectKey string, expires int64, params ...string) bool {
+	if time.Now().Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(s.sign(method, objectKey, expires, params...)))
+}
+
+// FileServer serves signed links of local storage: downloads of private files and direct uploads
+type FileServer struct {
+	files *LocalFileManager
+}
+
+func NewFileServer(files *LocalFileManager) *FileServer {
+	return &FileServer{files: files}
+}
+
+// ServeHTTP handles request to object key, which is passed in URL path
+func (s *FileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	objectKey := strings.TrimPrefix(r.URL.Path, "/")
+	query := r.URL.Query()
+
+	expires, err := strconv.ParseInt(query.Get(queryExpires), 10, 64)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if !s.files.signer.verify(query.Get(querySignature), http.MethodGet, objectKey, expires) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		s.serveFile(w, r, objectKey)
+	case http.MethodPut:
+		fileType, size := query.Get(queryContentType), query.Get(querySize)
+		if !s.files.signer.verify(query.Get(querySignature), http.MethodPut, objectKey, expires, fileType, size) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		s.uploadFile(w, r, objectKey, fileType, size)
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *FileServer) serveFile(w http.ResponseWriter, r *http.Request, objectKey string) {
+	for _, access := range []string{PrivateRoute, PublicRoute} {
+		filePath := s.files.getPath(access, objectKey)
+		if info, err := os.Stat(filePath); err == nil && !info.IsDir() {
+			http.ServeFile(w, r, filePath)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// uploadFile stores upload only if its content type and size match signed ones, like S3 presigned PUT does
+func (s *FileServer) uploadFile(w http.ResponseWriter, r *http.Request, objectKey, fileType, size string) {
+	if r.Header.Get(contentType) != fileType || strconv.FormatInt(r.ContentLength, 10) != size {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	content := io.LimitReader(r.Body, r.ContentLength)
+	if err := s.files.writeFile(PrivateRoute, objectKey, content); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if info, err := os.Stat(s.files.getPath(PrivateRoute, objectKey)); err != nil || strconv.FormatInt(info.Size(), 10) != size {
+		_ = os.Remove(s.files.getPath(PrivateRoute, objectKey))
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
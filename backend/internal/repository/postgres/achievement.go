@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+)
+
+type AchievementPostgres struct {
+	db *sqlx.DB
+}
+
+func NewAchievementPostgres(db *sqlx.DB) *AchievementPostgres {
+	return &AchievementPostgres{
+		db: db,
+	}
+}
+
+func (r *AchievementPostgres) GetUnlockedAchievements(userId int) ([]entity.UnlockedAchievement, error) {
+	getAchievementsQuery := fmt.Sprintf(`
+			SELECT achievement, unlock_timestamp
+			FROM %s
+			WHERE user_id=$1
+		`, achievementsTable)
+
+	rows, err := r.db.Query(getAchievementsQuery, userId)
+	if err != nil {
+		logRepoError(err)
+		return []entity.UnlockedAchievement{}, failure.Unknown
+	}
+	defer rows.Close()
+
+	achievements := []entity.UnlockedAchievement{}
+	for rows.Next() {
+		var achievement entity.UnlockedAchievement
+		if err := rows.Scan(&achievement.Code, &achievement.UnlockTimestamp); err != nil {
+			logRepoError(err)
+			continue
+		}
+		achievements = append(achievements, achievement)
+	}
+
+	return achievements, nil
+}
+
+// UnlockAchievement returns false if achievement has already been unlocked
+func (r *AchievementPostgres) UnlockAchievement(userId int, code string) (bool, error) {
+
+	unlockAchievementQuery := fmt.Sprintf(`
+			INSERT INTO %s (user_id, achievement)
+			VALUES ($1, $2)
+			ON CONFLICT (user_id, achievement) DO NOTHING
+		`, achievementsTable)
+
+	result, err := r.db.Exec(unlockAchievementQuery, userId, code)
+	if err != nil {
+		logRepoError(err)
+		return false, failure.Unknown
+	}
+	unlocked, err := result.RowsAffected()
+	if err != nil {
+		logRepoError(err)
+		return false, failure.Unknown
+	}
+
+	return unlocked > 0, nil
+}
+
+// AddRecipeCooking records recipe cooking. Recipe cooking is recorded once a day
+func (r *AchievementPostgres) AddRecipeCooking(recipeId, userId int) error {
+
+	addCookingQuery := fmt.Sprintf(`
+			INSERT INTO %s (user_id, recipe_id)
+			VALUES ($1, $2)
+			ON CONFLICT (user_id, recipe_id, cooking_date) DO NOTHING
+		`, recipeCookingsTable)
+
+	if _, err := r.db.Exec(addCookingQuery, userId, recipeId); err != nil {
+		logRepoError(err)
+		return failure.RecipeNotFound
+	}
+
+	return nil
+}
+
+func (r *AchievementPostgres) GetPublicRecipesCount(userId int) (int, error) {
+	getCountQuery := fmt.Sprintf(`
+			SELECT COUNT(*)
+			FROM %s
+			WHERE owner_id=$1 AND visibility=$2
+		`, recipesTable)
+
+	return r.getCount(getCountQuery, userId, entity.VisibilityPublic)
+}
+
+// GetRecipeSavesCount returns how many times recipes of user were saved by other users
+func (r *AchievementPostgres) GetRecipeSavesCount(userId int) (int, error) {
+	getCountQuery := fmt.Sprintf(`
+			SELECT COUNT(*)
+			FROM %[1]v
+			INNER JOIN %[2]v ON %[2]v.recipe_id=%[1]v.recipe_id
+			WHERE %[2]v.owner_id=$1 AND %[1]v.user_id<>$1
+		`, usersRecipesTable, recipesTable)
+
+	return r.getCount(getCountQuery, userId)
+}
+
+func (r *AchievementPostgres) GetReceivedLikesCount(userId int) (int, error) {
+	getCountQuery := fmt.Sprintf(`
+			SELECT COALESCE(SUM(likes), 0)
+			FROM %s
+			WHERE owner_id=$1
+		`, recipesTable)
+
+	return r.getCount(getCountQuery, userId)
+}
+
+func (r *AchievementPostgres) GetCookedRecipesCount(userId int) (int, error) {
+	getCountQuery := fmt.Sprintf(`
+			SELECT COUNT(DISTINCT recipe_id)
+			FROM %s
+			WHERE user_id=$1
+		`, recipeCookingsTable)
+
+	return r.getCount(getCountQuery, userId)
+}
+
+// GetCookingStreak groups consecutive cooking days: day minus its row number is the same for every day of streak
+func (r *AchievementPostgres) GetCookingStreak(userId int) (entity.CookingStreak, error) {
+	var streak entity.CookingStreak
+
+	getStreakQuery := fmt.Sprintf(`
+			SELECT
+				COALESCE(MAX(length) FILTER (WHERE last_day>=timezone('utc', now())::date-1), 0),
+				COALESCE(MAX(length), 0)
+			FROM
+			(
+				SELECT MAX(cooking_date) AS last_day, COUNT(*) AS length
+				FROM
+				(
+					SELECT cooking_date, cooking_date-(ROW_NUMBER() OVER (ORDER BY cooking_date))::int AS streak_start
+					FROM
+					(
+						SELECT DISTINCT cooking_date
+						FROM %s
+						WHERE user_id=$1
+					) AS days
+				) AS numbered_days
+				GROUP BY streak_start
+			) AS streaks
+		`, recipeCookingsTable)
+
+	if err := r.db.QueryRow(getStreakQuery, userId).Scan(&streak.Current, &streak.Longest); err != nil {
+		logRepoError(err)
+		return entity.CookingStreak{}, failure.Unknown
+	}
+
+	return streak, nil
+}
+
+func (r *AchievementPostgres) getCount(query string, args ...interface{}) (int, error) {
+	var count int
+	if err := r.db.Get(&count, query, args...); err != nil {
+		logRepoError(err)
+		return 0, failure.Unknown
+	}
+	return count, nil
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"github.com/mephistolie/chefbook-server/internal/repository/postgres/dto"
+)
+
+type AllergenPostgres struct {
+	db *sqlx.DB
+}
+
+func NewAllergenPostgres(db *sqlx.DB) *AllergenPostgres {
+	return &AllergenPostgres{
+		db: db,
+	}
+}
+
+func (r *AllergenPostgres) GetRecipesWithUnknownAllergens(limit int) ([]entity.Recipe, error) {
+	query := fmt.Sprintf(`
+			SELECT recipe_id, ingredients
+			FROM %s
+			WHERE allergens IS NULL AND encrypted=false
+			ORDER BY recipe_id
+			LIMIT $1
+		`, recipesTable)
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		logRepoError(err)
+		return []entity.Recipe{}, failure.Unknown
+	}
+	defer rows.Close()
+
+	recipes := []entity.Recipe{}
+	for rows.Next() {
+		var recipe entity.Recipe
+		var bsonIngredients []byte
+		if err := rows.Scan(&recipe.Id, &bsonIngredients); err != nil {
+			logRepoError(err)
+			continue
+		}
+
+		var ingredients []dto.IngredientItem
+		if err := json.Unmarshal(bsonIngredients, &ingredients); err != nil {
+			logRepoError(err)
+			ingredients = []dto.IngredientItem{}
+		}
+		recipe.Ingredients = dto.NewIngredientsEntity(ingredients)
+
+		recipes = append(recipes, recipe)
+	}
+
+	return recipes, nil
+}
+
+func (r *AllergenPostgres) SetRecipeAllergens(recipeId int, allergens []string) error {
+	query := fmt.Sprintf(`
+			UPDATE %s
+			SET allergens=$1
+			WHERE recipe_id=$2
+		`, recipesTable)
+
+	if _, err := r.db.Exec(query, pq.Array(allergens), recipeId); err != nil {
+		logRepoError(err)
+		return failure.RecipeNotFound
+	}
+
+	return nil
+}
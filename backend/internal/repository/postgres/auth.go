@@ -98,7 +98,8 @@ func (r *AuthPostgres) GetUserById(userId int) (entity.Profile, error) {
 	var user dto.ProfileInfo
 
 	getUserQuery := fmt.Sprintf(`
-			SELECT user_id, email, username, password, is_activated, avatar, premium, broccoins, is_blocked
+			SELECT user_id, email, username, bio, password, is_activated, avatar, premium, broccoins, is_blocked,
+				excluded_allergens
 			FROM %s
 			WHERE user_id=$1
 		`, usersTable)
@@ -115,7 +116,8 @@ func (r *AuthPostgres) GetUserByEmail(email string) (entity.Profile, error) {
 	var user dto.ProfileInfo
 
 	getUserQuery := fmt.Sprintf(`
-			SELECT user_id, email, username, password, is_activated, avatar, premium, broccoins, is_blocked
+			SELECT user_id, email, username, bio, password, is_activated, avatar, premium, broccoins, is_blocked,
+				excluded_allergens
 			FROM %s
 			WHERE email=$1
 		`, usersTable)
@@ -169,7 +171,8 @@ func (r *AuthPostgres) GetUserActivationLink(userId int) (uuid.UUID, error) {
 	return activationLink, nil
 }
 
-func (r *AuthPostgres) ActivateProfile(activationLink uuid.UUID) error {
+func (r *AuthPostgres) ActivateProfile(activationLink uuid.UUID) (int, error) {
+	var userId int
 
 	activateProfileQuery := fmt.Sprintf(`
 			UPDATE %s
@@ -180,14 +183,15 @@ func (r *AuthPostgres) ActivateProfile(activationLink uuid.UUID) error {
 				FROM %s
 				WHERE activation_link=$1
 			)
+			RETURNING user_id
 		`, usersTable, activationLinksTable)
 
-	if _, err := r.db.Exec(activateProfileQuery, activationLink); err != nil {
+	if err := r.db.Get(&userId, activateProfileQuery, activationLink); err != nil {
 		logRepoError(err)
-		return failure.InvalidActivationLink
+		return 0, failure.InvalidActivationLink
 	}
 
-	return nil
+	return userId, nil
 }
 
 func (r *AuthPostgres) ChangePassword(userId int, password string) error {
@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"time"
+)
+
+type BroccoinPostgres struct {
+	db *sqlx.DB
+}
+
+func NewBroccoinPostgres(db *sqlx.DB) *BroccoinPostgres {
+	return &BroccoinPostgres{
+		db: db,
+	}
+}
+
+func (r *BroccoinPostgres) GetBroccoinTransactions(userId int, query entity.BroccoinTransactionsQuery) ([]entity.BroccoinTransaction, error) {
+	getTransactionsQuery := fmt.Sprintf(`
+			SELECT transaction_id, user_id, amount, balance, reason, reference, creation_timestamp
+			FROM %s
+			WHERE user_id=$1
+			ORDER BY transaction_id DESC
+			LIMIT %d OFFSET %d
+		`, broccoinsTable, query.PageSize, (query.Page-1)*query.PageSize)
+
+	rows, err := r.db.Query(getTransactionsQuery, userId)
+	if err != nil {
+		logRepoError(err)
+		return []entity.BroccoinTransaction{}, failure.Unknown
+	}
+	defer rows.Close()
+
+	transactions := []entity.BroccoinTransaction{}
+	for rows.Next() {
+		transaction, err := scanBroccoinTransaction(rows)
+		if err != nil {
+			logRepoError(err)
+			continue
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
+
+func (r *BroccoinPostgres) AddBroccoinTransaction(input entity.BroccoinTransactionInput) (entity.BroccoinTransaction, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return entity.BroccoinTransaction{}, failure.Unknown
+	}
+
+	transaction, err := addBroccoinTransaction(tx, input)
+	if err != nil {
+		return entity.BroccoinTransaction{}, rollbackTransaction(tx, nil, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logRepoError(err)
+		return entity.BroccoinTransaction{}, failure.Unknown
+	}
+
+	return transaction, nil
+}
+
+// PurchasePremium exchanges broccoins for premium days. Premium is extended from its end date or from now if it's expired
+func (r *BroccoinPostgres) PurchasePremium(userId, price, days int) (entity.PremiumPurchase, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return entity.PremiumPurchase{}, failure.Unknown
+	}
+
+	transaction, err := addBroccoinTransaction(tx, entity.BroccoinTransactionInput{
+		UserId: userId,
+		Amount: -price,
+		Reason: entity.BroccoinReasonPremiumPurchase,
+	})
+	if err != nil {
+		return entity.PremiumPurchase{}, rollbackTransaction(tx, nil, err)
+	}
+
+	var premiumEndDate sql.NullTime
+	getPremiumQuery := fmt.Sprintf(`
+			SELECT premium
+			FROM %s
+			WHERE user_id=$1
+		`, usersTable)
+
+	if err := tx.QueryRow(getPremiumQuery, userId).Scan(&premiumEndDate); err != nil {
+		return entity.PremiumPurchase{}, rollbackTransaction(tx, err, failure.UserNotFound)
+	}
+
+	purchase := entity.PremiumPurchase{
+		Transaction:    transaction,
+		PremiumEndDate: time.Now().UTC(),
+	}
+	if premiumEndDate.Valid && premiumEndDate.Time.After(purchase.PremiumEndDate) {
+		purchase.PremiumEndDate = premiumEndDate.Time
+	}
+	purchase.PremiumEndDate = purchase.PremiumEndDate.AddDate(0, 0, days)
+
+	if err := setPremiumDate(tx, userId, purchase.PremiumEndDate); err != nil {
+		return entity.PremiumPurchase{}, rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logRepoError(err)
+		return entity.PremiumPurchase{}, failure.Unknown
+	}
+
+	return purchase, nil
+}
+
+// addBroccoinTransaction changes user balance and records transaction. User row stays locked until transaction end,
+// so concurrent transactions of user are applied one by one
+func addBroccoinTransaction(tx *sql.Tx, input entity.BroccoinTransactionInput) (entity.BroccoinTransaction, error) {
+	var balance int
+
+	changeBalanceQuery := fmt.Sprintf(`
+			UPDATE %s
+			SET broccoins=broccoins+$1
+			WHERE user_id=$2
+			RETURNING broccoins
+		`, usersTable)
+
+	if err := tx.QueryRow(changeBalanceQuery, input.Amount, input.UserId).Scan(&balance); err != nil {
+		logRepoError(err)
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == checkViolationCode {
+			return entity.BroccoinTransaction{}, failure.NotEnoughBroccoins
+		}
+		return entity.BroccoinTransaction{}, failure.UserNotFound
+	}
+
+	addTransactionQuery := fmt.Sprintf(`
+			INSERT INTO %s (user_id, amount, balance, reason, reference)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING transaction_id, user_id, amount, balance, reason, reference, creation_timestamp
+		`, broccoinsTable)
+
+	transaction, err := scanBroccoinTransaction(tx.QueryRow(addTransactionQuery, input.UserId, input.Amount, balance,
+		input.Reason, input.Reference))
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolationCode {
+			return entity.BroccoinTransaction{}, failure.BroccoinTransactionExists
+		}
+		logRepoError(err)
+		return entity.BroccoinTransaction{}, failure.Unknown
+	}
+
+	return transaction, nil
+}
+
+func scanBroccoinTransaction(row rowScanner) (entity.BroccoinTransaction, error) {
+	var transaction entity.BroccoinTransaction
+	err := row.Scan(&transaction.Id, &transaction.UserId, &transaction.Amount, &transaction.Balance, &transaction.Reason,
+		&transaction.Reference, &transaction.CreationTimestamp)
+	return transaction, err
+}
@@ -1,8 +1,10 @@
 package postgres
 
 import (
+	"database/sql"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/mephistolie/chefbook-server/internal/entity"
 	"github.com/mephistolie/chefbook-server/internal/entity/failure"
 	"github.com/mephistolie/chefbook-server/internal/repository/postgres/dto"
@@ -23,9 +25,10 @@ func (r *CategoryPostgres) GetUserCategories(userId int) []entity.Category {
 	var categories []entity.Category
 
 	getCategoriesQuery := fmt.Sprintf(`
-			SELECT category_id, name, cover
+			SELECT category_id, name, cover, parent_id, position
 			FROM %s
 			WHERE user_id=$1
+			ORDER BY position, category_id
 		`, categoriesTable)
 
 	rows, err := r.db.Query(getCategoriesQuery, userId)
@@ -37,7 +40,7 @@ func (r *CategoryPostgres) GetUserCategories(userId int) []entity.Category {
 		category := dto.Category{
 			UserId: userId,
 		}
-		err := rows.Scan(&category.Id, &category.Name, &category.Cover)
+		err := rows.Scan(&category.Id, &category.Name, &category.Cover, &category.ParentId, &category.Position)
 		if err != nil {
 			logger.Error(err)
 			continue
@@ -53,13 +56,14 @@ func (r *CategoryPostgres) GetRecipeCategories(recipeId, userId int) []entity.Ca
 
 	getCategoriesQuery := fmt.Sprintf(`
 			SELECT
-				%[1]v.category_id, %[2]v.name, %[2]v.cover
+				%[1]v.category_id, %[2]v.name, %[2]v.cover, %[2]v.parent_id, %[2]v.position
 			FROM
 				%[1]v
-			LEFT JOIN
-				%[2]v ON %[1]v.user_id= %[2]v.user_id
+			INNER JOIN
+				%[2]v ON %[2]v.category_id=%[1]v.category_id
 			WHERE
 				%[1]v.recipe_id=$1 AND %[1]v.user_id=$2
+			ORDER BY %[2]v.position, %[2]v.category_id
 		`, recipesCategoriesTable, categoriesTable)
 
 	rows, err := r.db.Query(getCategoriesQuery, recipeId, userId)
@@ -69,8 +73,10 @@ func (r *CategoryPostgres) GetRecipeCategories(recipeId, userId int) []entity.Ca
 	}
 
 	for rows.Next() {
-		var category dto.Category
-		err := rows.Scan(&category.Id, &category.Name, &category.Cover)
+		category := dto.Category{
+			UserId: userId,
+		}
+		err := rows.Scan(&category.Id, &category.Name, &category.Cover, &category.ParentId, &category.Position)
 		if err != nil {
 			continue
 		}
@@ -84,12 +90,17 @@ func (r *CategoryPostgres) CreateCategory(category entity.CategoryInput, userId
 	var id int
 
 	addCategoryQuery := fmt.Sprintf(`
-			INSERT INTO %s (name, cover, user_id)
-			VALUES ($1, $2, $3)
+			INSERT INTO %[1]v (name, cover, parent_id, user_id, position)
+			VALUES ($1, $2, $3, $4,
+			(
+				SELECT COALESCE(MAX(position), 0)+1
+				FROM %[1]v
+				WHERE user_id=$4 AND parent_id IS NOT DISTINCT FROM $3
+			))
 			RETURNING category_id
 		`, categoriesTable)
 
-	row := r.db.QueryRow(addCategoryQuery, category.Name, category.Cover, userId)
+	row := r.db.QueryRow(addCategoryQuery, category.Name, category.Cover, category.ParentId, userId)
 	if err := row.Scan(&id); err != nil {
 		logRepoError(err)
 		return 0, failure.UnableAddCategory
@@ -102,13 +113,14 @@ func (r *CategoryPostgres) GetCategory(categoryId int) (entity.Category, error)
 	var category dto.Category
 
 	getCategoryQuery := fmt.Sprintf(`
-			SELECT category_id, name, cover, user_id
+			SELECT category_id, name, cover, parent_id, position, user_id
 			FROM %s
 			WHERE category_id=$1
 		`, categoriesTable)
 
 	row := r.db.QueryRow(getCategoryQuery, categoryId)
-	if err := row.Scan(&category.Id, &category.Name, &category.Cover, &category.UserId); err != nil {
+	if err := row.Scan(&category.Id, &category.Name, &category.Cover, &category.ParentId, &category.Position,
+		&category.UserId); err != nil {
 		logRepoError(err)
 		return entity.Category{}, failure.CategoryNotFound
 	}
@@ -134,15 +146,25 @@ func (r *CategoryPostgres) GetCategoryOwnerId(categoryId int) (int, error) {
 	return ownerId, nil
 }
 
+// UpdateCategory moves category to the end of new parent subcategories if parent is changed
 func (r *CategoryPostgres) UpdateCategory(categoryId int, category entity.CategoryInput) error {
 
 	updateCategoryQuery := fmt.Sprintf(`
-			UPDATE %s
-			SET name=$1, cover=$2
-			WHERE category_id=$3
+			UPDATE %[1]v
+			SET name=$1, cover=$2, parent_id=$3, position=
+				CASE
+					WHEN parent_id IS NOT DISTINCT FROM $3 THEN position
+					ELSE
+					(
+						SELECT COALESCE(MAX(siblings.position), 0)+1
+						FROM %[1]v AS siblings
+						WHERE siblings.user_id=%[1]v.user_id AND siblings.parent_id IS NOT DISTINCT FROM $3
+					)
+				END
+			WHERE category_id=$4
 		`, categoriesTable)
 
-	if _, err := r.db.Exec(updateCategoryQuery, category.Name, category.Cover, categoryId); err != nil {
+	if _, err := r.db.Exec(updateCategoryQuery, category.Name, category.Cover, category.ParentId, categoryId); err != nil {
 		logRepoError(err)
 		return failure.CategoryNotFound
 	}
@@ -150,16 +172,71 @@ func (r *CategoryPostgres) UpdateCategory(categoryId int, category entity.Catego
 	return nil
 }
 
+// SetCategoriesOrder sets parents of user categories and their positions among siblings by order of passed categories
+func (r *CategoryPostgres) SetCategoriesOrder(userId int, categories []entity.CategoryPosition) error {
+	ids := make([]int64, len(categories))
+	parentIds := make([]sql.NullInt64, len(categories))
+	for i, category := range categories {
+		ids[i] = int64(category.Id)
+		if category.ParentId != nil {
+			parentIds[i] = sql.NullInt64{Int64: int64(*category.ParentId), Valid: true}
+		}
+	}
+
+	setOrderQuery := fmt.Sprintf(`
+			UPDATE %[1]v
+			SET parent_id=ordered.parent_id, position=ordered.position
+			FROM
+			(
+				SELECT category_id, parent_id, ROW_NUMBER() OVER (PARTITION BY parent_id ORDER BY ordinality) AS position
+				FROM unnest($2::int[], $3::int[]) WITH ORDINALITY AS items(category_id, parent_id, ordinality)
+			) AS ordered
+			WHERE %[1]v.user_id=$1 AND %[1]v.category_id=ordered.category_id
+		`, categoriesTable)
+
+	res, err := r.db.Exec(setOrderQuery, userId, pq.Array(ids), pq.Array(parentIds))
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	if changes, err := res.RowsAffected(); err != nil || int(changes) != len(categories) {
+		return failure.InvalidCategoriesOrder
+	}
+
+	return nil
+}
+
+// DeleteCategory moves subcategories of deleted category to its parent
 func (r *CategoryPostgres) DeleteCategory(categoryId int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	moveSubcategoriesQuery := fmt.Sprintf(`
+			UPDATE %[1]v
+			SET parent_id=(SELECT parent_id FROM %[1]v WHERE category_id=$1)
+			WHERE parent_id=$1
+		`, categoriesTable)
+
+	if _, err := tx.Exec(moveSubcategoriesQuery, categoryId); err != nil {
+		return rollbackTransaction(tx, err, failure.Unknown)
+	}
 
 	deleteCategoryQuery := fmt.Sprintf(`
 			DELETE FROM %s
 			WHERE category_id=$1
 		`, categoriesTable)
 
-	if _, err := r.db.Exec(deleteCategoryQuery, categoryId); err != nil {
+	if _, err := tx.Exec(deleteCategoryQuery, categoryId); err != nil {
+		return rollbackTransaction(tx, err, failure.CategoryNotFound)
+	}
+
+	if err := tx.Commit(); err != nil {
 		logRepoError(err)
-		return failure.CategoryNotFound
+		return failure.Unknown
 	}
 
 	return nil
@@ -0,0 +1,382 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+)
+
+type CollectionPostgres struct {
+	db *sqlx.DB
+}
+
+func NewCollectionPostgres(db *sqlx.DB) *CollectionPostgres {
+	return &CollectionPostgres{
+		db: db,
+	}
+}
+
+func (r *CollectionPostgres) GetCollections(params entity.CollectionsQuery, userId int) ([]entity.Collection, error) {
+	showShared := params.Saved || params.Followed || (params.AuthorId != nil && *params.AuthorId == userId)
+
+	query := r.getCollectionsSelectStatement() + fmt.Sprintf(`
+			WHERE
+				($2::varchar IS NULL OR %[1]v.name ILIKE '%%' || $2 || '%%')
+				AND ($3::int IS NULL OR %[1]v.owner_id=$3)
+				AND ($4 OR %[1]v.visibility='%[3]v')
+				AND (NOT $5 OR %[2]v.saved=true)
+				AND (NOT $6 OR %[2]v.followed=true)
+		`, collectionsTable, usersCollectionsTable, entity.VisibilityPublic)
+	query += r.getPagingStatement(params)
+
+	rows, err := r.db.Query(query, userId, params.Search, params.AuthorId, showShared, params.Saved, params.Followed)
+	if err != nil {
+		logRepoError(err)
+		return []entity.Collection{}, failure.Unknown
+	}
+	defer rows.Close()
+
+	collections := []entity.Collection{}
+	for rows.Next() {
+		collection, err := scanCollection(rows)
+		if err != nil {
+			logRepoError(err)
+			continue
+		}
+		collections = append(collections, collection)
+	}
+
+	return collections, nil
+}
+
+func (r *CollectionPostgres) GetCollection(collectionId, userId int) (entity.Collection, error) {
+	query := r.getCollectionsSelectStatement() + fmt.Sprintf(" WHERE %s.collection_id=$2", collectionsTable)
+
+	collection, err := scanCollection(r.db.QueryRow(query, userId, collectionId))
+	if err != nil {
+		logRepoError(err)
+		return entity.Collection{}, failure.CollectionNotFound
+	}
+
+	return collection, nil
+}
+
+// GetCollectionRecipes returns collection recipes in user defined order. Recipes that became private
+// after adding to collection are visible only for their owners
+func (r *CollectionPostgres) GetCollectionRecipes(collectionId, userId int) ([]entity.RecipeInfo, error) {
+	query := fmt.Sprintf(`
+			SELECT
+				%[1]v.recipe_id, %[1]v.name, %[1]v.owner_id, %[1]v.language, %[1]v.likes, %[1]v.servings, %[1]v.time,
+				%[1]v.calories, %[1]v.preview, %[1]v.visibility, %[1]v.encrypted, %[1]v.creation_timestamp,
+				%[1]v.update_timestamp, coalesce(%[2]v.favourite, false),
+				(
+					SELECT EXISTS
+					(
+						SELECT 1 FROM
+							%[3]v
+						WHERE
+							%[3]v.recipe_id=%[1]v.recipe_id AND user_id=$1
+					)
+				) AS liked, %[4]v.username, %[1]v.allergens
+			FROM
+				%[5]v
+			INNER JOIN
+				%[6]v ON %[6]v.collection_id=%[5]v.collection_id
+			INNER JOIN
+				%[1]v ON %[1]v.recipe_id=%[5]v.recipe_id
+			LEFT JOIN
+				%[2]v ON %[2]v.recipe_id=%[1]v.recipe_id AND %[2]v.user_id=$1
+			LEFT JOIN
+				%[4]v ON %[4]v.user_id=%[1]v.owner_id
+			WHERE
+				%[5]v.collection_id=$2
+				AND
+				(
+					%[1]v.visibility='%[7]v' OR %[1]v.owner_id=$1
+					OR (%[1]v.owner_id=%[6]v.owner_id AND %[1]v.visibility<>'%[8]v')
+				)
+			ORDER BY %[5]v.position
+		`, recipesTable, usersRecipesTable, likesTable, usersTable, collectionsRecipesTable, collectionsTable,
+		entity.VisibilityPublic, entity.VisibilityPrivate)
+
+	rows, err := r.db.Query(query, userId, collectionId)
+	if err != nil {
+		logRepoError(err)
+		return []entity.RecipeInfo{}, failure.Unknown
+	}
+	defer rows.Close()
+
+	return scanRecipeInfos(rows), nil
+}
+
+func (r *CollectionPostgres) GetCollectionOwnerId(collectionId int) (int, error) {
+	var ownerId int
+
+	query := fmt.Sprintf(`
+			SELECT owner_id
+			FROM %s
+			WHERE collection_id=$1
+		`, collectionsTable)
+
+	if err := r.db.Get(&ownerId, query, collectionId); err != nil {
+		logRepoError(err)
+		return 0, failure.CollectionNotFound
+	}
+
+	return ownerId, nil
+}
+
+func (r *CollectionPostgres) GetCollectionsCount(userId int) (int, error) {
+	var count int
+
+	query := fmt.Sprintf(`
+			SELECT COUNT(*)
+			FROM %s
+			WHERE owner_id=$1
+		`, collectionsTable)
+
+	if err := r.db.Get(&count, query, userId); err != nil {
+		logRepoError(err)
+		return 0, failure.Unknown
+	}
+
+	return count, nil
+}
+
+func (r *CollectionPostgres) CreateCollection(collection entity.CollectionInput, userId int) (int, error) {
+	var id int
+
+	query := fmt.Sprintf(`
+			INSERT INTO %s (name, description, visibility, owner_id)
+			VALUES ($1, $2, $3, $4)
+			RETURNING collection_id
+		`, collectionsTable)
+
+	row := r.db.QueryRow(query, collection.Name, collection.Description, collection.Visibility, userId)
+	if err := row.Scan(&id); err != nil {
+		logRepoError(err)
+		return 0, failure.UnableAddCollection
+	}
+
+	return id, nil
+}
+
+func (r *CollectionPostgres) UpdateCollection(collectionId int, collection entity.CollectionInput) error {
+	query := fmt.Sprintf(`
+			UPDATE %s
+			SET name=$1, description=$2, visibility=$3, update_timestamp=timezone('utc', now())
+			WHERE collection_id=$4
+		`, collectionsTable)
+
+	if _, err := r.db.Exec(query, collection.Name, collection.Description, collection.Visibility, collectionId); err != nil {
+		logRepoError(err)
+		return failure.CollectionNotFound
+	}
+
+	return nil
+}
+
+func (r *CollectionPostgres) DeleteCollection(collectionId int) error {
+	query := fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE collection_id=$1
+		`, collectionsTable)
+
+	if _, err := r.db.Exec(query, collectionId); err != nil {
+		logRepoError(err)
+		return failure.CollectionNotFound
+	}
+
+	return nil
+}
+
+// SetCollectionRecipes replaces collection recipes keeping order of passed ids.
+// Only public recipes and non-private recipes of collection owner can be added
+func (r *CollectionPostgres) SetCollectionRecipes(collectionId int, recipeIds []int, ownerId int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	clearRecipesQuery := fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE collection_id=$1
+		`, collectionsRecipesTable)
+
+	if _, err := tx.Exec(clearRecipesQuery, collectionId); err != nil {
+		return rollbackTransaction(tx, err, failure.CollectionNotFound)
+	}
+
+	if len(recipeIds) > 0 {
+		addRecipesQuery := fmt.Sprintf(`
+				INSERT INTO %[1]v (collection_id, recipe_id, position)
+				SELECT $1, ids.recipe_id, ids.position
+				FROM unnest($2::int[]) WITH ORDINALITY AS ids(recipe_id, position)
+				INNER JOIN %[2]v ON %[2]v.recipe_id=ids.recipe_id
+				WHERE %[2]v.visibility='%[3]v' OR (%[2]v.owner_id=$3 AND %[2]v.visibility<>'%[4]v')
+			`, collectionsRecipesTable, recipesTable, entity.VisibilityPublic, entity.VisibilityPrivate)
+
+		res, err := tx.Exec(addRecipesQuery, collectionId, pq.Array(recipeIds), ownerId)
+		if err != nil {
+			return rollbackTransaction(tx, err, failure.Unknown)
+		}
+		if added, err := res.RowsAffected(); err != nil || int(added) != len(recipeIds) {
+			return rollbackTransaction(tx, err, failure.RecipeUnavailableForCollection)
+		}
+	}
+
+	updateTimestampQuery := fmt.Sprintf(`
+			UPDATE %s
+			SET update_timestamp=timezone('utc', now())
+			WHERE collection_id=$1
+		`, collectionsTable)
+
+	if _, err := tx.Exec(updateTimestampQuery, collectionId); err != nil {
+		return rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	return nil
+}
+
+func (r *CollectionPostgres) SetCollectionFollowed(collectionId int, isFollowed bool, userId int) error {
+	return r.setUserCollectionFlag(collectionId, "followed", isFollowed, userId)
+}
+
+func (r *CollectionPostgres) SetCollectionSaved(collectionId int, isSaved bool, userId int) error {
+	return r.setUserCollectionFlag(collectionId, "saved", isSaved, userId)
+}
+
+// ConvertCategoryToCollection creates collection from category recipes that can be published.
+// Category itself stays untouched
+func (r *CollectionPostgres) ConvertCategoryToCollection(categoryId int, collection entity.CollectionInput, userId int) (int, error) {
+	var collectionId int
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return 0, failure.Unknown
+	}
+
+	createCollectionQuery := fmt.Sprintf(`
+			INSERT INTO %[1]v (name, description, visibility, owner_id)
+			SELECT name, $2, $3, user_id
+			FROM %[2]v
+			WHERE category_id=$1 AND user_id=$4
+			RETURNING collection_id
+		`, collectionsTable, categoriesTable)
+
+	row := tx.QueryRow(createCollectionQuery, categoryId, collection.Description, collection.Visibility, userId)
+	if err := row.Scan(&collectionId); err != nil {
+		return 0, rollbackTransaction(tx, err, failure.CategoryNotFound)
+	}
+
+	copyRecipesQuery := fmt.Sprintf(`
+			INSERT INTO %[1]v (collection_id, recipe_id, position)
+			SELECT DISTINCT ON (%[3]v.recipe_id) $1, %[3]v.recipe_id, dense_rank() OVER (ORDER BY %[3]v.name, %[3]v.recipe_id)
+			FROM %[2]v
+			INNER JOIN %[3]v ON %[3]v.recipe_id=%[2]v.recipe_id
+			WHERE
+				%[2]v.category_id=$2 AND %[2]v.user_id=$3
+				AND (%[3]v.visibility='%[4]v' OR (%[3]v.owner_id=$3 AND %[3]v.visibility<>'%[5]v'))
+		`, collectionsRecipesTable, recipesCategoriesTable, recipesTable, entity.VisibilityPublic, entity.VisibilityPrivate)
+
+	if _, err := tx.Exec(copyRecipesQuery, collectionId, categoryId, userId); err != nil {
+		return 0, rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logRepoError(err)
+		return 0, failure.Unknown
+	}
+
+	return collectionId, nil
+}
+
+func (r *CollectionPostgres) setUserCollectionFlag(collectionId int, flag string, value bool, userId int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	setFlagQuery := fmt.Sprintf(`
+			INSERT INTO %[1]v (collection_id, user_id, %[2]v)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (collection_id, user_id) DO UPDATE SET %[2]v=$3
+		`, usersCollectionsTable, flag)
+
+	if _, err := tx.Exec(setFlagQuery, collectionId, userId, value); err != nil {
+		return rollbackTransaction(tx, err, failure.CollectionNotFound)
+	}
+
+	clearUnusedQuery := fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE collection_id=$1 AND user_id=$2 AND followed=false AND saved=false
+		`, usersCollectionsTable)
+
+	if _, err := tx.Exec(clearUnusedQuery, collectionId, userId); err != nil {
+		return rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	return nil
+}
+
+func (r *CollectionPostgres) getCollectionsSelectStatement() string {
+	return fmt.Sprintf(`
+			SELECT
+				%[1]v.collection_id, %[1]v.name, %[1]v.description, %[1]v.owner_id, %[2]v.username, %[1]v.visibility,
+				(
+					SELECT count(*)
+					FROM %[3]v
+					WHERE %[3]v.collection_id=%[1]v.collection_id
+				) AS recipes_count,
+				(
+					SELECT count(*)
+					FROM %[4]v AS followers
+					WHERE followers.collection_id=%[1]v.collection_id AND followers.followed=true
+				) AS followers,
+				coalesce(%[4]v.followed, false), coalesce(%[4]v.saved, false),
+				%[1]v.creation_timestamp, %[1]v.update_timestamp
+			FROM
+				%[1]v
+			LEFT JOIN
+				%[4]v ON %[4]v.collection_id=%[1]v.collection_id AND %[4]v.user_id=$1
+			LEFT JOIN
+				%[2]v ON %[2]v.user_id=%[1]v.owner_id
+		`, collectionsTable, usersTable, collectionsRecipesTable, usersCollectionsTable)
+}
+
+func (r *CollectionPostgres) getPagingStatement(params entity.CollectionsQuery) string {
+	sortingColumn := fmt.Sprintf("%s.%s", collectionsTable, params.SortBy)
+	if params.SortBy == entity.SortingFollowers {
+		sortingColumn = "followers"
+	}
+
+	return fmt.Sprintf(" ORDER BY %s DESC, %s.collection_id DESC LIMIT %d OFFSET %d",
+		sortingColumn, collectionsTable, params.PageSize, (params.Page-1)*params.PageSize)
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCollection(row rowScanner) (entity.Collection, error) {
+	var collection entity.Collection
+	err := row.Scan(&collection.Id, &collection.Name, &collection.Description, &collection.OwnerId, &collection.OwnerName,
+		&collection.Visibility, &collection.RecipesCount, &collection.Followers, &collection.IsFollowed, &collection.IsSaved,
+		&collection.CreationTimestamp, &collection.UpdateTimestamp)
+	return collection, err
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "github.com/lib/pq"
+
+// Allergens keeps difference between unknown allergens, stored as NULL, and recipe without allergens
+type Allergens struct {
+	Values *[]string
+}
+
+func (a *Allergens) Scan(src interface{}) error {
+	if src == nil {
+		a.Values = nil
+		return nil
+	}
+
+	var array pq.StringArray
+	if err := array.Scan(src); err != nil {
+		return err
+	}
+
+	values := []string(array)
+	if values == nil {
+		values = []string{}
+	}
+	a.Values = &values
+
+	return nil
+}
+
+func NewAllergensArray(allergens *[]string) interface{} {
+	if allergens == nil {
+		return nil
+	}
+	return pq.Array(*allergens)
+}
@@ -3,17 +3,21 @@ package dto
 import "github.com/mephistolie/chefbook-server/internal/entity"
 
 type Category struct {
-	Id     int     `db:"category_id"`
-	Name   string  `db:"name"`
-	Cover  *string `db:"cover"`
-	UserId int     `db:"user_id"`
+	Id       int     `db:"category_id"`
+	Name     string  `db:"name"`
+	Cover    *string `db:"cover"`
+	ParentId *int    `db:"parent_id"`
+	Position int     `db:"position"`
+	UserId   int     `db:"user_id"`
 }
 
 func (c *Category) Entity() entity.Category {
 	return entity.Category{
-		Id:     c.Id,
-		Name:   c.Name,
-		Cover:  c.Cover,
-		UserId: c.UserId,
+		Id:       c.Id,
+		Name:     c.Name,
+		Cover:    c.Cover,
+		ParentId: c.ParentId,
+		Position: c.Position,
+		UserId:   c.UserId,
 	}
 }
@@ -1,22 +1,25 @@
 package dto
 
 import (
+	"github.com/lib/pq"
 	"github.com/mephistolie/chefbook-server/internal/entity"
 	"time"
 )
 
 type ProfileInfo struct {
-	Id                int        `db:"user_id"`
-	Email             string     `db:"email"`
-	Username          *string    `db:"username,omitempty"`
-	CreationTimestamp time.Time  `db:"registered"`
-	Password          string     `db:"password"`
-	IsActivated       bool       `db:"is_activated"`
-	Avatar            *string    `db:"avatar"`
-	PremiumEndDate    *time.Time `db:"premium"`
-	Broccoins         int        `db:"broccoins"`
-	IsBlocked         bool       `db:"is_blocked"`
-	Key               *string    `db:"key"`
+	Id                int            `db:"user_id"`
+	Email             string         `db:"email"`
+	Username          *string        `db:"username,omitempty"`
+	Bio               *string        `db:"bio"`
+	CreationTimestamp time.Time      `db:"registered"`
+	Password          string         `db:"password"`
+	IsActivated       bool           `db:"is_activated"`
+	Avatar            *string        `db:"avatar"`
+	PremiumEndDate    *time.Time     `db:"premium"`
+	Broccoins         int            `db:"broccoins"`
+	IsBlocked         bool           `db:"is_blocked"`
+	ExcludedAllergens pq.StringArray `db:"excluded_allergens"`
+	Key               *string        `db:"key"`
 }
 
 func (p *ProfileInfo) Entity() entity.Profile {
@@ -24,6 +27,7 @@ func (p *ProfileInfo) Entity() entity.Profile {
 		Id:                p.Id,
 		Email:             p.Email,
 		Username:          p.Username,
+		Bio:               p.Bio,
 		CreationTimestamp: p.CreationTimestamp,
 		Password:          p.Password,
 		IsActivated:       p.IsActivated,
@@ -31,5 +35,6 @@ func (p *ProfileInfo) Entity() entity.Profile {
 		PremiumEndDate:    p.PremiumEndDate,
 		Broccoins:         p.Broccoins,
 		IsBlocked:         p.IsBlocked,
+		ExcludedAllergens: p.ExcludedAllergens,
 	}
 }
@@ -11,11 +11,12 @@ type IngredientItem struct {
 }
 
 type CookingItem struct {
-	Text     string    `json:"text" binding:"required,min=1"`
-	Type     string    `json:"type" binding:"required"`
-	Link     *string   `json:"link,omitempty"`
-	Time     *int16    `json:"time,omitempty"`
-	Pictures *[]string `json:"pictures,omitempty"`
+	Text       string    `json:"text" binding:"required,min=1"`
+	Type       string    `json:"type" binding:"required"`
+	Link       *string   `json:"link,omitempty"`
+	Time       *int16    `json:"time,omitempty"`
+	Pictures   *[]string `json:"pictures,omitempty"`
+	PictureIds *[]int    `json:"picture_ids,omitempty"`
 }
 
 func (i *IngredientItem) Entity() entity.IngredientItem {
@@ -56,21 +57,23 @@ func NewIngredients(entities []entity.IngredientItem) []IngredientItem {
 
 func (i *CookingItem) Entity() entity.CookingItem {
 	return entity.CookingItem{
-		Text:     i.Text,
-		Type:     i.Type,
-		Time:     i.Time,
-		Pictures: i.Pictures,
-		Link:     i.Link,
+		Text:       i.Text,
+		Type:       i.Type,
+		Time:       i.Time,
+		Pictures:   i.Pictures,
+		PictureIds: i.PictureIds,
+		Link:       i.Link,
 	}
 }
 
 func NewCookingItem(cookingItem entity.CookingItem) CookingItem {
 	return CookingItem{
-		Text:     cookingItem.Text,
-		Type:     cookingItem.Type,
-		Time:     cookingItem.Time,
-		Pictures: cookingItem.Pictures,
-		Link:     cookingItem.Link,
+		Text:       cookingItem.Text,
+		Type:       cookingItem.Type,
+		Time:       cookingItem.Time,
+		Pictures:   cookingItem.Pictures,
+		PictureIds: cookingItem.PictureIds,
+		Link:       cookingItem.Link,
 	}
 }
 
@@ -32,7 +32,7 @@ func (r *EncryptionPostgres) GetUserKeyLink(userId int) (*string, error) {
 	return link, nil
 }
 
-func (r *EncryptionPostgres) SetUserKeyLink(userId int, url *string) error {
+func (r *EncryptionPostgres) SetUserKeyLink(userId int, objectKey *string) error {
 
 	setKeyQuery := fmt.Sprintf(`
 			UPDATE %s
@@ -40,7 +40,7 @@ func (r *EncryptionPostgres) SetUserKeyLink(userId int, url *string) error {
 			WHERE user_id=$2
 		`, usersTable)
 
-	if _, err := r.db.Exec(setKeyQuery, url, userId); err != nil {
+	if _, err := r.db.Exec(setKeyQuery, objectKey, userId); err != nil {
 		logRepoError(err)
 		return failure.UserNotFound
 	}
@@ -65,7 +65,7 @@ func (r *EncryptionPostgres) GetRecipeKeyLink(recipeId int) (*string, error) {
 	return link, nil
 }
 
-func (r *EncryptionPostgres) SetRecipeKeyLink(recipeId int, url *string) error {
+func (r *EncryptionPostgres) SetRecipeKeyLink(recipeId int, objectKey *string) error {
 
 	setKeyQuery := fmt.Sprintf(`
 			UPDATE %s
@@ -73,10 +73,55 @@ func (r *EncryptionPostgres) SetRecipeKeyLink(recipeId int, url *string) error {
 			WHERE recipe_id=$2
 		`, recipesTable)
 
-	if _, err := r.db.Exec(setKeyQuery, url, recipeId); err != nil {
+	if _, err := r.db.Exec(setKeyQuery, objectKey, recipeId); err != nil {
 		logRepoError(err)
 		return failure.UserNotFound
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// GetPublicUserKeyLinks returns user keys which are still stored as public links
+func (r *EncryptionPostgres) GetPublicUserKeyLinks() (map[int]string, error) {
+	getKeyLinksQuery := fmt.Sprintf(`
+			SELECT user_id, key
+			FROM %s
+			WHERE key LIKE 'http%%'
+		`, usersTable)
+
+	return r.getKeyLinks(getKeyLinksQuery)
+}
+
+// GetPublicRecipeKeyLinks returns recipe keys which are still stored as public links
+func (r *EncryptionPostgres) GetPublicRecipeKeyLinks() (map[int]string, error) {
+	getKeyLinksQuery := fmt.Sprintf(`
+			SELECT recipe_id, key
+			FROM %s
+			WHERE key LIKE 'http%%'
+		`, recipesTable)
+
+	return r.getKeyLinks(getKeyLinksQuery)
+}
+
+func (r *EncryptionPostgres) getKeyLinks(query string) (map[int]string, error) {
+	links := make(map[int]string)
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		logRepoError(err)
+		return map[int]string{}, failure.Unknown
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var link string
+		if err := rows.Scan(&id, &link); err != nil {
+			logRepoError(err)
+			return map[int]string{}, failure.Unknown
+		}
+		links[id] = link
+	}
+
+	return links, nil
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+)
+
+type FollowPostgres struct {
+	db *sqlx.DB
+}
+
+func NewFollowPostgres(db *sqlx.DB) *FollowPostgres {
+	return &FollowPostgres{
+		db: db,
+	}
+}
+
+func (r *FollowPostgres) FollowUser(authorId, followerId int) error {
+
+	followQuery := fmt.Sprintf(`
+			INSERT INTO %s (follower_id, author_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, followsTable)
+
+	if _, err := r.db.Exec(followQuery, followerId, authorId); err != nil {
+		logRepoError(err)
+		return failure.UnableFollowUser
+	}
+
+	return nil
+}
+
+func (r *FollowPostgres) UnfollowUser(authorId, followerId int) error {
+
+	unfollowQuery := fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE follower_id=$1 AND author_id=$2
+		`, followsTable)
+
+	if _, err := r.db.Exec(unfollowQuery, followerId, authorId); err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	return nil
+}
+
+func (r *FollowPostgres) GetFollowers(userId int) ([]entity.ProfileInfo, error) {
+	query := fmt.Sprintf(`
+			SELECT
+				%[1]v.follower_id, %[2]v.username, %[2]v.registered, %[2]v.avatar, %[2]v.premium
+			FROM
+				%[1]v
+			LEFT JOIN
+				%[2]v ON %[2]v.user_id=%[1]v.follower_id
+			WHERE
+				%[1]v.author_id=$1
+			ORDER BY %[1]v.creation_timestamp DESC
+		`, followsTable, usersTable)
+
+	return r.getUsers(query, userId)
+}
+
+func (r *FollowPostgres) GetFollowing(userId int) ([]entity.ProfileInfo, error) {
+	query := fmt.Sprintf(`
+			SELECT
+				%[1]v.author_id, %[2]v.username, %[2]v.registered, %[2]v.avatar, %[2]v.premium
+			FROM
+				%[1]v
+			LEFT JOIN
+				%[2]v ON %[2]v.user_id=%[1]v.author_id
+			WHERE
+				%[1]v.follower_id=$1
+			ORDER BY %[1]v.creation_timestamp DESC
+		`, followsTable, usersTable)
+
+	return r.getUsers(query, userId)
+}
+
+func (r *FollowPostgres) getUsers(query string, userId int) ([]entity.ProfileInfo, error) {
+	rows, err := r.db.Query(query, userId)
+	if err != nil {
+		logRepoError(err)
+		return []entity.ProfileInfo{}, failure.UserNotFound
+	}
+	defer rows.Close()
+
+	users := []entity.ProfileInfo{}
+	for rows.Next() {
+		var user entity.ProfileInfo
+		err := rows.Scan(&user.Id, &user.Username, &user.CreationTimestamp, &user.Avatar, &user.PremiumEndDate)
+		if err != nil {
+			logRepoError(err)
+			continue
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
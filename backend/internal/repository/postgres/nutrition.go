@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"strconv"
+)
+
+type NutritionPostgres struct {
+	db *sqlx.DB
+}
+
+func NewNutritionPostgres(db *sqlx.DB) *NutritionPostgres {
+	return &NutritionPostgres{
+		db: db,
+	}
+}
+
+func (r *NutritionPostgres) AddFoods(foods []entity.Food) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	addFoodQuery := fmt.Sprintf(`
+			INSERT INTO %s (source, external_id, name, language, calories, protein, fats, carbohydrates)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (source, external_id) DO UPDATE
+			SET name=$3, language=$4, calories=$5, protein=$6, fats=$7, carbohydrates=$8
+		`, foodsTable)
+
+	for _, food := range foods {
+		if _, err := tx.Exec(addFoodQuery, food.Source, food.ExternalId, food.Name, food.Language, food.Calories,
+			food.Protein, food.Fats, food.Carbohydrates); err != nil {
+			logRepoError(err)
+			if err := tx.Rollback(); err != nil {
+				logRepoError(err)
+			}
+			return failure.Unknown
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	return nil
+}
+
+// FindFood looks for the closest food by word similarity of names with trigram index.
+// Foods with similarity below minConfidence aren't matched
+func (r *NutritionPostgres) FindFood(text, language string, minConfidence float64) (entity.FoodMatch, error) {
+	var match entity.FoodMatch
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return entity.FoodMatch{}, failure.Unknown
+	}
+
+	setThresholdQuery := `SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)`
+
+	if _, err := tx.Exec(setThresholdQuery, strconv.FormatFloat(minConfidence, 'f', -1, 64)); err != nil {
+		return entity.FoodMatch{}, rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	query := fmt.Sprintf(`
+			SELECT
+				food_id, source, external_id, name, language, calories, protein, fats, carbohydrates,
+				word_similarity(lower($1), lower(name)) AS confidence
+			FROM
+				%s
+			WHERE
+				lower($1) <%% lower(name) AND ($2='' OR language=$2)
+			ORDER BY lower($1) <<-> lower(name)
+			LIMIT 1
+		`, foodsTable)
+
+	row := tx.QueryRow(query, text, language)
+	if err := row.Scan(&match.Food.Id, &match.Food.Source, &match.Food.ExternalId, &match.Food.Name, &match.Food.Language,
+		&match.Food.Calories, &match.Food.Protein, &match.Food.Fats, &match.Food.Carbohydrates, &match.Confidence); err != nil {
+		if err == sql.ErrNoRows {
+			return entity.FoodMatch{}, rollbackTransaction(tx, nil, failure.FoodNotFound)
+		}
+		return entity.FoodMatch{}, rollbackTransaction(tx, err, failure.FoodNotFound)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logRepoError(err)
+		return entity.FoodMatch{}, failure.Unknown
+	}
+
+	return match, nil
+}
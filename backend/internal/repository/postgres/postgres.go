@@ -1,21 +1,45 @@
 package postgres
 
 import (
+	"database/sql"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
 )
 
 const (
-	usersTable             = "users"
-	activationLinksTable   = "activation_links"
-	rolesTable             = "roles"
-	sessionsTable          = "sessions"
-	recipesTable           = "recipes"
-	categoriesTable        = "categories"
-	shoppingListTable      = "shopping_list"
-	usersRecipesTable      = "users_recipes"
-	likesTable             = "likes"
-	recipesCategoriesTable = "recipes_categories"
+	usersTable              = "users"
+	activationLinksTable    = "activation_links"
+	rolesTable              = "roles"
+	sessionsTable           = "sessions"
+	recipesTable            = "recipes"
+	categoriesTable         = "categories"
+	shoppingListTable       = "shopping_list"
+	usersRecipesTable       = "users_recipes"
+	likesTable              = "likes"
+	recipesCategoriesTable  = "recipes_categories"
+	followsTable            = "follows"
+	recipeViewsTable        = "recipe_views"
+	tagsTable               = "tags"
+	recipesTagsTable        = "recipes_tags"
+	foodsTable              = "foods"
+	collectionsTable        = "collections"
+	collectionsRecipesTable = "collections_recipes"
+	usersCollectionsTable   = "users_collections"
+	recipeLinksTable        = "recipe_links"
+	recipePicturesTable     = "recipe_pictures"
+	storageFilesTable       = "storage_files"
+	subscriptionsTable      = "subscriptions"
+	broccoinsTable          = "broccoin_transactions"
+	referralsTable          = "referrals"
+	recipeCookingsTable     = "recipe_cookings"
+	achievementsTable       = "users_achievements"
+	keyRequestsTable        = "encrypted_recipes_requests"
+	recipeSimilaritiesTable = "recipe_similarities"
+	ingredientTokensTable   = "recipe_ingredient_tokens"
+
+	uniqueViolationCode = "23505"
+	checkViolationCode  = "23514"
 )
 
 type Config struct {
@@ -36,3 +60,15 @@ func NewPostgresDB(cfg Config) (*sqlx.DB, error) {
 
 	return db, nil
 }
+
+// rollbackTransaction logs transaction error and returns failure to pass to service
+func rollbackTransaction(tx *sql.Tx, err error, failureErr error) error {
+	if err != nil {
+		logRepoError(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+	return failureErr
+}
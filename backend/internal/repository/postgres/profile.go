@@ -3,6 +3,8 @@ package postgres
 import (
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mephistolie/chefbook-server/internal/entity"
 	"github.com/mephistolie/chefbook-server/internal/entity/failure"
 	"time"
 )
@@ -15,6 +17,56 @@ func NewProfilePostgres(db *sqlx.DB) *ProfilePostgres {
 	return &ProfilePostgres{db: db}
 }
 
+func (r *ProfilePostgres) GetPublicProfile(userId, requesterId int) (entity.PublicProfile, error) {
+	var profile entity.PublicProfile
+
+	getProfileQuery := fmt.Sprintf(`
+			SELECT
+				%[1]v.user_id, %[1]v.username, %[1]v.bio, %[1]v.registered, %[1]v.avatar,
+				(
+					SELECT count(*)
+					FROM %[2]v
+					WHERE %[2]v.owner_id=%[1]v.user_id AND %[2]v.visibility='%[4]v'
+				) AS recipes_count,
+				(
+					SELECT coalesce(sum(%[2]v.likes), 0)
+					FROM %[2]v
+					WHERE %[2]v.owner_id=%[1]v.user_id AND %[2]v.visibility='%[4]v'
+				) AS likes_count,
+				(
+					SELECT count(*)
+					FROM %[3]v
+					WHERE %[3]v.author_id=%[1]v.user_id
+				) AS followers_count,
+				(
+					SELECT count(*)
+					FROM %[3]v
+					WHERE %[3]v.follower_id=%[1]v.user_id
+				) AS following_count,
+				(
+					SELECT EXISTS
+					(
+						SELECT 1
+						FROM %[3]v
+						WHERE %[3]v.author_id=%[1]v.user_id AND %[3]v.follower_id=$2
+					)
+				) AS followed
+			FROM
+				%[1]v
+			WHERE
+				%[1]v.user_id=$1 AND %[1]v.is_activated=true AND %[1]v.is_blocked=false
+		`, usersTable, recipesTable, followsTable, entity.VisibilityPublic)
+
+	row := r.db.QueryRow(getProfileQuery, userId, requesterId)
+	if err := row.Scan(&profile.Id, &profile.Username, &profile.Bio, &profile.CreationTimestamp, &profile.Avatar,
+		&profile.RecipesCount, &profile.LikesCount, &profile.FollowersCount, &profile.FollowingCount, &profile.IsFollowed); err != nil {
+		logRepoError(err)
+		return entity.PublicProfile{}, failure.UserNotFound
+	}
+
+	return profile, nil
+}
+
 func (r *ProfilePostgres) SetUsername(userId int, username *string) error {
 
 	SetUsernameQuery := fmt.Sprintf(`
@@ -31,15 +83,48 @@ func (r *ProfilePostgres) SetUsername(userId int, username *string) error {
 	return nil
 }
 
-func (r *ProfilePostgres) IncreaseBroccoins(userId, broccoins int) error {
+func (r *ProfilePostgres) SetBio(userId int, bio *string) error {
+
+	setBioQuery := fmt.Sprintf(`
+			UPDATE %s
+			SET bio=$1
+			WHERE user_id=$2
+		`, usersTable)
+
+	if _, err := r.db.Exec(setBioQuery, bio, userId); err != nil {
+		logRepoError(err)
+		return failure.UserNotFound
+	}
+
+	return nil
+}
+
+func (r *ProfilePostgres) GetExcludedAllergens(userId int) ([]string, error) {
+	var allergens pq.StringArray
 
-	increaseBroccoinsQuery := fmt.Sprintf(`
+	getAllergensQuery := fmt.Sprintf(`
+			SELECT excluded_allergens
+			FROM %s
+			WHERE user_id=$1
+		`, usersTable)
+
+	if err := r.db.Get(&allergens, getAllergensQuery, userId); err != nil {
+		logRepoError(err)
+		return []string{}, failure.UserNotFound
+	}
+
+	return allergens, nil
+}
+
+func (r *ProfilePostgres) SetExcludedAllergens(userId int, allergens []string) error {
+
+	setAllergensQuery := fmt.Sprintf(`
 			UPDATE %s
-			SET broccoins=broccoins+$1
+			SET excluded_allergens=$1
 			WHERE user_id=$2
 		`, usersTable)
 
-	if _, err := r.db.Exec(increaseBroccoinsQuery, broccoins, userId); err != nil {
+	if _, err := r.db.Exec(setAllergensQuery, pq.Array(allergens), userId); err != nil {
 		logRepoError(err)
 		return failure.UserNotFound
 	}
@@ -47,6 +132,36 @@ func (r *ProfilePostgres) IncreaseBroccoins(userId, broccoins int) error {
 	return nil
 }
 
+// GetAvatarLinks returns avatars which are stored as absolute links instead of object keys
+func (r *ProfilePostgres) GetAvatarLinks() (map[int]string, error) {
+	links := make(map[int]string)
+
+	getAvatarLinksQuery := fmt.Sprintf(`
+			SELECT user_id, avatar
+			FROM %s
+			WHERE avatar LIKE 'http%%'
+		`, usersTable)
+
+	rows, err := r.db.Query(getAvatarLinksQuery)
+	if err != nil {
+		logRepoError(err)
+		return map[int]string{}, failure.Unknown
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userId int
+		var link string
+		if err := rows.Scan(&userId, &link); err != nil {
+			logRepoError(err)
+			return map[int]string{}, failure.Unknown
+		}
+		links[userId] = link
+	}
+
+	return links, nil
+}
+
 func (r *ProfilePostgres) SetAvatarLink(userId int, url *string) error {
 
 	setAvatarQuery := fmt.Sprintf(`
@@ -64,6 +179,15 @@ func (r *ProfilePostgres) SetAvatarLink(userId int, url *string) error {
 }
 
 func (r *ProfilePostgres) SetPremiumDate(userId int, expiresAt time.Time) error {
+	if err := setPremiumDate(r.db, userId, expiresAt); err != nil {
+		logRepoError(err)
+		return failure.UserNotFound
+	}
+	return nil
+}
+
+// setPremiumDate sets premium end date with database connection or inside transaction
+func setPremiumDate(db sqlx.Execer, userId int, expiresAt time.Time) error {
 
 	setPremiumDateQuery := fmt.Sprintf(`
 			UPDATE %s
@@ -71,11 +195,21 @@ func (r *ProfilePostgres) SetPremiumDate(userId int, expiresAt time.Time) error
 			WHERE user_id=$2
 		`, usersTable)
 
-	if _, err := r.db.Exec(setPremiumDateQuery, expiresAt, userId); err != nil {
-		logRepoError(err)
-		return failure.UserNotFound
-	}
-	return nil
+	_, err := db.Exec(setPremiumDateQuery, expiresAt, userId)
+	return err
+}
+
+// extendPremium adds days to premium end date or to now if premium is expired
+func extendPremium(db sqlx.Execer, userId, days int) error {
+
+	extendPremiumQuery := fmt.Sprintf(`
+			UPDATE %s
+			SET premium=GREATEST(COALESCE(premium, now()), now())+make_interval(days=>$1)
+			WHERE user_id=$2
+		`, usersTable)
+
+	_, err := db.Exec(extendPremiumQuery, days, userId)
+	return err
 }
 
 func (r *ProfilePostgres) SetProfileCreationDate(userId int, creationTimestamp time.Time) error {
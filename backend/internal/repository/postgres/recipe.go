@@ -21,7 +21,6 @@ func NewRecipePostgres(db *sqlx.DB) *RecipePostgres {
 }
 
 func (r *RecipePostgres) GetRecipes(params entity.RecipesQuery, userId int) ([]entity.RecipeInfo, error) {
-	var recipes []entity.RecipeInfo
 	var rows *sql.Rows
 	var err error
 
@@ -36,31 +35,66 @@ func (r *RecipePostgres) GetRecipes(params entity.RecipesQuery, userId int) ([]e
 		logRepoError(err)
 		return []entity.RecipeInfo{}, nil
 	}
+	defer rows.Close()
 
+	return scanRecipeInfos(rows), nil
+}
+
+func (r *RecipePostgres) GetRecipesTagFacets(params entity.RecipesQuery, userId int) ([]entity.TagFacet, error) {
+	var rows *sql.Rows
+	var err error
+
+	facetsQuery := fmt.Sprintf(`
+			SELECT
+				%[3]v.tag_id, %[4]v.type, count(*) AS recipes_count
+			FROM
+				%[1]v
+			LEFT JOIN
+				%[2]v ON %[2]v.recipe_id=%[1]v.recipe_id AND %[2]v.user_id=%[5]v
+			INNER JOIN
+				%[3]v ON %[3]v.recipe_id=%[1]v.recipe_id
+			INNER JOIN
+				%[4]v ON %[4]v.tag_id=%[3]v.tag_id
+		`, recipesTable, usersRecipesTable, recipesTagsTable, tagsTable, userId)
+	facetsQuery += r.getWhereStatement(params, userId)
+	facetsQuery += fmt.Sprintf(" GROUP BY %[1]v.tag_id, %[2]v.type ORDER BY recipes_count DESC, %[1]v.tag_id",
+		recipesTagsTable, tagsTable)
+
+	if params.Search != nil {
+		rows, err = r.db.Query(facetsQuery, *params.Search)
+	} else {
+		rows, err = r.db.Query(facetsQuery)
+	}
+	if err != nil {
+		logRepoError(err)
+		return []entity.TagFacet{}, failure.Unknown
+	}
+	defer rows.Close()
+
+	facets := []entity.TagFacet{}
 	for rows.Next() {
-		var recipe entity.RecipeInfo
-		err := rows.Scan(&recipe.Id, &recipe.Name, &recipe.OwnerId, &recipe.Language, &recipe.Likes, &recipe.Servings,
-			&recipe.Time, &recipe.Calories, &recipe.Preview, &recipe.Visibility, &recipe.IsEncrypted, &recipe.CreationTimestamp,
-			&recipe.UpdateTimestamp, &recipe.IsFavourite, &recipe.IsLiked, &recipe.OwnerName)
-		if err != nil {
+		var facet entity.TagFacet
+		if err := rows.Scan(&facet.Id, &facet.Type, &facet.Count); err != nil {
 			logRepoError(err)
 			continue
 		}
-		recipes = append(recipes, recipe)
+		facets = append(facets, facet)
 	}
 
-	return recipes, nil
+	return facets, nil
 }
 
 func (r *RecipePostgres) GetRecipe(recipeId int) (entity.Recipe, error) {
 	var recipe entity.Recipe
 	var bsonIngredients []byte
 	var bsonCooking []byte
+	var allergens dto.Allergens
 
 	getRecipeQuery := fmt.Sprintf(`
 			SELECT
 				recipe_id, name, owner_id, language, description, likes, servings, time, calories, protein, fats,
-				carbohydrates, ingredients, cooking, preview, visibility, encrypted, creation_timestamp, update_timestamp
+				carbohydrates, ingredients, cooking, preview, visibility, encrypted, creation_timestamp, update_timestamp,
+				allergens
 			FROM
 				%s
 			WHERE
@@ -70,10 +104,12 @@ func (r *RecipePostgres) GetRecipe(recipeId int) (entity.Recipe, error) {
 	row := r.db.QueryRow(getRecipeQuery, recipeId)
 	if err := row.Scan(&recipe.Id, &recipe.Name, &recipe.OwnerId, &recipe.Language, &recipe.Description, &recipe.Likes,
 		&recipe.Servings, &recipe.Time, &recipe.Calories, &recipe.Macronutrients.Protein, &recipe.Macronutrients.Fats, &recipe.Macronutrients.Carbohydrates,
-		&bsonIngredients, &bsonCooking, &recipe.Preview, &recipe.Visibility, &recipe.IsEncrypted, &recipe.CreationTimestamp, &recipe.UpdateTimestamp); err != nil {
+		&bsonIngredients, &bsonCooking, &recipe.Preview, &recipe.Visibility, &recipe.IsEncrypted, &recipe.CreationTimestamp, &recipe.UpdateTimestamp,
+		&allergens); err != nil {
 		logRepoError(err)
 		return entity.Recipe{}, failure.RecipeNotFound
 	}
+	recipe.Allergens = allergens.Values
 
 	var ingredients []dto.IngredientItem
 	var cooking []dto.CookingItem
@@ -91,10 +127,11 @@ func (r *RecipePostgres) GetRecipe(recipeId int) (entity.Recipe, error) {
 	return recipe, nil
 }
 
-func (r *RecipePostgres) GetRandomRecipe(languages *[]string, userId int) (entity.UserRecipe, error) {
+func (r *RecipePostgres) GetRandomRecipe(languages *[]string, excludedAllergens []string, userId int) (entity.UserRecipe, error) {
 	var recipe entity.UserRecipe
 	var bsonIngredients []byte
 	var bsonCooking []byte
+	var allergens dto.Allergens
 
 	getRecipeQuery := fmt.Sprintf(`
 			SELECT
@@ -109,7 +146,7 @@ func (r *RecipePostgres) GetRandomRecipe(languages *[]string, userId int) (entit
 						FROM %[3]v
 						WHERE %[3]v.recipe_id=%[1]v.recipe_id AND user_id=$1
 					)
-				) AS liked, %[4]v.username
+				) AS liked, %[4]v.username, %[1]v.allergens
 			FROM
 				%[1]v
 			LEFT JOIN
@@ -119,6 +156,7 @@ func (r *RecipePostgres) GetRandomRecipe(languages *[]string, userId int) (entit
 		`, recipesTable, usersRecipesTable, likesTable, usersTable)
 	getRecipeQuery += fmt.Sprintf(" WHERE visibility='%s'", entity.VisibilityPublic)
 	getRecipeQuery += r.getLanguagesFilter(languages)
+	getRecipeQuery += r.getAllergensFilter(excludedAllergens, false)
 	getRecipeQuery += " ORDER BY RANDOM() LIMIT 1"
 
 	row := r.db.QueryRow(getRecipeQuery, userId)
@@ -126,10 +164,11 @@ func (r *RecipePostgres) GetRandomRecipe(languages *[]string, userId int) (entit
 		&recipe.Servings, &recipe.Time, &recipe.Calories, &recipe.Macronutrients.Protein, &recipe.Macronutrients.Fats,
 		&recipe.Macronutrients.Carbohydrates, &bsonIngredients, &bsonCooking, &recipe.Preview, &recipe.Visibility,
 		&recipe.IsEncrypted, &recipe.CreationTimestamp, &recipe.UpdateTimestamp, &recipe.IsFavourite, &recipe.IsLiked,
-		&recipe.OwnerName); err != nil {
+		&recipe.OwnerName, &allergens); err != nil {
 		logRepoError(err)
 		return entity.UserRecipe{}, failure.UnableGetRandomRecipe
 	}
+	recipe.Allergens = allergens.Values
 
 	var ingredients []dto.IngredientItem
 	var cooking []dto.CookingItem
@@ -151,6 +190,7 @@ func (r *RecipePostgres) GetRecipeWithUserFields(recipeId, userId int) (entity.U
 	var recipe entity.UserRecipe
 	var bsonIngredients []byte
 	var bsonCooking []byte
+	var allergens dto.Allergens
 
 	getRecipeQuery := fmt.Sprintf(`
 			SELECT
@@ -165,7 +205,7 @@ func (r *RecipePostgres) GetRecipeWithUserFields(recipeId, userId int) (entity.U
 						FROM %[3]v
 						WHERE %[3]v.recipe_id=%[1]v.recipe_id AND user_id=$1
 					)
-				) AS liked, %[4]v.username
+				) AS liked, %[4]v.username, %[1]v.allergens
 			FROM
 				%[1]v
 			LEFT JOIN
@@ -179,10 +219,11 @@ func (r *RecipePostgres) GetRecipeWithUserFields(recipeId, userId int) (entity.U
 	if err := row.Scan(&recipe.Id, &recipe.Name, &recipe.OwnerId, &recipe.Language, &recipe.Description, &recipe.Likes, &recipe.Servings,
 		&recipe.Time, &recipe.Calories, &recipe.Macronutrients.Protein, &recipe.Macronutrients.Fats, &recipe.Macronutrients.Carbohydrates,
 		&bsonIngredients, &bsonCooking, &recipe.Preview, &recipe.Visibility, &recipe.IsEncrypted, &recipe.CreationTimestamp, &recipe.UpdateTimestamp,
-		&recipe.IsFavourite, &recipe.IsLiked, &recipe.OwnerName); err != nil {
+		&recipe.IsFavourite, &recipe.IsLiked, &recipe.OwnerName, &allergens); err != nil {
 		logRepoError(err)
 		return entity.UserRecipe{}, failure.RecipeNotFound
 	}
+	recipe.Allergens = allergens.Values
 
 	var ingredients []dto.IngredientItem
 	var cooking []dto.CookingItem
@@ -218,14 +259,49 @@ func (r *RecipePostgres) GetRecipeOwnerId(recipeId int) (int, error) {
 	return userId, err
 }
 
-func (r *RecipePostgres) AddRecipeToRecipeBook(recipeId, userId int) error {
+func (r *RecipePostgres) GetNonPublicRecipeIds() ([]int, error) {
+	var recipeIds []int
+
+	getRecipeIdsQuery := fmt.Sprintf(`
+			SELECT recipe_id
+			FROM %s
+			WHERE visibility<>'%s'
+		`, recipesTable, entity.VisibilityPublic)
+
+	if err := r.db.Select(&recipeIds, getRecipeIdsQuery); err != nil {
+		logRepoError(err)
+		return []int{}, failure.Unknown
+	}
+
+	return recipeIds, nil
+}
+
+// GetRecipeIdsWithPictureLinks returns recipes which preview or cooking may contain absolute links instead of object keys
+func (r *RecipePostgres) GetRecipeIdsWithPictureLinks() ([]int, error) {
+	var recipeIds []int
+
+	getRecipeIdsQuery := fmt.Sprintf(`
+			SELECT recipe_id
+			FROM %s
+			WHERE preview LIKE 'http%%' OR jsonb_path_exists(cooking, '$[*].pictures[*] ? (@ starts with "http")')
+		`, recipesTable)
+
+	if err := r.db.Select(&recipeIds, getRecipeIdsQuery); err != nil {
+		logRepoError(err)
+		return []int{}, failure.Unknown
+	}
+
+	return recipeIds, nil
+}
+
+func (r *RecipePostgres) AddRecipeToRecipeBook(recipeId, userId int, linkId *int) error {
 
 	addRecipeQuery := fmt.Sprintf(`
-			INSERT INTO %s (recipe_id, user_id)
-			VALUES ($1, $2)
+			INSERT INTO %s (recipe_id, user_id, link_id)
+			VALUES ($1, $2, $3)
 		`, usersRecipesTable)
 
-	if _, err := r.db.Exec(addRecipeQuery, recipeId, userId); err != nil {
+	if _, err := r.db.Exec(addRecipeQuery, recipeId, userId, linkId); err != nil {
 		logRepoError(err)
 		return failure.UnableAddRecipe
 	}
@@ -462,11 +538,11 @@ func (r *RecipePostgres) getRecipesByParamsQuery(params entity.RecipesQuery, use
 						WHERE
 							%[3]v.recipe_id=%[1]v.recipe_id AND user_id=%[5]v
 					)
-				) AS liked, %[4]v.username
+				) AS liked, %[4]v.username, %[1]v.allergens
 			FROM
 				%[1]v
 			LEFT JOIN
-				%[2]v ON %[2]v.recipe_id=%[1]v.recipe_id
+				%[2]v ON %[2]v.recipe_id=%[1]v.recipe_id AND %[2]v.user_id=%[5]v
 			LEFT JOIN
 				%[4]v ON %[4]v.user_id=%[1]v.owner_id
 		`, recipesTable, usersRecipesTable, likesTable, usersTable, userId)
@@ -481,8 +557,9 @@ func (r *RecipePostgres) getWhereStatement(params entity.RecipesQuery, userId in
 	whereStatement := " WHERE"
 
 	if params.Saved {
-		whereStatement += fmt.Sprintf(" %[1]v.user_id=%[2]d AND (%[3]v.owner_id=%[4]v OR %[3]v.visibility<>'%[5]v')",
-			usersRecipesTable, userId, recipesTable, userId, entity.VisibilityPrivate)
+		whereStatement += fmt.Sprintf(" %[1]v.user_id=%[2]d AND (%[3]v.owner_id=%[4]v OR %[3]v.visibility<>'%[5]v'"+
+			" OR %[1]v.link_id IN (SELECT link_id FROM %[6]v WHERE expires_at IS NULL OR expires_at>now()))",
+			usersRecipesTable, userId, recipesTable, userId, entity.VisibilityPrivate, recipeLinksTable)
 	} else {
 		whereStatement += fmt.Sprintf(" %[1]v.visibility='%[2]v' AND %[1]v.encrypted=false", recipesTable, entity.VisibilityPublic)
 	}
@@ -491,12 +568,26 @@ func (r *RecipePostgres) getWhereStatement(params entity.RecipesQuery, userId in
 		whereStatement += fmt.Sprintf(" AND %s.owner_id=%d", recipesTable, *params.AuthorId)
 	}
 
+	if params.Followed && params.FollowedCollections {
+		whereStatement += fmt.Sprintf(" AND (%[1]v.owner_id IN (SELECT author_id FROM %[2]v WHERE follower_id=%[3]d)"+
+			" OR %[1]v.recipe_id IN (SELECT %[4]v.recipe_id FROM %[4]v INNER JOIN %[5]v ON %[5]v.collection_id=%[4]v.collection_id"+
+			" WHERE %[5]v.user_id=%[3]d AND %[5]v.followed=true))",
+			recipesTable, followsTable, userId, collectionsRecipesTable, usersCollectionsTable)
+	} else if params.Followed {
+		whereStatement += fmt.Sprintf(" AND %s.owner_id IN (SELECT author_id FROM %s WHERE follower_id=%d)",
+			recipesTable, followsTable, userId)
+	}
+
 	whereStatement += r.getLanguagesFilter(params.Languages)
 
 	if params.Search != nil {
 		whereStatement += fmt.Sprintf(" AND %s.name LIKE ", recipesTable) + "'%' || $1 || '%'"
 	}
 
+	whereStatement += r.getTagsFilter(params.Tags, params.ExcludeTags)
+	whereStatement += r.getCategoryFilter(params.CategoryId, params.IncludeSubcategories, userId)
+	whereStatement += r.getAllergensFilter(params.ExcludedAllergens, params.Saved)
+
 	whereStatement += r.getRecipesRangeFilter("time", params.MinTime, params.MaxTime)
 	whereStatement += r.getRecipesRangeFilter("servings", params.MinServings, params.MaxServings)
 	whereStatement += r.getRecipesRangeFilter("calories", params.MinCalories, params.MaxCalories)
@@ -505,7 +596,11 @@ func (r *RecipePostgres) getWhereStatement(params entity.RecipesQuery, userId in
 }
 
 func (r *RecipePostgres) getPagingStatement(params entity.RecipesQuery) string {
-	pagingStatement := fmt.Sprintf(" ORDER BY %s", params.SortBy)
+	sortingColumn := params.SortBy
+	if params.SortBy == entity.SortingTrending {
+		sortingColumn = "trending_score"
+	}
+	pagingStatement := fmt.Sprintf(" ORDER BY %s.%s", recipesTable, sortingColumn)
 
 	switch params.SortBy {
 	case entity.SortingTime, entity.SortingCalories:
@@ -521,14 +616,73 @@ func (r *RecipePostgres) getPagingStatement(params entity.RecipesQuery) string {
 func (r *RecipePostgres) getRecipesRangeFilter(field string, min, max *int) string {
 	filter := ""
 	if min != nil {
-		filter += fmt.Sprintf(" AND %s.%s>=%d", recipesTable, field, min)
+		filter += fmt.Sprintf(" AND %s.%s>=%d", recipesTable, field, *min)
 	}
 	if max != nil {
-		filter += fmt.Sprintf(" AND %s.%s<=%d", recipesTable, field, max)
+		filter += fmt.Sprintf(" AND %s.%s<=%d", recipesTable, field, *max)
 	}
 	return filter
 }
 
+func (r *RecipePostgres) getTagsFilter(tags, excludedTags *[]string) string {
+	filter := ""
+	if tags != nil {
+		for _, tag := range *tags {
+			filter += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM %[1]v WHERE %[1]v.recipe_id=%[2]v.recipe_id AND %[1]v.tag_id='%[3]v')",
+				recipesTagsTable, recipesTable, tag)
+		}
+	}
+	if excludedTags != nil && len(*excludedTags) > 0 {
+		excludedTagsString := ""
+		for _, tag := range *excludedTags {
+			excludedTagsString += fmt.Sprintf("'%s', ", tag)
+		}
+		excludedTagsString = excludedTagsString[:len(excludedTagsString)-2]
+		filter += fmt.Sprintf(" AND NOT EXISTS (SELECT 1 FROM %[1]v WHERE %[1]v.recipe_id=%[2]v.recipe_id AND %[1]v.tag_id IN (%[3]v))",
+			recipesTagsTable, recipesTable, excludedTagsString)
+	}
+	return filter
+}
+
+// getCategoryFilter keeps recipes in user category. Subcategories are collected recursively;
+// UNION drops repeated categories, so the query ends even if categories are looped
+func (r *RecipePostgres) getCategoryFilter(categoryId *int, includeSubcategories bool, userId int) string {
+	if categoryId == nil {
+		return ""
+	}
+
+	categoriesStatement := fmt.Sprintf("%d", *categoryId)
+	if includeSubcategories {
+		categoriesStatement = fmt.Sprintf("WITH RECURSIVE subcategories AS (SELECT category_id FROM %[1]v"+
+			" WHERE category_id=%[2]d AND user_id=%[3]d UNION SELECT %[1]v.category_id FROM %[1]v"+
+			" INNER JOIN subcategories ON %[1]v.parent_id=subcategories.category_id) SELECT category_id FROM subcategories",
+			categoriesTable, *categoryId, userId)
+	}
+
+	return fmt.Sprintf(" AND EXISTS (SELECT 1 FROM %[1]v WHERE %[1]v.recipe_id=%[2]v.recipe_id AND %[1]v.user_id=%[3]d"+
+		" AND %[1]v.category_id IN (%[4]v))", recipesCategoriesTable, recipesTable, userId, categoriesStatement)
+}
+
+// getAllergensFilter hides recipes with excluded allergens. Recipes with unknown allergens are hidden too,
+// except recipes in user recipe book, where encrypted recipes are usual
+func (r *RecipePostgres) getAllergensFilter(excludedAllergens []string, inRecipeBook bool) string {
+	if len(excludedAllergens) == 0 {
+		return ""
+	}
+
+	allergensString := ""
+	for _, allergen := range excludedAllergens {
+		allergensString += fmt.Sprintf("'%s', ", allergen)
+	}
+	allergensString = allergensString[:len(allergensString)-2]
+
+	overlapFilter := fmt.Sprintf("NOT %[1]v.allergens && ARRAY[%[2]v]::varchar[]", recipesTable, allergensString)
+	if inRecipeBook {
+		return fmt.Sprintf(" AND (%[1]v.allergens IS NULL OR %[2]v)", recipesTable, overlapFilter)
+	}
+	return fmt.Sprintf(" AND %[1]v.allergens IS NOT NULL AND %[2]v", recipesTable, overlapFilter)
+}
+
 func (r *RecipePostgres) getLanguagesFilter(languages *[]string) string {
 	filter := ""
 	if languages != nil && len(*languages) > 0 {
@@ -540,3 +694,21 @@ func (r *RecipePostgres) getLanguagesFilter(languages *[]string) string {
 	}
 	return filter
 }
+
+func scanRecipeInfos(rows *sql.Rows) []entity.RecipeInfo {
+	recipes := []entity.RecipeInfo{}
+	for rows.Next() {
+		var recipe entity.RecipeInfo
+		var allergens dto.Allergens
+		err := rows.Scan(&recipe.Id, &recipe.Name, &recipe.OwnerId, &recipe.Language, &recipe.Likes, &recipe.Servings,
+			&recipe.Time, &recipe.Calories, &recipe.Preview, &recipe.Visibility, &recipe.IsEncrypted, &recipe.CreationTimestamp,
+			&recipe.UpdateTimestamp, &recipe.IsFavourite, &recipe.IsLiked, &recipe.OwnerName, &allergens)
+		if err != nil {
+			logRepoError(err)
+			continue
+		}
+		recipe.Allergens = allergens.Values
+		recipes = append(recipes, recipe)
+	}
+	return recipes
+}
@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"time"
+)
+
+type RecipeLinkPostgres struct {
+	db *sqlx.DB
+}
+
+func NewRecipeLinkPostgres(db *sqlx.DB) *RecipeLinkPostgres {
+	return &RecipeLinkPostgres{
+		db: db,
+	}
+}
+
+func (r *RecipeLinkPostgres) CreateRecipeLink(recipeId int, token string, expiresAt *time.Time) (entity.RecipeLink, error) {
+	link := entity.RecipeLink{
+		Token:     token,
+		RecipeId:  recipeId,
+		ExpiresAt: expiresAt,
+	}
+
+	query := fmt.Sprintf(`
+			INSERT INTO %s (token, recipe_id, expires_at)
+			VALUES ($1, $2, $3)
+			RETURNING link_id, creation_timestamp
+		`, recipeLinksTable)
+
+	row := r.db.QueryRow(query, token, recipeId, expiresAt)
+	if err := row.Scan(&link.Id, &link.CreationTimestamp); err != nil {
+		logRepoError(err)
+		return entity.RecipeLink{}, failure.Unknown
+	}
+
+	return link, nil
+}
+
+func (r *RecipeLinkPostgres) GetRecipeLinks(recipeId int) ([]entity.RecipeLink, error) {
+	query := fmt.Sprintf(`
+			SELECT link_id, token, recipe_id, expires_at, creation_timestamp
+			FROM %s
+			WHERE recipe_id=$1
+			ORDER BY creation_timestamp DESC
+		`, recipeLinksTable)
+
+	rows, err := r.db.Query(query, recipeId)
+	if err != nil {
+		logRepoError(err)
+		return []entity.RecipeLink{}, failure.Unknown
+	}
+	defer rows.Close()
+
+	links := []entity.RecipeLink{}
+	for rows.Next() {
+		var link entity.RecipeLink
+		if err := rows.Scan(&link.Id, &link.Token, &link.RecipeId, &link.ExpiresAt, &link.CreationTimestamp); err != nil {
+			logRepoError(err)
+			continue
+		}
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+func (r *RecipeLinkPostgres) GetActiveRecipeLink(token string) (entity.RecipeLink, error) {
+	var link entity.RecipeLink
+
+	query := fmt.Sprintf(`
+			SELECT link_id, token, recipe_id, expires_at, creation_timestamp
+			FROM %s
+			WHERE token=$1 AND (expires_at IS NULL OR expires_at>now())
+		`, recipeLinksTable)
+
+	row := r.db.QueryRow(query, token)
+	if err := row.Scan(&link.Id, &link.Token, &link.RecipeId, &link.ExpiresAt, &link.CreationTimestamp); err != nil {
+		logRepoError(err)
+		return entity.RecipeLink{}, failure.RecipeLinkNotFound
+	}
+
+	return link, nil
+}
+
+func (r *RecipeLinkPostgres) DeleteRecipeLink(linkId, recipeId int) error {
+	query := fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE link_id=$1 AND recipe_id=$2
+		`, recipeLinksTable)
+
+	res, err := r.db.Exec(query, linkId, recipeId)
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	if changes, err := res.RowsAffected(); err != nil || changes == 0 {
+		return failure.RecipeLinkNotFound
+	}
+
+	return nil
+}
+
+// IsRecipeSavedByActiveLink checks whether user saved private recipe by share link that isn't revoked or expired yet
+func (r *RecipeLinkPostgres) IsRecipeSavedByActiveLink(recipeId, userId int) bool {
+	var saved bool
+
+	query := fmt.Sprintf(`
+			SELECT EXISTS
+			(
+				SELECT 1
+				FROM %[1]v
+				INNER JOIN %[2]v ON %[2]v.link_id=%[1]v.link_id
+				WHERE
+					%[1]v.recipe_id=$1 AND %[1]v.user_id=$2
+					AND (%[2]v.expires_at IS NULL OR %[2]v.expires_at>now())
+			)
+		`, usersRecipesTable, recipeLinksTable)
+
+	if err := r.db.QueryRow(query, recipeId, userId).Scan(&saved); err != nil {
+		logRepoError(err)
+		return false
+	}
+
+	return saved
+}
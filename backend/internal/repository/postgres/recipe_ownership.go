@@ -42,16 +42,16 @@ func (r *RecipeOwnershipPostgres) CreateRecipe(recipe entity.RecipeInput, userId
 	createRecipeQuery := fmt.Sprintf(`
 			INSERT INTO %s
 				(name, owner_id, language, description, servings, time, calories, protein, fats, carbohydrates, ingredients,
-				cooking, preview, visibility, encrypted)
+				cooking, preview, visibility, encrypted, allergens)
 			VALUES
-				($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+				($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 			RETURNING
 				recipe_id
 		`,	recipesTable)
 
 	row := tx.QueryRow(createRecipeQuery, recipe.Name, userId, recipe.Language, recipe.Description, recipe.Servings,
 		recipe.Time, recipe.Calories, recipe.Macronutrients.Protein, recipe.Macronutrients.Fats, recipe.Macronutrients.Carbohydrates,
-		bsonIngredients, bsonCooking, recipe.Preview, recipe.Visibility, recipe.IsEncrypted)
+		bsonIngredients, bsonCooking, recipe.Preview, recipe.Visibility, recipe.IsEncrypted, dto.NewAllergensArray(recipe.Allergens))
 	if err := row.Scan(&id); err != nil {
 		logRepoError(err)
 		if err := tx.Rollback(); err != nil {
@@ -102,15 +102,38 @@ func (r *RecipeOwnershipPostgres) UpdateRecipe(recipeId int, recipe entity.Recip
 				%s
 			SET
 				name=$1, language=$2, description=$3, servings=$4, time=$5, calories=$6, protein=$7, fats=$8,
-				carbohydrates=$9, ingredients=$10, cooking=$11, preview=$12, visibility=$13, encrypted=$14, update_timestamp=$15
+				carbohydrates=$9, ingredients=$10, cooking=$11, preview=$12, visibility=$13, encrypted=$14, update_timestamp=$15,
+				allergens=$16
 			WHERE
-				recipe_id=$16
+				recipe_id=$17
 		`, recipesTable)
 
 	if _, err := r.db.Exec(updateRecipeQuery, recipe.Name, recipe.Language, recipe.Description, recipe.Servings,
 		recipe.Time, recipe.Calories, recipe.Macronutrients.Protein,
 		recipe.Macronutrients.Fats, recipe.Macronutrients.Carbohydrates, bsonIngredients, bsonCooking, recipe.Preview,
-		recipe.Visibility, recipe.IsEncrypted, time.Now().UTC(), recipeId); err != nil {
+		recipe.Visibility, recipe.IsEncrypted, time.Now().UTC(), dto.NewAllergensArray(recipe.Allergens), recipeId); err != nil {
+		logRepoError(err)
+		return failure.RecipeNotFound
+	}
+
+	return nil
+}
+
+// SetRecipePictures replaces recipe preview and cooking without update timestamp change
+func (r *RecipeOwnershipPostgres) SetRecipePictures(recipeId int, preview *string, cooking []entity.CookingItem) error {
+	bsonCooking, err := json.Marshal(dto.NewCooking(cooking))
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	setRecipePicturesQuery := fmt.Sprintf(`
+			UPDATE %s
+			SET preview=$1, cooking=$2
+			WHERE recipe_id=$3
+		`, recipesTable)
+
+	if _, err := r.db.Exec(setRecipePicturesQuery, preview, bsonCooking, recipeId); err != nil {
 		logRepoError(err)
 		return failure.RecipeNotFound
 	}
@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+)
+
+type RecipePicturePostgres struct {
+	db *sqlx.DB
+}
+
+func NewRecipePicturePostgres(db *sqlx.DB) *RecipePicturePostgres {
+	return &RecipePicturePostgres{
+		db: db,
+	}
+}
+
+func (r *RecipePicturePostgres) GetRecipePictures(recipeId int) ([]entity.RecipePicture, error) {
+	query := fmt.Sprintf(`
+			SELECT picture_id, recipe_id, object_key, caption, position, is_preview, creation_timestamp
+			FROM %s
+			WHERE recipe_id=$1
+			ORDER BY position, picture_id
+		`, recipePicturesTable)
+
+	rows, err := r.db.Query(query, recipeId)
+	if err != nil {
+		logRepoError(err)
+		return []entity.RecipePicture{}, failure.Unknown
+	}
+	defer rows.Close()
+
+	pictures := []entity.RecipePicture{}
+	for rows.Next() {
+		picture, err := scanRecipePicture(rows)
+		if err != nil {
+			logRepoError(err)
+			continue
+		}
+		pictures = append(pictures, picture)
+	}
+
+	return pictures, nil
+}
+
+func (r *RecipePicturePostgres) GetRecipePicture(pictureId, recipeId int) (entity.RecipePicture, error) {
+	query := fmt.Sprintf(`
+			SELECT picture_id, recipe_id, object_key, caption, position, is_preview, creation_timestamp
+			FROM %s
+			WHERE picture_id=$1 AND recipe_id=$2
+		`, recipePicturesTable)
+
+	picture, err := scanRecipePicture(r.db.QueryRow(query, pictureId, recipeId))
+	if err != nil {
+		logRepoError(err)
+		return entity.RecipePicture{}, failure.PictureNotFound
+	}
+
+	return picture, nil
+}
+
+// AddRecipePicture appends picture to the end of recipe gallery. Already added picture is returned as is
+func (r *RecipePicturePostgres) AddRecipePicture(recipeId int, objectKey string) (entity.RecipePicture, error) {
+	query := fmt.Sprintf(`
+			INSERT INTO %[1]v (recipe_id, object_key, position)
+			SELECT $1, $2, COALESCE(MAX(position), 0) + 1
+			FROM %[1]v
+			WHERE recipe_id=$1
+			ON CONFLICT (recipe_id, object_key) DO UPDATE SET object_key=EXCLUDED.object_key
+			RETURNING picture_id, recipe_id, object_key, caption, position, is_preview, creation_timestamp
+		`, recipePicturesTable)
+
+	picture, err := scanRecipePicture(r.db.QueryRow(query, recipeId, objectKey))
+	if err != nil {
+		logRepoError(err)
+		return entity.RecipePicture{}, failure.UnableUploadFile
+	}
+
+	return picture, nil
+}
+
+func (r *RecipePicturePostgres) SetRecipePictureCaption(pictureId, recipeId int, caption *string) error {
+	query := fmt.Sprintf(`
+			UPDATE %s
+			SET caption=$1
+			WHERE picture_id=$2 AND recipe_id=$3
+		`, recipePicturesTable)
+
+	res, err := r.db.Exec(query, caption, pictureId, recipeId)
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	if changes, err := res.RowsAffected(); err != nil || changes == 0 {
+		return failure.PictureNotFound
+	}
+
+	return nil
+}
+
+// SetRecipePreview sets recipe preview and marks gallery picture with the same object key as preview one
+func (r *RecipePicturePostgres) SetRecipePreview(recipeId int, preview *string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	setPreviewQuery := fmt.Sprintf(`
+			UPDATE %s
+			SET preview=$1
+			WHERE recipe_id=$2
+		`, recipesTable)
+
+	if _, err := tx.Exec(setPreviewQuery, preview, recipeId); err != nil {
+		return rollbackTransaction(tx, err, failure.RecipeNotFound)
+	}
+
+	resetPreviewFlagQuery := fmt.Sprintf(`
+			UPDATE %s
+			SET is_preview=FALSE
+			WHERE recipe_id=$1 AND is_preview AND object_key IS DISTINCT FROM $2
+		`, recipePicturesTable)
+
+	if _, err := tx.Exec(resetPreviewFlagQuery, recipeId, preview); err != nil {
+		return rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	if preview != nil {
+		setPreviewFlagQuery := fmt.Sprintf(`
+				UPDATE %s
+				SET is_preview=TRUE
+				WHERE recipe_id=$1 AND object_key=$2
+			`, recipePicturesTable)
+
+		if _, err := tx.Exec(setPreviewFlagQuery, recipeId, *preview); err != nil {
+			return rollbackTransaction(tx, err, failure.Unknown)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	return nil
+}
+
+// SetRecipePicturesOrder sets positions of recipe pictures by order of passed ids
+func (r *RecipePicturePostgres) SetRecipePicturesOrder(recipeId int, pictureIds []int) error {
+	query := fmt.Sprintf(`
+			UPDATE %[1]v
+			SET position=ids.position
+			FROM unnest($2::int[]) WITH ORDINALITY AS ids(picture_id, position)
+			WHERE %[1]v.recipe_id=$1 AND %[1]v.picture_id=ids.picture_id
+		`, recipePicturesTable)
+
+	res, err := r.db.Exec(query, recipeId, pq.Array(pictureIds))
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	if changes, err := res.RowsAffected(); err != nil || int(changes) != len(pictureIds) {
+		return failure.InvalidPictureOrder
+	}
+
+	return nil
+}
+
+// DeleteRecipePicture deletes picture from recipe gallery and resets recipe preview if picture was used as it
+func (r *RecipePicturePostgres) DeleteRecipePicture(pictureId, recipeId int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	var objectKey string
+	var isPreview bool
+
+	deletePictureQuery := fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE picture_id=$1 AND recipe_id=$2
+			RETURNING object_key, is_preview
+		`, recipePicturesTable)
+
+	if err := tx.QueryRow(deletePictureQuery, pictureId, recipeId).Scan(&objectKey, &isPreview); err != nil {
+		return rollbackTransaction(tx, err, failure.PictureNotFound)
+	}
+
+	if isPreview {
+		resetPreviewQuery := fmt.Sprintf(`
+				UPDATE %s
+				SET preview=NULL
+				WHERE recipe_id=$1 AND preview=$2
+			`, recipesTable)
+
+		if _, err := tx.Exec(resetPreviewQuery, recipeId, objectKey); err != nil {
+			return rollbackTransaction(tx, err, failure.Unknown)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	return nil
+}
+
+func scanRecipePicture(row rowScanner) (entity.RecipePicture, error) {
+	var picture entity.RecipePicture
+	err := row.Scan(&picture.Id, &picture.RecipeId, &picture.Link, &picture.Caption, &picture.Position, &picture.IsPreview,
+		&picture.CreationTimestamp)
+	return picture, err
+}
@@ -1,8 +1,10 @@
 package postgres
 
 import (
+	"database/sql"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/mephistolie/chefbook-server/internal/entity"
 	"github.com/mephistolie/chefbook-server/internal/entity/failure"
 )
@@ -130,4 +132,182 @@ func (r *RecipeSharingPostgres) SetOwnerPrivateKeyLinkForUser(recipeId int, user
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func (r *RecipeSharingPostgres) CreateKeyRequest(recipeId, userId int, userKey string) (int, error) {
+	var requestId int
+
+	query := fmt.Sprintf(`
+			INSERT INTO %s (recipe_id, user_id, encrypted_user_key)
+			VALUES ($1, $2, $3)
+			RETURNING request_id
+		`, keyRequestsTable)
+
+	if err := r.db.QueryRow(query, recipeId, userId, userKey).Scan(&requestId); err != nil {
+		logRepoError(err)
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolationCode {
+			return 0, failure.KeyRequestAlreadyExists
+		}
+		return 0, failure.Unknown
+	}
+
+	return requestId, nil
+}
+
+func (r *RecipeSharingPostgres) GetKeyRequest(requestId int) (entity.RecipeKeyRequest, error) {
+	query := r.getKeyRequestsSelectStatement() + fmt.Sprintf(" WHERE %s.request_id=$1", keyRequestsTable)
+
+	rows, err := r.db.Query(query, requestId)
+	if err != nil {
+		logRepoError(err)
+		return entity.RecipeKeyRequest{}, failure.Unknown
+	}
+	defer rows.Close()
+
+	requests := scanKeyRequests(rows)
+	if len(requests) == 0 {
+		return entity.RecipeKeyRequest{}, failure.KeyRequestNotFound
+	}
+
+	return requests[0], nil
+}
+
+func (r *RecipeSharingPostgres) GetRecipeKeyRequests(recipeId int, status *string) ([]entity.RecipeKeyRequest, error) {
+	return r.getKeyRequests("recipe_id", recipeId, status)
+}
+
+func (r *RecipeSharingPostgres) GetUserKeyRequests(userId int, status *string) ([]entity.RecipeKeyRequest, error) {
+	return r.getKeyRequests("user_id", userId, status)
+}
+
+// ApproveKeyRequest stores recipe key wrapped by owner for requester in request and grants access to recipe
+// by adding it to requester recipe book. Key columns of recipe book are left for manually exchanged key links
+func (r *RecipeSharingPostgres) ApproveKeyRequest(requestId int, recipeKey string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	var recipeId, userId int
+
+	approveQuery := fmt.Sprintf(`
+			UPDATE %s
+			SET status='%s', encrypted_recipe_key=$1, update_timestamp=timezone('utc', now())
+			WHERE request_id=$2 AND status='%s'
+			RETURNING recipe_id, user_id
+		`, keyRequestsTable, entity.KeyRequestApproved, entity.KeyRequestPending)
+
+	if err := tx.QueryRow(approveQuery, recipeKey, requestId).Scan(&recipeId, &userId); err != nil {
+		return rollbackTransaction(tx, err, failure.KeyRequestAlreadyHandled)
+	}
+
+	addRecipeQuery := fmt.Sprintf(`
+			INSERT INTO %[1]v (recipe_id, user_id)
+			SELECT $1, $2
+			WHERE NOT EXISTS
+			(
+				SELECT 1
+				FROM %[1]v
+				WHERE recipe_id=$1 AND user_id=$2
+			)
+		`, usersRecipesTable)
+
+	if _, err := tx.Exec(addRecipeQuery, recipeId, userId); err != nil {
+		return rollbackTransaction(tx, err, failure.UnableAddRecipe)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	return nil
+}
+
+// GetApprovedRecipeKey returns recipe key from the latest approved request of user
+func (r *RecipeSharingPostgres) GetApprovedRecipeKey(recipeId, userId int) (string, error) {
+	var key *string
+
+	query := fmt.Sprintf(`
+			SELECT encrypted_recipe_key
+			FROM %s
+			WHERE recipe_id=$1 AND user_id=$2 AND status='%s' AND encrypted_recipe_key IS NOT NULL
+			ORDER BY update_timestamp DESC
+			LIMIT 1
+		`, keyRequestsTable, entity.KeyRequestApproved)
+
+	if err := r.db.Get(&key, query, recipeId, userId); err != nil || key == nil {
+		if err != nil && err != sql.ErrNoRows {
+			logRepoError(err)
+		}
+		return "", failure.NoKey
+	}
+
+	return *key, nil
+}
+
+func (r *RecipeSharingPostgres) SetKeyRequestStatus(requestId int, status string) error {
+	query := fmt.Sprintf(`
+			UPDATE %s
+			SET status=$1, update_timestamp=timezone('utc', now())
+			WHERE request_id=$2 AND status='%s'
+		`, keyRequestsTable, entity.KeyRequestPending)
+
+	res, err := r.db.Exec(query, status, requestId)
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	if rowsCount, err := res.RowsAffected(); err != nil || rowsCount == 0 {
+		return failure.KeyRequestAlreadyHandled
+	}
+
+	return nil
+}
+
+func (r *RecipeSharingPostgres) getKeyRequests(ownerColumn string, ownerId int, status *string) ([]entity.RecipeKeyRequest, error) {
+	query := r.getKeyRequestsSelectStatement() + fmt.Sprintf(`
+			WHERE %[1]v.%[2]v=$1 AND ($2::varchar IS NULL OR %[1]v.status=$2)
+			ORDER BY %[1]v.update_timestamp DESC
+		`, keyRequestsTable, ownerColumn)
+
+	rows, err := r.db.Query(query, ownerId, status)
+	if err != nil {
+		logRepoError(err)
+		return []entity.RecipeKeyRequest{}, failure.Unknown
+	}
+	defer rows.Close()
+
+	return scanKeyRequests(rows), nil
+}
+
+func (r *RecipeSharingPostgres) getKeyRequestsSelectStatement() string {
+	return fmt.Sprintf(`
+			SELECT
+				%[1]v.request_id, %[1]v.recipe_id, %[2]v.name, %[1]v.user_id, %[3]v.username, %[1]v.encrypted_user_key,
+				%[1]v.encrypted_recipe_key, %[1]v.status, %[1]v.creation_timestamp, %[1]v.update_timestamp
+			FROM
+				%[1]v
+			INNER JOIN
+				%[2]v ON %[2]v.recipe_id=%[1]v.recipe_id
+			LEFT JOIN
+				%[3]v ON %[3]v.user_id=%[1]v.user_id
+		`, keyRequestsTable, recipesTable, usersTable)
+}
+
+func scanKeyRequests(rows *sql.Rows) []entity.RecipeKeyRequest {
+	requests := []entity.RecipeKeyRequest{}
+	for rows.Next() {
+		var request entity.RecipeKeyRequest
+		err := rows.Scan(&request.Id, &request.RecipeId, &request.RecipeName, &request.UserId, &request.Username,
+			&request.UserKey, &request.RecipeKey, &request.Status, &request.CreationTimestamp, &request.UpdateTimestamp)
+		if err != nil {
+			logRepoError(err)
+			continue
+		}
+		requests = append(requests, request)
+	}
+	return requests
+}
@@ -0,0 +1,291 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+)
+
+const (
+	likeSignalWeight      = 2.0
+	saveSignalWeight      = 1.0
+	favouriteSignalWeight = 2.0
+	categorySignalWeight  = 0.5
+
+	minIngredientTokenLength = 3
+)
+
+type RecommendationPostgres struct {
+	db *sqlx.DB
+}
+
+func NewRecommendationPostgres(db *sqlx.DB) *RecommendationPostgres {
+	return &RecommendationPostgres{
+		db: db,
+	}
+}
+
+// UpdateSimilarities precomputes item-to-item cosine similarity between recipes by users who liked or saved them.
+// Only public unencrypted recipes are stored as similar ones, and each recipe keeps not more than limit of them
+func (r *RecommendationPostgres) UpdateSimilarities(limit int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	deleteSimilaritiesQuery := fmt.Sprintf(`
+			DELETE FROM %s
+		`, recipeSimilaritiesTable)
+
+	if _, err := tx.Exec(deleteSimilaritiesQuery); err != nil {
+		return rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	addSimilaritiesQuery := fmt.Sprintf(`
+			WITH item_users AS
+			(
+				SELECT user_id, recipe_id
+				FROM %[2]v
+				UNION
+				SELECT %[3]v.user_id, %[3]v.recipe_id
+				FROM %[3]v
+				INNER JOIN %[1]v ON %[1]v.recipe_id=%[3]v.recipe_id
+				WHERE %[3]v.user_id<>%[1]v.owner_id
+			),
+			popularity AS
+			(
+				SELECT recipe_id, count(*) AS users_count
+				FROM item_users
+				GROUP BY recipe_id
+			),
+			co_occurrences AS
+			(
+				SELECT source.recipe_id AS source_id, candidate.recipe_id AS candidate_id, count(*) AS together
+				FROM item_users AS source
+				INNER JOIN item_users AS candidate ON candidate.user_id=source.user_id AND candidate.recipe_id<>source.recipe_id
+				INNER JOIN %[1]v ON %[1]v.recipe_id=candidate.recipe_id
+				WHERE %[1]v.visibility='%[5]v' AND %[1]v.encrypted=false
+				GROUP BY source.recipe_id, candidate.recipe_id
+			),
+			similarities AS
+			(
+				SELECT
+					co_occurrences.source_id, co_occurrences.candidate_id,
+					co_occurrences.together / sqrt(source_popularity.users_count * candidate_popularity.users_count) AS score
+				FROM
+					co_occurrences
+				INNER JOIN
+					popularity AS source_popularity ON source_popularity.recipe_id=co_occurrences.source_id
+				INNER JOIN
+					popularity AS candidate_popularity ON candidate_popularity.recipe_id=co_occurrences.candidate_id
+			)
+			INSERT INTO %[4]v (recipe_id, similar_recipe_id, score)
+			SELECT source_id, candidate_id, score
+			FROM
+			(
+				SELECT source_id, candidate_id, score, row_number() OVER (PARTITION BY source_id ORDER BY score DESC) AS rank
+				FROM similarities
+			) AS ranked
+			WHERE rank<=$1
+		`, recipesTable, likesTable, usersRecipesTable, recipeSimilaritiesTable, entity.VisibilityPublic)
+
+	if _, err := tx.Exec(addSimilaritiesQuery, limit); err != nil {
+		return rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	return nil
+}
+
+// UpdateIngredientTokens precomputes words of ingredients of unencrypted recipes for content recommendations
+func (r *RecommendationPostgres) UpdateIngredientTokens() error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	deleteTokensQuery := fmt.Sprintf(`
+			DELETE FROM %s
+		`, ingredientTokensTable)
+
+	if _, err := tx.Exec(deleteTokensQuery); err != nil {
+		return rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	addTokensQuery := fmt.Sprintf(`
+			INSERT INTO %[2]v (recipe_id, token)
+			SELECT DISTINCT %[1]v.recipe_id, token
+			FROM
+				%[1]v,
+				jsonb_array_elements(%[1]v.ingredients) AS item,
+				regexp_split_to_table(lower(item->>'text'), '[^[:alpha:]]+') AS token
+			WHERE %[1]v.encrypted=false AND item->>'type'='%[3]v' AND length(token)>=%[4]v
+		`, recipesTable, ingredientTokensTable, entity.TypeIngredient, minIngredientTokenLength)
+
+	if _, err := tx.Exec(addTokensQuery); err != nil {
+		return rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	return nil
+}
+
+// GetCollaborativeRecommendations ranks recipes similar to the ones user liked, saved, marked as favourite
+// or sorted into categories by precomputed similarities
+func (r *RecommendationPostgres) GetCollaborativeRecommendations(userId int, languages *[]string, limit int) ([]entity.RecipeInfo, error) {
+	scoresQuery := fmt.Sprintf(`
+			WITH user_items AS
+			(
+				SELECT recipe_id, sum(weight) AS weight
+				FROM
+				(
+					SELECT recipe_id, %[6]v AS weight
+					FROM %[2]v
+					WHERE user_id=$1
+					UNION ALL
+					SELECT recipe_id, %[7]v + CASE WHEN favourite THEN %[8]v ELSE 0 END AS weight
+					FROM %[3]v
+					WHERE user_id=$1
+					UNION ALL
+					SELECT DISTINCT recipe_id, %[9]v AS weight
+					FROM %[4]v
+					WHERE user_id=$1
+				) AS signals
+				GROUP BY recipe_id
+			)
+			SELECT
+				%[5]v.similar_recipe_id AS recipe_id,
+				sum(user_items.weight * %[5]v.score) AS score
+			FROM
+				user_items
+			INNER JOIN
+				%[5]v ON %[5]v.recipe_id=user_items.recipe_id
+			INNER JOIN
+				%[1]v AS candidate ON candidate.recipe_id=%[5]v.similar_recipe_id
+			WHERE
+				candidate.visibility='%[10]v' AND candidate.encrypted=false AND candidate.owner_id<>$1
+				AND ($3::varchar[] IS NULL OR candidate.language=ANY($3))
+				AND %[5]v.similar_recipe_id NOT IN (SELECT recipe_id FROM user_items)
+			GROUP BY
+				%[5]v.similar_recipe_id
+		`, recipesTable, likesTable, usersRecipesTable, recipesCategoriesTable, recipeSimilaritiesTable,
+		likeSignalWeight, saveSignalWeight, favouriteSignalWeight, categorySignalWeight, entity.VisibilityPublic)
+
+	return r.getScoredRecipes(scoresQuery, userId, languages, limit)
+}
+
+// GetContentRecommendations ranks public recipes by ingredients overlap with recipes in user recipe book
+// and by languages user reads; suitable for users without enough interactions for collaborative filtering.
+// Trending recipes are added to candidates, so users with empty recipe book get recommendations too
+func (r *RecommendationPostgres) GetContentRecommendations(userId int, languages *[]string, limit int) ([]entity.RecipeInfo, error) {
+	scoresQuery := fmt.Sprintf(`
+			WITH user_recipes AS
+			(
+				SELECT recipe_id
+				FROM %[2]v
+				WHERE user_id=$1
+				UNION
+				SELECT recipe_id
+				FROM %[3]v
+				WHERE user_id=$1
+			),
+			user_tokens AS
+			(
+				SELECT DISTINCT token
+				FROM %[4]v
+				WHERE recipe_id IN (SELECT recipe_id FROM user_recipes)
+			),
+			user_languages AS
+			(
+				SELECT DISTINCT %[1]v.language
+				FROM %[1]v
+				INNER JOIN user_recipes ON user_recipes.recipe_id=%[1]v.recipe_id
+			),
+			candidates AS
+			(
+				SELECT recipe_id, count(*) AS common_tokens
+				FROM %[4]v
+				WHERE token IN (SELECT token FROM user_tokens)
+				GROUP BY recipe_id
+				UNION ALL
+				(
+					SELECT recipe_id, 0 AS common_tokens
+					FROM %[1]v
+					WHERE visibility='%[5]v' AND encrypted=false
+					ORDER BY trending_score DESC
+					LIMIT $2
+				)
+			)
+			SELECT
+				candidate.recipe_id,
+				max(candidates.common_tokens)
+				+ CASE WHEN candidate.language IN (SELECT language FROM user_languages) THEN 1 ELSE 0 END
+				+ ln(1 + candidate.likes) / 10 AS score
+			FROM
+				candidates
+			INNER JOIN
+				%[1]v AS candidate ON candidate.recipe_id=candidates.recipe_id
+			WHERE
+				candidate.visibility='%[5]v' AND candidate.encrypted=false AND candidate.owner_id<>$1
+				AND ($3::varchar[] IS NULL OR candidate.language=ANY($3))
+				AND candidate.recipe_id NOT IN (SELECT recipe_id FROM user_recipes)
+			GROUP BY
+				candidate.recipe_id, candidate.language, candidate.likes
+		`, recipesTable, usersRecipesTable, likesTable, ingredientTokensTable, entity.VisibilityPublic)
+
+	return r.getScoredRecipes(scoresQuery, userId, languages, limit)
+}
+
+func (r *RecommendationPostgres) getScoredRecipes(scoresQuery string, userId int, languages *[]string, limit int) ([]entity.RecipeInfo, error) {
+	query := fmt.Sprintf(`
+			SELECT
+				%[1]v.recipe_id, %[1]v.name, %[1]v.owner_id, %[1]v.language, %[1]v.likes, %[1]v.servings, %[1]v.time,
+				%[1]v.calories, %[1]v.preview, %[1]v.visibility, %[1]v.encrypted, %[1]v.creation_timestamp,
+				%[1]v.update_timestamp, coalesce(%[2]v.favourite, false),
+				(
+					SELECT EXISTS
+					(
+						SELECT 1 FROM
+							%[3]v
+						WHERE
+							%[3]v.recipe_id=%[1]v.recipe_id AND user_id=$1
+					)
+				) AS liked, %[4]v.username, %[1]v.allergens
+			FROM
+				(%[5]v) AS scores
+			INNER JOIN
+				%[1]v ON %[1]v.recipe_id=scores.recipe_id
+			LEFT JOIN
+				%[2]v ON %[2]v.recipe_id=%[1]v.recipe_id AND %[2]v.user_id=$1
+			LEFT JOIN
+				%[4]v ON %[4]v.user_id=%[1]v.owner_id
+			ORDER BY scores.score DESC, %[1]v.trending_score DESC
+			LIMIT $2
+		`, recipesTable, usersRecipesTable, likesTable, usersTable, scoresQuery)
+
+	var languagesFilter interface{} = nil
+	if languages != nil && len(*languages) > 0 {
+		languagesFilter = pq.Array(*languages)
+	}
+
+	rows, err := r.db.Query(query, userId, limit, languagesFilter)
+	if err != nil {
+		logRepoError(err)
+		return []entity.RecipeInfo{}, failure.Unknown
+	}
+	defer rows.Close()
+
+	return scanRecipeInfos(rows), nil
+}
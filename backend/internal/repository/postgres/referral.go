@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+)
+
+type ReferralPostgres struct {
+	db *sqlx.DB
+}
+
+func NewReferralPostgres(db *sqlx.DB) *ReferralPostgres {
+	return &ReferralPostgres{
+		db: db,
+	}
+}
+
+func (r *ReferralPostgres) GetReferralCode(userId int) (*string, error) {
+	var code *string
+
+	getCodeQuery := fmt.Sprintf(`
+			SELECT referral_code
+			FROM %s
+			WHERE user_id=$1
+		`, usersTable)
+
+	if err := r.db.Get(&code, getCodeQuery, userId); err != nil {
+		logRepoError(err)
+		return nil, failure.UserNotFound
+	}
+
+	return code, nil
+}
+
+// SetReferralCode sets referral code if user hasn't it yet and returns actual user code
+func (r *ReferralPostgres) SetReferralCode(userId int, code string) (string, error) {
+	var actualCode string
+
+	setCodeQuery := fmt.Sprintf(`
+			UPDATE %s
+			SET referral_code=COALESCE(referral_code, $1)
+			WHERE user_id=$2
+			RETURNING referral_code
+		`, usersTable)
+
+	if err := r.db.Get(&actualCode, setCodeQuery, code, userId); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolationCode {
+			return "", failure.ReferralCodeExists
+		}
+		logRepoError(err)
+		return "", failure.UserNotFound
+	}
+
+	return actualCode, nil
+}
+
+func (r *ReferralPostgres) GetUserIdByReferralCode(code string) (int, error) {
+	var userId int
+
+	getUserQuery := fmt.Sprintf(`
+			SELECT user_id
+			FROM %s
+			WHERE referral_code=$1 AND is_activated=true AND is_blocked=false
+		`, usersTable)
+
+	if err := r.db.Get(&userId, getUserQuery, code); err != nil {
+		if err != sql.ErrNoRows {
+			logRepoError(err)
+		}
+		return 0, failure.InvalidReferralCode
+	}
+
+	return userId, nil
+}
+
+func (r *ReferralPostgres) GetReferrals(referrerId int) ([]entity.Referral, error) {
+	getReferralsQuery := fmt.Sprintf(`
+			SELECT %[1]v.referrer_id, %[1]v.invitee_id, %[2]v.username, %[1]v.status, %[1]v.creation_timestamp,
+				%[1]v.activation_timestamp
+			FROM %[1]v
+			LEFT JOIN %[2]v ON %[2]v.user_id=%[1]v.invitee_id
+			WHERE %[1]v.referrer_id=$1
+			ORDER BY %[1]v.creation_timestamp DESC
+		`, referralsTable, usersTable)
+
+	rows, err := r.db.Query(getReferralsQuery, referrerId)
+	if err != nil {
+		logRepoError(err)
+		return []entity.Referral{}, failure.Unknown
+	}
+	defer rows.Close()
+
+	referrals := []entity.Referral{}
+	for rows.Next() {
+		var referral entity.Referral
+		if err := rows.Scan(&referral.ReferrerId, &referral.InviteeId, &referral.InviteeName, &referral.Status,
+			&referral.CreationTimestamp, &referral.ActivationTimestamp); err != nil {
+			logRepoError(err)
+			continue
+		}
+		referrals = append(referrals, referral)
+	}
+
+	return referrals, nil
+}
+
+func (r *ReferralPostgres) GetPendingReferralsCount(referrerId int) (int, error) {
+	var count int
+
+	getCountQuery := fmt.Sprintf(`
+			SELECT COUNT(*)
+			FROM %s
+			WHERE referrer_id=$1 AND status=$2
+		`, referralsTable)
+
+	if err := r.db.Get(&count, getCountQuery, referrerId, entity.ReferralStatusPending); err != nil {
+		logRepoError(err)
+		return 0, failure.Unknown
+	}
+
+	return count, nil
+}
+
+// AddReferral links invitee to referrer. Invitee can be linked only once, so repeated sign up keeps first referrer
+func (r *ReferralPostgres) AddReferral(referrerId, inviteeId int) error {
+
+	addReferralQuery := fmt.Sprintf(`
+			INSERT INTO %s (referrer_id, invitee_id)
+			VALUES ($1, $2)
+			ON CONFLICT (invitee_id) DO NOTHING
+		`, referralsTable)
+
+	if _, err := r.db.Exec(addReferralQuery, referrerId, inviteeId); err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	return nil
+}
+
+// ActivateReferral completes pending referral of invitee. Referrer row is locked until transaction end,
+// so concurrent activations can't exceed referrer limits. Premium days are added only to rewarded referral
+func (r *ReferralPostgres) ActivateReferral(inviteeId int, limits entity.ReferralLimits, referrerPremiumDays,
+	inviteePremiumDays int) (entity.Referral, error) {
+	var referral entity.Referral
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return entity.Referral{}, failure.Unknown
+	}
+
+	getReferralQuery := fmt.Sprintf(`
+			SELECT referrer_id, invitee_id, creation_timestamp
+			FROM %s
+			WHERE invitee_id=$1 AND status=$2
+			FOR UPDATE
+		`, referralsTable)
+
+	if err := tx.QueryRow(getReferralQuery, inviteeId, entity.ReferralStatusPending).Scan(&referral.ReferrerId,
+		&referral.InviteeId, &referral.CreationTimestamp); err != nil {
+		if err == sql.ErrNoRows {
+			return entity.Referral{}, rollbackTransaction(tx, nil, failure.ReferralNotFound)
+		}
+		return entity.Referral{}, rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	lockReferrerQuery := fmt.Sprintf(`
+			SELECT user_id
+			FROM %s
+			WHERE user_id=$1
+			FOR UPDATE
+		`, usersTable)
+
+	if _, err := tx.Exec(lockReferrerQuery, referral.ReferrerId); err != nil {
+		return entity.Referral{}, rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	var rewardedCount, dailyRewardedCount int
+	getRewardedCountQuery := fmt.Sprintf(`
+			SELECT COUNT(*), COUNT(*) FILTER (WHERE activation_timestamp > now()-interval '1 day')
+			FROM %s
+			WHERE referrer_id=$1 AND status=$2
+		`, referralsTable)
+
+	if err := tx.QueryRow(getRewardedCountQuery, referral.ReferrerId, entity.ReferralStatusRewarded).Scan(&rewardedCount,
+		&dailyRewardedCount); err != nil {
+		return entity.Referral{}, rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	referral.Status = entity.ReferralStatusRewarded
+	if (limits.MaxRewards > 0 && rewardedCount >= limits.MaxRewards) ||
+		(limits.MaxDailyRewards > 0 && dailyRewardedCount >= limits.MaxDailyRewards) {
+		referral.Status = entity.ReferralStatusLimitReached
+	}
+
+	activateReferralQuery := fmt.Sprintf(`
+			UPDATE %s
+			SET status=$1, activation_timestamp=now()
+			WHERE invitee_id=$2
+			RETURNING activation_timestamp
+		`, referralsTable)
+
+	if err := tx.QueryRow(activateReferralQuery, referral.Status, inviteeId).Scan(&referral.ActivationTimestamp); err != nil {
+		return entity.Referral{}, rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	if referral.Status == entity.ReferralStatusRewarded {
+		if referrerPremiumDays > 0 {
+			if err := extendPremium(tx, referral.ReferrerId, referrerPremiumDays); err != nil {
+				return entity.Referral{}, rollbackTransaction(tx, err, failure.Unknown)
+			}
+		}
+		if inviteePremiumDays > 0 {
+			if err := extendPremium(tx, inviteeId, inviteePremiumDays); err != nil {
+				return entity.Referral{}, rollbackTransaction(tx, err, failure.Unknown)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logRepoError(err)
+		return entity.Referral{}, failure.Unknown
+	}
+
+	return referral, nil
+}
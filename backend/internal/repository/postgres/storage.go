@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"time"
+)
+
+type StoragePostgres struct {
+	db *sqlx.DB
+}
+
+func NewStoragePostgres(db *sqlx.DB) *StoragePostgres {
+	return &StoragePostgres{
+		db: db,
+	}
+}
+
+// GetStorageReferences returns avatars, keys, previews, gallery and cooking pictures of all users and recipes
+func (r *StoragePostgres) GetStorageReferences() (entity.StorageReferences, error) {
+	var references entity.StorageReferences
+
+	getUserFilesQuery := fmt.Sprintf(`
+			SELECT avatar FROM %[1]v WHERE avatar IS NOT NULL
+			UNION
+			SELECT key FROM %[1]v WHERE key IS NOT NULL
+		`, usersTable)
+
+	if err := r.db.Select(&references.Files, getUserFilesQuery); err != nil {
+		logRepoError(err)
+		return entity.StorageReferences{}, failure.Unknown
+	}
+
+	var recipeFiles []string
+	getRecipeFilesQuery := fmt.Sprintf(`
+			SELECT preview FROM %[1]v WHERE preview IS NOT NULL
+			UNION
+			SELECT key FROM %[1]v WHERE key IS NOT NULL
+			UNION
+			SELECT jsonb_array_elements_text(step->'pictures')
+			FROM %[1]v, jsonb_array_elements(CASE WHEN jsonb_typeof(cooking)='array' THEN cooking ELSE '[]' END) AS step
+			WHERE jsonb_typeof(step->'pictures')='array'
+			UNION
+			SELECT object_key FROM %[2]v
+		`, recipesTable, recipePicturesTable)
+
+	if err := r.db.Select(&recipeFiles, getRecipeFilesQuery); err != nil {
+		logRepoError(err)
+		return entity.StorageReferences{}, failure.Unknown
+	}
+	references.Files = append(references.Files, recipeFiles...)
+
+	return references, nil
+}
+
+// GetStorageUsage returns size and count of user files. Excluded file, e.g. replaced avatar, isn't counted
+func (r *StoragePostgres) GetStorageUsage(userId int, excludedKey *string) (entity.StorageUsage, error) {
+	var usage entity.StorageUsage
+
+	query := fmt.Sprintf(`
+			SELECT COALESCE(SUM(size), 0), COUNT(*)
+			FROM %s
+			WHERE user_id=$1 AND object_key IS DISTINCT FROM $2
+		`, storageFilesTable)
+
+	if err := r.db.QueryRow(query, userId, excludedKey).Scan(&usage.Bytes, &usage.Files); err != nil {
+		logRepoError(err)
+		return entity.StorageUsage{}, failure.Unknown
+	}
+
+	return usage, nil
+}
+
+// ReserveUserFile counts file in usage of user if usage with it fits into quota. User row is locked,
+// so parallel reservations of same user are checked one after another. Replaced file isn't counted in usage
+func (r *StoragePostgres) ReserveUserFile(file entity.UserFile, quota entity.StorageQuota, replacedKey *string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	lockUserQuery := fmt.Sprintf(`
+			SELECT user_id FROM %s
+			WHERE user_id=$1
+			FOR UPDATE
+		`, usersTable)
+
+	if _, err = tx.Exec(lockUserQuery, file.UserId); err != nil {
+		return rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	reserveFileQuery := fmt.Sprintf(`
+			INSERT INTO %[1]v (object_key, user_id, recipe_id, size)
+			SELECT $1::varchar, $2::int, $3::int, $4::bigint
+			FROM %[1]v
+			WHERE user_id=$2 AND object_key<>$1 AND object_key IS DISTINCT FROM $5
+			HAVING COALESCE(SUM(size), 0)+$4 <= $6 AND COUNT(*)+1 <= $7
+			ON CONFLICT (object_key) DO UPDATE SET user_id=EXCLUDED.user_id, recipe_id=EXCLUDED.recipe_id, size=EXCLUDED.size
+		`, storageFilesTable)
+
+	res, err := tx.Exec(reserveFileQuery, file.Key, file.UserId, file.RecipeId, file.Size, replacedKey,
+		quota.MaxBytes, quota.MaxFiles)
+	if err != nil {
+		return rollbackTransaction(tx, err, failure.Unknown)
+	}
+	if changes, err := res.RowsAffected(); err != nil || changes == 0 {
+		return rollbackTransaction(tx, err, failure.QuotaExceeded)
+	}
+
+	if err = tx.Commit(); err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	return nil
+}
+
+// GetUserFileKeys returns keys of counted files, which were counted before passed time
+func (r *StoragePostgres) GetUserFileKeys(countedBefore time.Time) ([]string, error) {
+	var objectKeys []string
+
+	query := fmt.Sprintf(`
+			SELECT object_key
+			FROM %s
+			WHERE creation_timestamp<$1
+		`, storageFilesTable)
+
+	if err := r.db.Select(&objectKeys, query, countedBefore); err != nil {
+		logRepoError(err)
+		return []string{}, failure.Unknown
+	}
+
+	return objectKeys, nil
+}
+
+func (r *StoragePostgres) DeleteUserFiles(objectKeys []string) error {
+	query := fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE object_key=ANY($1)
+		`, storageFilesTable)
+
+	if _, err := r.db.Exec(query, pq.Array(objectKeys)); err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	return nil
+}
+
+func (r *StoragePostgres) GetRecipeOwnerIds() (map[int]int, error) {
+	query := fmt.Sprintf(`
+			SELECT recipe_id, owner_id
+			FROM %s
+		`, recipesTable)
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		logRepoError(err)
+		return map[int]int{}, failure.Unknown
+	}
+	defer rows.Close()
+
+	owners := make(map[int]int)
+	for rows.Next() {
+		var recipeId, ownerId int
+		if err := rows.Scan(&recipeId, &ownerId); err != nil {
+			logRepoError(err)
+			continue
+		}
+		owners[recipeId] = ownerId
+	}
+
+	return owners, nil
+}
+
+// SetUserFiles replaces all counted files. Files of deleted users and recipes are skipped
+func (r *StoragePostgres) SetUserFiles(files []entity.UserFile) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return 0, failure.Unknown
+	}
+
+	clearFilesQuery := fmt.Sprintf(`
+			DELETE FROM %s
+		`, storageFilesTable)
+
+	if _, err := tx.Exec(clearFilesQuery); err != nil {
+		return 0, rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	addFileQuery := fmt.Sprintf(`
+			INSERT INTO %[1]v (object_key, user_id, recipe_id, size)
+			SELECT $1, $2, $3, $4
+			WHERE
+				EXISTS (SELECT 1 FROM %[2]v WHERE user_id=$2)
+				AND ($3::int IS NULL OR EXISTS (SELECT 1 FROM %[3]v WHERE recipe_id=$3))
+		`, storageFilesTable, usersTable, recipesTable)
+
+	added := 0
+	for _, file := range files {
+		res, err := tx.Exec(addFileQuery, file.Key, file.UserId, file.RecipeId, file.Size)
+		if err != nil {
+			return 0, rollbackTransaction(tx, err, failure.Unknown)
+		}
+		if changes, err := res.RowsAffected(); err == nil && changes > 0 {
+			added++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logRepoError(err)
+		return 0, failure.Unknown
+	}
+
+	return added, nil
+}
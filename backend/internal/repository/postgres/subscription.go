@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"time"
+)
+
+const subscriptionColumns = `subscription_id, user_id, provider, product_id, original_transaction_id, receipt, status,
+				expires_at, auto_renew, creation_timestamp, update_timestamp`
+
+type SubscriptionPostgres struct {
+	db *sqlx.DB
+}
+
+func NewSubscriptionPostgres(db *sqlx.DB) *SubscriptionPostgres {
+	return &SubscriptionPostgres{
+		db: db,
+	}
+}
+
+func (r *SubscriptionPostgres) GetSubscriptions(userId int) ([]entity.Subscription, error) {
+	query := fmt.Sprintf(`
+			SELECT %s
+			FROM %s
+			WHERE user_id=$1
+			ORDER BY expires_at DESC
+		`, subscriptionColumns, subscriptionsTable)
+
+	return r.getSubscriptions(query, userId)
+}
+
+func (r *SubscriptionPostgres) GetSubscription(provider, originalTransactionId string) (entity.Subscription, error) {
+	query := fmt.Sprintf(`
+			SELECT %s
+			FROM %s
+			WHERE provider=$1 AND original_transaction_id=$2
+		`, subscriptionColumns, subscriptionsTable)
+
+	subscription, err := scanSubscription(r.db.QueryRow(query, provider, originalTransactionId))
+	if err != nil {
+		logRepoError(err)
+		return entity.Subscription{}, failure.SubscriptionNotFound
+	}
+
+	return subscription, nil
+}
+
+// GetExpiredSubscriptions returns subscriptions which still grant premium but expired before passed time
+func (r *SubscriptionPostgres) GetExpiredSubscriptions(expiredBefore time.Time) ([]entity.Subscription, error) {
+	query := fmt.Sprintf(`
+			SELECT %s
+			FROM %s
+			WHERE status IN ('%s', '%s') AND expires_at<$1
+			ORDER BY expires_at
+		`, subscriptionColumns, subscriptionsTable, entity.SubscriptionStatusActive, entity.SubscriptionStatusGracePeriod)
+
+	return r.getSubscriptions(query, expiredBefore)
+}
+
+// SetSubscription saves subscription state and moves premium end date of its owner by the change of premium granted
+// by subscription, so premium days got in other ways are kept
+func (r *SubscriptionPostgres) SetSubscription(subscription entity.Subscription) (entity.Subscription, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return entity.Subscription{}, failure.Unknown
+	}
+
+	now := time.Now().UTC()
+	previousEntitledUntil := now
+
+	getPreviousQuery := fmt.Sprintf(`
+			SELECT %s
+			FROM %s
+			WHERE provider=$1 AND original_transaction_id=$2
+			FOR UPDATE
+		`, subscriptionColumns, subscriptionsTable)
+
+	previous, err := scanSubscription(tx.QueryRow(getPreviousQuery, subscription.Provider, subscription.OriginalTransactionId))
+	switch {
+	case err == nil:
+		if previous.UserId != subscription.UserId {
+			return entity.Subscription{}, rollbackTransaction(tx, nil, failure.SubscriptionOwnedByAnotherUser)
+		}
+		previousEntitledUntil = previous.EntitledUntil(now)
+	case !errors.Is(err, sql.ErrNoRows):
+		return entity.Subscription{}, rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	setSubscriptionQuery := fmt.Sprintf(`
+			INSERT INTO %s (user_id, provider, product_id, original_transaction_id, receipt, status, expires_at, auto_renew)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (provider, original_transaction_id) DO UPDATE
+			SET product_id=EXCLUDED.product_id, receipt=EXCLUDED.receipt, status=EXCLUDED.status,
+				expires_at=EXCLUDED.expires_at, auto_renew=EXCLUDED.auto_renew, update_timestamp=timezone('utc', now())
+			RETURNING %s
+		`, subscriptionsTable, subscriptionColumns)
+
+	saved, err := scanSubscription(tx.QueryRow(setSubscriptionQuery, subscription.UserId, subscription.Provider,
+		subscription.ProductId, subscription.OriginalTransactionId, subscription.Receipt, subscription.Status,
+		subscription.ExpiresAt, subscription.AutoRenew))
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolationCode {
+			return entity.Subscription{}, rollbackTransaction(tx, err, failure.SubscriptionOwnedByAnotherUser)
+		}
+		return entity.Subscription{}, rollbackTransaction(tx, err, failure.Unknown)
+	}
+
+	premiumChange := saved.EntitledUntil(now).Sub(previousEntitledUntil)
+	if premiumChange != 0 {
+		setPremiumQuery := fmt.Sprintf(`
+				UPDATE %[1]v
+				SET premium=GREATEST(
+					GREATEST(COALESCE(premium, $2), $2) + make_interval(secs => $3),
+					(
+						SELECT MAX(expires_at)
+						FROM %[2]v
+						WHERE user_id=$1 AND subscription_id<>$4 AND status IN ('%[3]v', '%[4]v')
+					)
+				)
+				WHERE user_id=$1
+			`, usersTable, subscriptionsTable, entity.SubscriptionStatusActive, entity.SubscriptionStatusGracePeriod)
+
+		if _, err := tx.Exec(setPremiumQuery, saved.UserId, now, premiumChange.Seconds(), saved.Id); err != nil {
+			return entity.Subscription{}, rollbackTransaction(tx, err, failure.Unknown)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logRepoError(err)
+		return entity.Subscription{}, failure.Unknown
+	}
+
+	return saved, nil
+}
+
+func (r *SubscriptionPostgres) getSubscriptions(query string, args ...interface{}) ([]entity.Subscription, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		logRepoError(err)
+		return []entity.Subscription{}, failure.Unknown
+	}
+	defer rows.Close()
+
+	subscriptions := []entity.Subscription{}
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			logRepoError(err)
+			continue
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, nil
+}
+
+func scanSubscription(row rowScanner) (entity.Subscription, error) {
+	var subscription entity.Subscription
+	err := row.Scan(&subscription.Id, &subscription.UserId, &subscription.Provider, &subscription.ProductId,
+		&subscription.OriginalTransactionId, &subscription.Receipt, &subscription.Status, &subscription.ExpiresAt,
+		&subscription.AutoRenew, &subscription.CreationTimestamp, &subscription.UpdateTimestamp)
+	return subscription, err
+}
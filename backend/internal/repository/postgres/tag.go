@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+)
+
+type TagPostgres struct {
+	db *sqlx.DB
+}
+
+func NewTagPostgres(db *sqlx.DB) *TagPostgres {
+	return &TagPostgres{
+		db: db,
+	}
+}
+
+func (r *TagPostgres) GetTags() ([]entity.Tag, error) {
+	query := fmt.Sprintf(`
+			SELECT tag_id, type
+			FROM %s
+			ORDER BY type, tag_id
+		`, tagsTable)
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		logRepoError(err)
+		return []entity.Tag{}, failure.Unknown
+	}
+	defer rows.Close()
+
+	return scanTags(rows), nil
+}
+
+func (r *TagPostgres) GetRecipeTags(recipeId int) []entity.Tag {
+	query := fmt.Sprintf(`
+			SELECT
+				%[1]v.tag_id, %[2]v.type
+			FROM
+				%[1]v
+			INNER JOIN
+				%[2]v ON %[2]v.tag_id=%[1]v.tag_id
+			WHERE
+				%[1]v.recipe_id=$1
+			ORDER BY %[2]v.type, %[1]v.tag_id
+		`, recipesTagsTable, tagsTable)
+
+	rows, err := r.db.Query(query, recipeId)
+	if err != nil {
+		logRepoError(err)
+		return []entity.Tag{}
+	}
+	defer rows.Close()
+
+	return scanTags(rows)
+}
+
+func (r *TagPostgres) GetRecipesTags(recipeIds []int) map[int][]entity.Tag {
+	recipesTags := make(map[int][]entity.Tag)
+	if len(recipeIds) == 0 {
+		return recipesTags
+	}
+
+	query := fmt.Sprintf(`
+			SELECT
+				%[1]v.recipe_id, %[1]v.tag_id, %[2]v.type
+			FROM
+				%[1]v
+			INNER JOIN
+				%[2]v ON %[2]v.tag_id=%[1]v.tag_id
+			WHERE
+				%[1]v.recipe_id=ANY($1)
+			ORDER BY %[2]v.type, %[1]v.tag_id
+		`, recipesTagsTable, tagsTable)
+
+	rows, err := r.db.Query(query, pq.Array(recipeIds))
+	if err != nil {
+		logRepoError(err)
+		return recipesTags
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var recipeId int
+		var tag entity.Tag
+		if err := rows.Scan(&recipeId, &tag.Id, &tag.Type); err != nil {
+			logRepoError(err)
+			continue
+		}
+		recipesTags[recipeId] = append(recipesTags[recipeId], tag)
+	}
+
+	return recipesTags
+}
+
+func (r *TagPostgres) SetRecipeTags(recipeId int, tagIds []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	clearTagsQuery := fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE recipe_id=$1
+		`, recipesTagsTable)
+
+	if _, err := tx.Exec(clearTagsQuery, recipeId); err != nil {
+		logRepoError(err)
+		if err := tx.Rollback(); err != nil {
+			logRepoError(err)
+		}
+		return failure.Unknown
+	}
+
+	if len(tagIds) > 0 {
+		addTagsQuery := fmt.Sprintf(`
+				INSERT INTO %[1]v (recipe_id, tag_id)
+					SELECT $1, tag_id
+					FROM %[2]v
+					WHERE tag_id=ANY($2)
+			`, recipesTagsTable, tagsTable)
+
+		res, err := tx.Exec(addTagsQuery, recipeId, pq.Array(tagIds))
+		if err != nil {
+			logRepoError(err)
+			if err := tx.Rollback(); err != nil {
+				logRepoError(err)
+			}
+			return failure.Unknown
+		}
+
+		if added, err := res.RowsAffected(); err != nil || int(added) != len(tagIds) {
+			if err := tx.Rollback(); err != nil {
+				logRepoError(err)
+			}
+			return failure.TagNotFound
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	return nil
+}
+
+func scanTags(rows *sql.Rows) []entity.Tag {
+	tags := []entity.Tag{}
+	for rows.Next() {
+		var tag entity.Tag
+		if err := rows.Scan(&tag.Id, &tag.Type); err != nil {
+			logRepoError(err)
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"math"
+)
+
+const viewsDeduplicationInterval = "1 day"
+
+type TrendingPostgres struct {
+	db *sqlx.DB
+}
+
+func NewTrendingPostgres(db *sqlx.DB) *TrendingPostgres {
+	return &TrendingPostgres{
+		db: db,
+	}
+}
+
+func (r *TrendingPostgres) AddRecipeView(recipeId, userId int) error {
+
+	addViewQuery := fmt.Sprintf(`
+			INSERT INTO %[1]v (recipe_id, user_id)
+			SELECT $1, $2
+			WHERE NOT EXISTS
+			(
+				SELECT 1
+				FROM %[1]v
+				WHERE recipe_id=$1 AND user_id=$2 AND creation_timestamp > now() - interval '%[2]v'
+			)
+		`, recipeViewsTable, viewsDeduplicationInterval)
+
+	if _, err := r.db.Exec(addViewQuery, recipeId, userId); err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	return nil
+}
+
+func (r *TrendingPostgres) UpdateTrendingScores(params entity.TrendingParams) error {
+	windowSeconds := params.Window.Seconds()
+	decayRate := math.Ln2 / params.HalfLife.Seconds()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	resetScoresQuery := fmt.Sprintf(`
+			UPDATE %s
+			SET trending_score=0
+			WHERE trending_score<>0
+		`, recipesTable)
+
+	if _, err := tx.Exec(resetScoresQuery); err != nil {
+		logRepoError(err)
+		if err := tx.Rollback(); err != nil {
+			logRepoError(err)
+		}
+		return failure.Unknown
+	}
+
+	updateScoresQuery := fmt.Sprintf(`
+			UPDATE %[1]v
+			SET trending_score=scores.score
+			FROM
+			(
+				SELECT recipe_id, sum(weight * exp(-$4 * extract(epoch FROM now() - creation_timestamp))) AS score
+				FROM
+				(
+					SELECT recipe_id, creation_timestamp, $1::double precision AS weight
+					FROM %[2]v
+					WHERE creation_timestamp > now() - $5 * interval '1 second'
+					UNION ALL
+					SELECT %[3]v.recipe_id, %[3]v.creation_timestamp, $2::double precision AS weight
+					FROM %[3]v
+					INNER JOIN %[1]v AS saved ON saved.recipe_id=%[3]v.recipe_id
+					WHERE %[3]v.user_id<>saved.owner_id AND %[3]v.creation_timestamp > now() - $5 * interval '1 second'
+					UNION ALL
+					SELECT recipe_id, creation_timestamp, $3::double precision AS weight
+					FROM %[4]v
+					WHERE creation_timestamp > now() - $5 * interval '1 second'
+				) AS events
+				GROUP BY recipe_id
+			) AS scores
+			WHERE %[1]v.recipe_id=scores.recipe_id AND %[1]v.visibility='%[5]v' AND %[1]v.encrypted=false
+		`, recipesTable, likesTable, usersRecipesTable, recipeViewsTable, entity.VisibilityPublic)
+
+	if _, err := tx.Exec(updateScoresQuery, params.LikeWeight, params.SaveWeight, params.ViewWeight, decayRate, windowSeconds); err != nil {
+		logRepoError(err)
+		if err := tx.Rollback(); err != nil {
+			logRepoError(err)
+		}
+		return failure.Unknown
+	}
+
+	deleteOldViewsQuery := fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE creation_timestamp < now() - $1 * interval '1 second'
+		`, recipeViewsTable)
+
+	if _, err := tx.Exec(deleteOldViewsQuery, windowSeconds); err != nil {
+		logRepoError(err)
+		if err := tx.Rollback(); err != nil {
+			logRepoError(err)
+		}
+		return failure.Unknown
+	}
+
+	if err := tx.Commit(); err != nil {
+		logRepoError(err)
+		return failure.Unknown
+	}
+
+	return nil
+}
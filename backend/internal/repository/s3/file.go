@@ -2,11 +2,16 @@ package s3
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/mephistolie/chefbook-server/internal/entity"
 	"github.com/mephistolie/chefbook-server/internal/entity/failure"
 	"github.com/minio/minio-go/v7"
+	"net/http"
+	"path"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -16,109 +21,341 @@ const (
 	keysDir = "keys"
 	recipesDir = "recipes"
 	imagesDir = "images"
+	thumbnailsDir = "thumbnails"
+	uploadsDir = "uploads"
+
+	// streamed files are uploaded by parts of minimal size, so only one part is buffered
+	streamPartSize = 5 << 20
 
 	xAmzAcl = "x-amz-acl"
 	publicRead = "public-read"
+	private = "private"
+	contentType = "Content-Type"
+	contentLength = "Content-Length"
 )
 
 type AWSFileManager struct {
-	client *minio.Client
+	client    *minio.Client
+	publicUrl string
 }
 
-func NewAWSFileManager(client *minio.Client) *AWSFileManager {
+// NewAWSFileManager creates S3 file manager. Public links are built with publicUrl, e.g. CDN host,
+// or with bucket address if it's empty
+func NewAWSFileManager(client *minio.Client, publicUrl string) *AWSFileManager {
+	if publicUrl == "" {
+		publicUrl = fmt.Sprintf("%s/%s", client.EndpointURL(), chefBookBucket)
+	}
 	return &AWSFileManager{
-		client: client,
+		client:    client,
+		publicUrl: strings.TrimSuffix(publicUrl, "/"),
 	}
 }
 
-func (r *AWSFileManager) UploadAvatar(ctx context.Context, userId int, input entity.MultipartFile) (string, error) {
-	opts := minio.PutObjectOptions{
-		ContentType: input.ContentType,
-		UserMetadata: map[string]string{xAmzAcl: publicRead},
+// GetFiles lists all objects of bucket
+func (r *AWSFileManager) GetFiles(ctx context.Context) ([]entity.StoredFile, error) {
+	var files []entity.StoredFile
+	for object := range r.client.ListObjects(ctx, chefBookBucket, minio.ListObjectsOptions{Recursive: true}) {
+		if object.Err != nil {
+			return []entity.StoredFile{}, failure.Unknown
+		}
+		files = append(files, entity.StoredFile{
+			Key:          object.Key,
+			Size:         object.Size,
+			LastModified: object.LastModified,
+		})
 	}
+	return files, nil
+}
+
+func (r *AWSFileManager) UploadAvatar(ctx context.Context, userId int, input entity.MultipartFile) (string, error) {
+	opts := getPutOptions(input, publicRead)
 
 	filePath := fmt.Sprintf("%s/%d/%s/%s", usersDir, userId, avatarsDir, input.Name)
 	_, err := r.client.PutObject(ctx, chefBookBucket, filePath, input.Content, input.Size, opts)
 	if err != nil {
-		return "", failure.UnableUploadFile
+		return "", getUploadFailure(err)
 	}
 
-	return fmt.Sprintf("%s/%s/%s", r.client.EndpointURL(), chefBookBucket, filePath), nil
+	return filePath, nil
 }
 
 func (r *AWSFileManager) UploadUserKey(ctx context.Context, userId int, input entity.MultipartFile) (string, error) {
-	opts := minio.PutObjectOptions{
-		ContentType: input.ContentType,
-		UserMetadata: map[string]string{xAmzAcl: publicRead},
+	filePath := fmt.Sprintf("%s/%d/%s/%s", usersDir, userId, keysDir, input.Name)
+	return r.uploadPrivateFile(ctx, filePath, input)
+}
+
+func (r *AWSFileManager) GetRecipePictures(ctx context.Context, recipeId int) []string {
+	picturesPath := fmt.Sprintf("%s/%d/%s", recipesDir, recipeId, imagesDir)
+	var objects []string
+	thumbnailsPath := fmt.Sprintf("%s/%s/", picturesPath, thumbnailsDir)
+	for object := range r.client.ListObjects(ctx, chefBookBucket, minio.ListObjectsOptions{Prefix: picturesPath, Recursive: true}) {
+		if strings.HasPrefix(object.Key, thumbnailsPath) {
+			continue
+		}
+		objects = append(objects, object.Key)
+	}
+	return objects
+}
+
+func (r *AWSFileManager) UploadRecipePicture(ctx context.Context, recipeId int, input entity.MultipartFile, isPublic bool) (string, error) {
+	opts := getPutOptions(input, getAcl(isPublic))
+
+	filePath := fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, imagesDir, input.Name)
+	if _, err := r.client.StatObject(ctx, chefBookBucket, filePath, minio.StatObjectOptions{}); err == nil {
+		return filePath, nil
 	}
 
-	filePath := fmt.Sprintf("%s/%d/%s/%s", usersDir, userId, keysDir, input.Name)
 	_, err := r.client.PutObject(ctx, chefBookBucket, filePath, input.Content, input.Size, opts)
 	if err != nil {
-		return "", failure.UnableUploadFile
+		return "", getUploadFailure(err)
 	}
 
-	return fmt.Sprintf("%s/%s/%s", r.client.EndpointURL(), chefBookBucket, filePath), nil
+	return filePath, nil
 }
 
-func (r *AWSFileManager) GetRecipePictures(ctx context.Context, recipeId int) []string {
+// UploadThumbnail stores thumbnail of picture to thumbnails directory next to picture
+func (r *AWSFileManager) UploadThumbnail(ctx context.Context, objectKey string, size int, input entity.MultipartFile, isPublic bool) error {
+	opts := getPutOptions(input, getAcl(isPublic))
+
+	filePath := r.GetThumbnailKey(objectKey, size)
+	_, err := r.client.PutObject(ctx, chefBookBucket, filePath, input.Content, input.Size, opts)
+	if err != nil {
+		return getUploadFailure(err)
+	}
+
+	return nil
+}
+
+func (r *AWSFileManager) GetThumbnailKey(objectKey string, size int) string {
+	return fmt.Sprintf("%s/%s/%d/%s", path.Dir(objectKey), thumbnailsDir, size, path.Base(objectKey))
+}
+
+// GetRecipePictureLink returns presigned link to recipe picture. Files which aren't recipe pictures get public links
+func (r *AWSFileManager) GetRecipePictureLink(ctx context.Context, recipeId int, objectKey string, ttl time.Duration) (string, error) {
+	picturesPath := fmt.Sprintf("%s/%d/%s/", recipesDir, recipeId, imagesDir)
+	if !strings.HasPrefix(objectKey, picturesPath) {
+		return r.GetFileLink(objectKey), nil
+	}
+	return r.GetPrivateFileLink(ctx, objectKey, ttl)
+}
+
+// SetRecipePicturesAccess updates ACL of all recipe pictures after recipe visibility change
+func (r *AWSFileManager) SetRecipePicturesAccess(ctx context.Context, recipeId int, isPublic bool) error {
 	picturesPath := fmt.Sprintf("%s/%d/%s", recipesDir, recipeId, imagesDir)
-	var objects []string
 	for object := range r.client.ListObjects(ctx, chefBookBucket, minio.ListObjectsOptions{Prefix: picturesPath, Recursive: true}) {
-		objects = append(objects, fmt.Sprintf("%s/%s/%s", r.client.EndpointURL(), chefBookBucket, object.Key))
+		if object.Err != nil {
+			return failure.UnableUploadFile
+		}
+		if err := r.setObjectAcl(ctx, object.Key, getAcl(isPublic)); err != nil {
+			return err
+		}
 	}
-	return objects
+	return nil
 }
 
-func (r *AWSFileManager) UploadRecipePicture(ctx context.Context, recipeId int, input entity.MultipartFile) (string, error) {
-	opts := minio.PutObjectOptions{
-		ContentType: input.ContentType,
-		UserMetadata: map[string]string{xAmzAcl: publicRead},
+// GetRecipePictureUploadLink returns presigned PUT link to uploads directory of recipe.
+// Content type and size are signed, so storage rejects uploads with other values
+func (r *AWSFileManager) GetRecipePictureUploadLink(ctx context.Context, recipeId int, uploadId string, input entity.UploadInput, ttl time.Duration) (entity.UploadLink, error) {
+	headers := http.Header{}
+	headers.Set(contentType, input.ContentType)
+	headers.Set(contentLength, strconv.FormatInt(input.Size, 10))
+
+	filePath := fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, uploadsDir, uploadId)
+	link, err := r.client.PresignHeader(ctx, http.MethodPut, chefBookBucket, filePath, ttl, nil, headers)
+	if err != nil {
+		return entity.UploadLink{}, failure.Unknown
 	}
 
-	filePath := fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, imagesDir, input.Name)
-	_, err := r.client.PutObject(ctx, chefBookBucket, filePath, input.Content, input.Size, opts)
+	return entity.UploadLink{
+		UploadId:  uploadId,
+		Url:       link.String(),
+		Headers:   map[string]string{contentType: input.ContentType, contentLength: strconv.FormatInt(input.Size, 10)},
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// GetRecipePictureUpload opens file uploaded by presigned link. Content must be closed by caller
+func (r *AWSFileManager) GetRecipePictureUpload(ctx context.Context, recipeId int, uploadId string) (entity.MultipartFile, error) {
+	filePath := fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, uploadsDir, uploadId)
+	info, err := r.client.StatObject(ctx, chefBookBucket, filePath, minio.StatObjectOptions{})
+	if err != nil {
+		return entity.MultipartFile{}, failure.UploadNotFound
+	}
+
+	object, err := r.client.GetObject(ctx, chefBookBucket, filePath, minio.GetObjectOptions{})
+	if err != nil {
+		return entity.MultipartFile{}, failure.UploadNotFound
+	}
+
+	return entity.MultipartFile{
+		Name:        uploadId,
+		Content:     object,
+		Size:        info.Size,
+		ContentType: info.ContentType,
+	}, nil
+}
+
+// CommitRecipePictureUpload moves file uploaded by presigned link to recipe pictures without downloading it
+func (r *AWSFileManager) CommitRecipePictureUpload(ctx context.Context, recipeId int, uploadId string, isPublic bool) (string, error) {
+	uploadPath := fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, uploadsDir, uploadId)
+	filePath := fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, imagesDir, uploadId)
+
+	info, err := r.client.StatObject(ctx, chefBookBucket, uploadPath, minio.StatObjectOptions{})
 	if err != nil {
+		return "", failure.UploadNotFound
+	}
+
+	src := minio.CopySrcOptions{
+		Bucket: chefBookBucket,
+		Object: uploadPath,
+	}
+	dst := minio.CopyDestOptions{
+		Bucket:          chefBookBucket,
+		Object:          filePath,
+		ReplaceMetadata: true,
+		UserMetadata:    map[string]string{xAmzAcl: getAcl(isPublic), contentType: info.ContentType},
+	}
+	if _, err := r.client.CopyObject(ctx, dst, src); err != nil {
 		return "", failure.UnableUploadFile
 	}
 
-	return fmt.Sprintf("%s/%s/%s", r.client.EndpointURL(), chefBookBucket, filePath), nil
+	return filePath, nil
 }
 
-func (r *AWSFileManager) DeleteRecipePicture(ctx context.Context, recipeId int, pictureName string) error {
-	return r.DeleteFile(ctx, r.getRecipePictureLink(recipeId, pictureName))
+func (r *AWSFileManager) DeleteRecipePictureUpload(ctx context.Context, recipeId int, uploadId string) error {
+	return r.DeletePrivateFile(ctx, fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, uploadsDir, uploadId))
 }
 
-func (r *AWSFileManager) UploadRecipeKey(ctx context.Context, recipeId int, input entity.MultipartFile) (string, error) {
-	opts := minio.PutObjectOptions{
-		ContentType: input.ContentType,
-		UserMetadata: map[string]string{xAmzAcl: publicRead},
+func (r *AWSFileManager) DeleteRecipePicture(ctx context.Context, recipeId int, pictureName string) error {
+	thumbnailsPath := fmt.Sprintf("%s/%d/%s/%s/", recipesDir, recipeId, imagesDir, thumbnailsDir)
+	for object := range r.client.ListObjects(ctx, chefBookBucket, minio.ListObjectsOptions{Prefix: thumbnailsPath, Recursive: true}) {
+		if object.Err == nil && path.Base(object.Key) == pictureName {
+			_ = r.DeletePrivateFile(ctx, object.Key)
+		}
 	}
+	return r.DeleteFile(ctx, fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, imagesDir, pictureName))
+}
 
+func (r *AWSFileManager) UploadRecipeKey(ctx context.Context, recipeId int, input entity.MultipartFile) (string, error) {
 	filePath := fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, keysDir, input.Name)
-	_, err := r.client.PutObject(ctx, chefBookBucket, filePath, input.Content, input.Size, opts)
+	return r.uploadPrivateFile(ctx, filePath, input)
+}
+
+// GetPrivateFileLink returns presigned link to private object which expires after ttl
+func (r *AWSFileManager) GetPrivateFileLink(ctx context.Context, objectKey string, ttl time.Duration) (string, error) {
+	link, err := r.client.PresignedGetObject(ctx, chefBookBucket, objectKey, ttl, nil)
 	if err != nil {
-		return "", failure.UnableUploadFile
+		return "", failure.Unknown
+	}
+	return link.String(), nil
+}
+
+func (r *AWSFileManager) DeletePrivateFile(ctx context.Context, objectKey string) error {
+	opts := minio.RemoveObjectOptions{ ForceDelete: true }
+	if err := r.client.RemoveObject(ctx, chefBookBucket, objectKey, opts); err != nil {
+		return failure.UnableDeleteFile
 	}
+	return nil
+}
 
-	return fmt.Sprintf("%s/%s/%s", r.client.EndpointURL(), chefBookBucket, filePath), nil
+// MakeFilePrivate revokes public access to object
+func (r *AWSFileManager) MakeFilePrivate(ctx context.Context, objectKey string) error {
+	return r.setObjectAcl(ctx, objectKey, private)
 }
 
-func (r *AWSFileManager) DeleteFile(ctx context.Context, url string) error {
+func (r *AWSFileManager) DeleteFile(ctx context.Context, objectKey string) error {
 	opts := minio.RemoveObjectOptions{ ForceDelete: true }
-	filePath := strings.ReplaceAll(url, fmt.Sprintf("%s/%s/", r.client.EndpointURL().String(), chefBookBucket), "")
-	if err := r.client.RemoveObject(ctx, chefBookBucket, filePath, opts); err != nil {
+	if err := r.client.RemoveObject(ctx, chefBookBucket, objectKey, opts); err != nil {
 		return failure.UnableDeleteFile
 	}
 	return nil
 }
 
-func (r *AWSFileManager) getRecipePictureLink(recipeId int, pictureName string) string {
-	filePath := fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, imagesDir, pictureName)
-	return fmt.Sprintf("%s/%s/%s", r.client.EndpointURL(), chefBookBucket, filePath)
+// GetFileLink returns public link to object. Absolute links stored before object keys are returned as is
+func (r *AWSFileManager) GetFileLink(objectKey string) string {
+	if isLink(objectKey) {
+		return objectKey
+	}
+	return fmt.Sprintf("%s/%s", r.publicUrl, objectKey)
+}
+
+// GetObjectKey returns object key of link to this storage or public url. Other values are returned as is
+func (r *AWSFileManager) GetObjectKey(link string) string {
+	for _, prefix := range []string{
+		fmt.Sprintf("%s/%s/", r.client.EndpointURL(), chefBookBucket),
+		r.publicUrl + "/",
+	} {
+		if strings.HasPrefix(link, prefix) {
+			objectKey := strings.TrimPrefix(link, prefix)
+			if query := strings.Index(objectKey, "?"); query >= 0 {
+				objectKey = objectKey[:query]
+			}
+			return objectKey
+		}
+	}
+	return link
+}
+
+func (r *AWSFileManager) uploadPrivateFile(ctx context.Context, filePath string, input entity.MultipartFile) (string, error) {
+	opts := getPutOptions(input, private)
+
+	_, err := r.client.PutObject(ctx, chefBookBucket, filePath, input.Content, input.Size, opts)
+	if err != nil {
+		return "", getUploadFailure(err)
+	}
+
+	return filePath, nil
+}
+
+// setObjectAcl copies object to itself because S3 ACL can't be changed with PUT Object ACL by minio client
+func (r *AWSFileManager) setObjectAcl(ctx context.Context, objectKey string, acl string) error {
+	info, err := r.client.StatObject(ctx, chefBookBucket, objectKey, minio.StatObjectOptions{})
+	if err != nil {
+		return failure.UnableUploadFile
+	}
+
+	src := minio.CopySrcOptions{
+		Bucket: chefBookBucket,
+		Object: objectKey,
+	}
+	dst := minio.CopyDestOptions{
+		Bucket:          chefBookBucket,
+		Object:          objectKey,
+		ReplaceMetadata: true,
+		UserMetadata:    map[string]string{xAmzAcl: acl, contentType: info.ContentType},
+	}
+	if _, err := r.client.CopyObject(ctx, dst, src); err != nil {
+		return failure.UnableUploadFile
+	}
+
+	return nil
+}
+
+func isLink(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
+
+func getAcl(isPublic bool) string {
+	if isPublic {
+		return publicRead
+	}
+	return private
+}
+
+func getPutOptions(input entity.MultipartFile, acl string) minio.PutObjectOptions {
+	opts := minio.PutObjectOptions{
+		ContentType: input.ContentType,
+		UserMetadata: map[string]string{xAmzAcl: acl},
+	}
+	if input.Size < 0 {
+		opts.PartSize = streamPartSize
+	}
+	return opts
 }
 
-func (r *AWSFileManager) getRecipeKeysLink(recipeId int, pictureName string) string {
-	filePath := fmt.Sprintf("%s/%d/%s/%s", recipesDir, recipeId, keysDir, pictureName)
-	return fmt.Sprintf("%s/%s/%s", r.client.EndpointURL(), chefBookBucket, filePath)
-}
\ No newline at end of file
+func getUploadFailure(err error) error {
+	if errors.Is(err, failure.InvalidFileSize) {
+		return failure.InvalidFileSize
+	}
+	return failure.UnableUploadFile
+}
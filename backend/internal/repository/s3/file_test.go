@@ -0,0 +1,55 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"github.com/mephistolie/chefbook-server/internal/repository/storagetest"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"os"
+	"testing"
+)
+
+const (
+	testHostEnv      = "STORAGE_TEST_S3_HOST"
+	testAccessKeyEnv = "STORAGE_TEST_S3_ACCESS_KEY"
+	testSecretKeyEnv = "STORAGE_TEST_S3_SECRET_KEY"
+
+	publicReadPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::%s/*"]}]}`
+)
+
+// TestAWSFileManager runs against MinIO from docker-compose.test.yml, e.g.
+// STORAGE_TEST_S3_HOST=localhost:9000 STORAGE_TEST_S3_ACCESS_KEY=minioadmin STORAGE_TEST_S3_SECRET_KEY=minioadmin.
+// MinIO ignores object ACLs, so bucket is made readable by public links and access isn't checked
+func TestAWSFileManager(t *testing.T) {
+	host := os.Getenv(testHostEnv)
+	if host == "" {
+		t.Skipf("%s isn't set", testHostEnv)
+	}
+
+	client, err := minio.New(host, &minio.Options{
+		Creds: credentials.NewStaticV4(os.Getenv(testAccessKeyEnv), os.Getenv(testSecretKeyEnv), ""),
+	})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	ctx := context.Background()
+	exists, err := client.BucketExists(ctx, chefBookBucket)
+	if err != nil {
+		t.Fatalf("check bucket: %v", err)
+	}
+	if !exists {
+		if err = client.MakeBucket(ctx, chefBookBucket, minio.MakeBucketOptions{}); err != nil {
+			t.Fatalf("create bucket: %v", err)
+		}
+	}
+	if err = client.SetBucketPolicy(ctx, chefBookBucket, fmt.Sprintf(publicReadPolicy, chefBookBucket)); err != nil {
+		t.Fatalf("set bucket policy: %v", err)
+	}
+
+	storagetest.Run(t, storagetest.Storage{
+		Files:          NewAWSFileManager(client, ""),
+		EnforcesAccess: false,
+	})
+}
@@ -0,0 +1,353 @@
+// Package storagetest checks that storage drivers behave the same way behind repository.File
+package storagetest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const (
+	linkTTL       = time.Minute
+	thumbnailSize = 128
+	pictureType   = "image/png"
+)
+
+// pictureContent starts with PNG signature, so drivers which sniff content type detect it as picture
+var pictureContent = []byte("\x89PNG\r\n\x1a\nstoragetest picture")
+
+// Storage is storage driver under test
+type Storage struct {
+	Files repository.File
+	// EnforcesAccess is set if storage rejects public links to private files.
+	// S3-compatible test servers, e.g. MinIO, ignore object ACLs, so access can't be checked with them
+	EnforcesAccess bool
+}
+
+// Run checks storage driver. Files are stored under random user and recipe ids and deleted after checks
+func Run(t *testing.T, storage Storage) {
+	id := int(time.Now().UnixNano()%1e9) + 1
+
+	t.Run("Avatar", func(t *testing.T) { testAvatar(t, storage, id) })
+	t.Run("Keys", func(t *testing.T) { testKeys(t, storage, id) })
+	t.Run("RecipePictures", func(t *testing.T) { testRecipePictures(t, storage, id) })
+	t.Run("PresignedUpload", func(t *testing.T) { testPresignedUpload(t, storage, id+1) })
+}
+
+func testAvatar(t *testing.T, storage Storage, userId int) {
+	ctx := context.Background()
+	files := storage.Files
+
+	objectKey, err := files.UploadAvatar(ctx, userId, newFile("avatar", pictureContent))
+	if err != nil {
+		t.Fatalf("upload avatar: %v", err)
+	}
+	if expected := fmt.Sprintf("users/%d/avatars/avatar", userId); objectKey != expected {
+		t.Fatalf("avatar key is %s, expected %s", objectKey, expected)
+	}
+	assertStored(t, files, objectKey, int64(len(pictureContent)))
+
+	link := files.GetFileLink(objectKey)
+	if files.GetObjectKey(link) != objectKey {
+		t.Errorf("object key of public link %s isn't %s", link, objectKey)
+	}
+	assertContent(t, link, pictureContent)
+
+	if err = files.MakeFilePrivate(ctx, objectKey); err != nil {
+		t.Fatalf("make avatar private: %v", err)
+	}
+	assertPrivateContent(t, files, objectKey, pictureContent)
+	if storage.EnforcesAccess {
+		assertUnavailable(t, link)
+	}
+
+	if err = files.DeleteFile(ctx, objectKey); err != nil {
+		t.Fatalf("delete avatar: %v", err)
+	}
+	assertNotStored(t, files, objectKey)
+}
+
+func testKeys(t *testing.T, storage Storage, id int) {
+	ctx := context.Background()
+	files := storage.Files
+	content := []byte("storagetest key")
+
+	userKey, err := files.UploadUserKey(ctx, id, newFile("key", content))
+	if err != nil {
+		t.Fatalf("upload user key: %v", err)
+	}
+	recipeKey, err := files.UploadRecipeKey(ctx, id, newFile("key", content))
+	if err != nil {
+		t.Fatalf("upload recipe key: %v", err)
+	}
+
+	for _, objectKey := range []string{userKey, recipeKey} {
+		assertStored(t, files, objectKey, int64(len(content)))
+		link := assertPrivateContent(t, files, objectKey, content)
+		if files.GetObjectKey(link) != objectKey {
+			t.Errorf("object key of private link %s isn't %s", link, objectKey)
+		}
+		if storage.EnforcesAccess {
+			assertUnavailable(t, files.GetFileLink(objectKey))
+		}
+
+		if err = files.DeletePrivateFile(ctx, objectKey); err != nil {
+			t.Fatalf("delete key %s: %v", objectKey, err)
+		}
+		assertNotStored(t, files, objectKey)
+	}
+}
+
+func testRecipePictures(t *testing.T, storage Storage, recipeId int) {
+	ctx := context.Background()
+	files := storage.Files
+
+	objectKey, err := files.UploadRecipePicture(ctx, recipeId, newFile("picture", pictureContent), false)
+	if err != nil {
+		t.Fatalf("upload recipe picture: %v", err)
+	}
+	if expected := fmt.Sprintf("recipes/%d/images/picture", recipeId); objectKey != expected {
+		t.Fatalf("recipe picture key is %s, expected %s", objectKey, expected)
+	}
+	t.Cleanup(func() { _ = files.DeleteRecipePicture(ctx, recipeId, "picture") })
+
+	duplicateKey, err := files.UploadRecipePicture(ctx, recipeId, newFile("picture", []byte("other content")), false)
+	if err != nil {
+		t.Fatalf("upload duplicate recipe picture: %v", err)
+	}
+	if duplicateKey != objectKey {
+		t.Errorf("duplicate recipe picture key is %s, expected %s", duplicateKey, objectKey)
+	}
+
+	thumbnailContent := []byte("\x89PNG\r\n\x1a\nstoragetest thumbnail")
+	if err = files.UploadThumbnail(ctx, objectKey, thumbnailSize, newFile("picture", thumbnailContent), false); err != nil {
+		t.Fatalf("upload thumbnail: %v", err)
+	}
+	thumbnailKey := files.GetThumbnailKey(objectKey, thumbnailSize)
+	if expected := fmt.Sprintf("recipes/%d/images/thumbnails/%d/picture", recipeId, thumbnailSize); thumbnailKey != expected {
+		t.Fatalf("thumbnail key is %s, expected %s", thumbnailKey, expected)
+	}
+	assertStored(t, files, thumbnailKey, int64(len(thumbnailContent)))
+
+	pictures := files.GetRecipePictures(ctx, recipeId)
+	if len(pictures) != 1 || pictures[0] != objectKey {
+		t.Errorf("recipe pictures are %v, expected only %s", pictures, objectKey)
+	}
+
+	link, err := files.GetRecipePictureLink(ctx, recipeId, objectKey, linkTTL)
+	if err != nil {
+		t.Fatalf("get recipe picture link: %v", err)
+	}
+	if files.GetObjectKey(link) != objectKey {
+		t.Errorf("object key of recipe picture link %s isn't %s", link, objectKey)
+	}
+	assertContent(t, link, pictureContent)
+	assertPrivateContent(t, files, thumbnailKey, thumbnailContent)
+	if storage.EnforcesAccess {
+		assertUnavailable(t, files.GetFileLink(objectKey))
+		assertUnavailable(t, files.GetFileLink(thumbnailKey))
+	}
+
+	foreignKey := fmt.Sprintf("recipes/%d/images/picture", recipeId+1)
+	if link, err = files.GetRecipePictureLink(ctx, recipeId, foreignKey, linkTTL); err != nil || link != files.GetFileLink(foreignKey) {
+		t.Errorf("link to picture of other recipe is %s, expected public link %s", link, files.GetFileLink(foreignKey))
+	}
+
+	if err = files.SetRecipePicturesAccess(ctx, recipeId, true); err != nil {
+		t.Fatalf("make recipe pictures public: %v", err)
+	}
+	assertContent(t, files.GetFileLink(objectKey), pictureContent)
+	assertContent(t, files.GetFileLink(thumbnailKey), thumbnailContent)
+
+	if err = files.SetRecipePicturesAccess(ctx, recipeId, false); err != nil {
+		t.Fatalf("make recipe pictures private: %v", err)
+	}
+	assertPrivateContent(t, files, objectKey, pictureContent)
+	if storage.EnforcesAccess {
+		assertUnavailable(t, files.GetFileLink(objectKey))
+		assertUnavailable(t, files.GetFileLink(thumbnailKey))
+	}
+
+	if err = files.DeleteRecipePicture(ctx, recipeId, "picture"); err != nil {
+		t.Fatalf("delete recipe picture: %v", err)
+	}
+	assertNotStored(t, files, objectKey)
+	assertNotStored(t, files, thumbnailKey)
+}
+
+func testPresignedUpload(t *testing.T, storage Storage, recipeId int) {
+	ctx := context.Background()
+	files := storage.Files
+	uploadId := "upload"
+
+	input := entity.UploadInput{ContentType: pictureType, Size: int64(len(pictureContent))}
+	link, err := files.GetRecipePictureUploadLink(ctx, recipeId, uploadId, input, linkTTL)
+	if err != nil {
+		t.Fatalf("get upload link: %v", err)
+	}
+	if link.UploadId != uploadId || !link.ExpiresAt.After(time.Now()) {
+		t.Errorf("upload link %+v doesn't match upload %s", link, uploadId)
+	}
+	t.Cleanup(func() {
+		_ = files.DeleteRecipePictureUpload(ctx, recipeId, uploadId)
+		_ = files.DeleteRecipePicture(ctx, recipeId, uploadId)
+	})
+
+	if status := put(t, link, pictureContent[:len(pictureContent)-1]); status < 400 {
+		t.Errorf("upload of unsigned size returned status %d", status)
+	}
+	if _, err = files.GetRecipePictureUpload(ctx, recipeId, uploadId); !errors.Is(err, failure.UploadNotFound) {
+		t.Errorf("upload of unsigned size is stored")
+	}
+
+	if status := put(t, link, pictureContent); status >= 300 {
+		t.Fatalf("upload returned status %d", status)
+	}
+
+	upload, err := files.GetRecipePictureUpload(ctx, recipeId, uploadId)
+	if err != nil {
+		t.Fatalf("get upload: %v", err)
+	}
+	content, err := io.ReadAll(upload.Content)
+	if closer, ok := upload.Content.(io.Closer); ok {
+		_ = closer.Close()
+	}
+	if err != nil {
+		t.Fatalf("read upload: %v", err)
+	}
+	if !bytes.Equal(content, pictureContent) || upload.Size != int64(len(pictureContent)) || upload.ContentType != pictureType {
+		t.Errorf("upload is %q of %d bytes and type %s, expected %q of %d bytes and type %s",
+			content, upload.Size, upload.ContentType, pictureContent, len(pictureContent), pictureType)
+	}
+
+	objectKey, err := files.CommitRecipePictureUpload(ctx, recipeId, uploadId, false)
+	if err != nil {
+		t.Fatalf("commit upload: %v", err)
+	}
+	if expected := fmt.Sprintf("recipes/%d/images/%s", recipeId, uploadId); objectKey != expected {
+		t.Fatalf("committed upload key is %s, expected %s", objectKey, expected)
+	}
+	assertPrivateContent(t, files, objectKey, pictureContent)
+
+	if err = files.DeleteRecipePictureUpload(ctx, recipeId, uploadId); err != nil {
+		t.Fatalf("delete upload: %v", err)
+	}
+	if _, err = files.GetRecipePictureUpload(ctx, recipeId, uploadId); !errors.Is(err, failure.UploadNotFound) {
+		t.Errorf("deleted upload is available, error: %v", err)
+	}
+	if _, err = files.CommitRecipePictureUpload(ctx, recipeId, uploadId, false); !errors.Is(err, failure.UploadNotFound) {
+		t.Errorf("deleted upload is committed, error: %v", err)
+	}
+	assertPrivateContent(t, files, objectKey, pictureContent)
+}
+
+func newFile(name string, content []byte) entity.MultipartFile {
+	return entity.MultipartFile{
+		Name:        name,
+		Content:     bytes.NewReader(content),
+		Size:        int64(len(content)),
+		ContentType: pictureType,
+	}
+}
+
+func assertStored(t *testing.T, files repository.File, objectKey string, size int64) {
+	t.Helper()
+	stored, err := files.GetFiles(context.Background())
+	if err != nil {
+		t.Fatalf("get files: %v", err)
+	}
+	for _, file := range stored {
+		if file.Key == objectKey {
+			if file.Size != size {
+				t.Errorf("size of %s is %d, expected %d", objectKey, file.Size, size)
+			}
+			return
+		}
+	}
+	t.Errorf("%s isn't stored", objectKey)
+}
+
+func assertNotStored(t *testing.T, files repository.File, objectKey string) {
+	t.Helper()
+	stored, err := files.GetFiles(context.Background())
+	if err != nil {
+		t.Fatalf("get files: %v", err)
+	}
+	for _, file := range stored {
+		if file.Key == objectKey {
+			t.Errorf("%s is still stored", objectKey)
+		}
+	}
+}
+
+func assertPrivateContent(t *testing.T, files repository.File, objectKey string, expected []byte) string {
+	t.Helper()
+	link, err := files.GetPrivateFileLink(context.Background(), objectKey, linkTTL)
+	if err != nil {
+		t.Fatalf("get private link of %s: %v", objectKey, err)
+	}
+	assertContent(t, link, expected)
+	return link
+}
+
+func assertContent(t *testing.T, link string, expected []byte) {
+	t.Helper()
+	status, content := get(t, link)
+	if status != http.StatusOK || !bytes.Equal(content, expected) {
+		t.Errorf("%s returned status %d and %q, expected %q", link, status, content, expected)
+	}
+}
+
+func assertUnavailable(t *testing.T, link string) {
+	t.Helper()
+	if status, _ := get(t, link); status < 400 {
+		t.Errorf("%s returned status %d, expected it to be unavailable", link, status)
+	}
+}
+
+func get(t *testing.T, link string) (int, []byte) {
+	t.Helper()
+	resp, err := http.Get(link)
+	if err != nil {
+		t.Fatalf("get %s: %v", link, err)
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read %s: %v", link, err)
+	}
+	return resp.StatusCode, content
+}
+
+// put uploads content by presigned link with its headers. Content length is set by content, not by headers
+func put(t *testing.T, link entity.UploadLink, content []byte) int {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPut, link.Url, bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("create upload request: %v", err)
+	}
+	for key, value := range link.Headers {
+		req.Header.Set(key, value)
+	}
+	req.ContentLength = int64(len(content))
+	req.Header.Set("Content-Length", strconv.Itoa(len(content)))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("upload to %s: %v", link.Url, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode
+}
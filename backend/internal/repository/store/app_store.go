@@ -0,0 +1,227 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"github.com/mephistolie/chefbook-server/pkg/logger"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	appStoreProductionEndpoint = "https://buy.itunes.apple.com/verifyReceipt"
+	appStoreSandboxEndpoint    = "https://sandbox.itunes.apple.com/verifyReceipt"
+
+	appStoreStatusValid          = 0
+	appStoreStatusSandboxReceipt = 21007
+
+	appStoreAutoRenewEnabled = "1"
+
+	storeRequestTimeout = 15 * time.Second
+)
+
+type AppStoreProvider struct {
+	client       *http.Client
+	sharedSecret string
+	bundleId     string
+}
+
+func NewAppStoreProvider(sharedSecret, bundleId string) *AppStoreProvider {
+	return &AppStoreProvider{
+		client:       &http.Client{Timeout: storeRequestTimeout},
+		sharedSecret: sharedSecret,
+		bundleId:     bundleId,
+	}
+}
+
+type appStoreReceiptRequest struct {
+	ReceiptData            string `json:"receipt-data"`
+	Password               string `json:"password"`
+	ExcludeOldTransactions bool   `json:"exclude-old-transactions"`
+}
+
+type appStoreReceiptResponse struct {
+	Status  int `json:"status"`
+	Receipt struct {
+		BundleId string `json:"bundle_id"`
+	} `json:"receipt"`
+	LatestReceipt      string                `json:"latest_receipt"`
+	LatestReceiptInfo  []appStoreTransaction `json:"latest_receipt_info"`
+	PendingRenewalInfo []appStoreRenewalInfo `json:"pending_renewal_info"`
+}
+
+type appStoreTransaction struct {
+	ProductId             string `json:"product_id"`
+	OriginalTransactionId string `json:"original_transaction_id"`
+	ExpiresDateMs         string `json:"expires_date_ms"`
+	CancellationDateMs    string `json:"cancellation_date_ms"`
+}
+
+type appStoreRenewalInfo struct {
+	OriginalTransactionId    string `json:"original_transaction_id"`
+	AutoRenewStatus          string `json:"auto_renew_status"`
+	GracePeriodExpiresDateMs string `json:"grace_period_expires_date_ms"`
+}
+
+// appStoreNotification contains fields of both signed V2 and legacy V1 App Store server notifications
+type appStoreNotification struct {
+	SignedPayload  string `json:"signedPayload"`
+	UnifiedReceipt struct {
+		LatestReceipt string `json:"latest_receipt"`
+	} `json:"unified_receipt"`
+	AutoRenewProductId string `json:"auto_renew_product_id"`
+}
+
+type appStoreNotificationPayload struct {
+	Data struct {
+		BundleId              string `json:"bundleId"`
+		SignedTransactionInfo string `json:"signedTransactionInfo"`
+	} `json:"data"`
+}
+
+type appStoreTransactionPayload struct {
+	OriginalTransactionId string `json:"originalTransactionId"`
+	ProductId             string `json:"productId"`
+}
+
+// VerifyReceipt validates base64 encoded receipt and returns state of the latest subscription transaction
+func (p *AppStoreProvider) VerifyReceipt(ctx context.Context, productId, receipt string) (entity.SubscriptionState, error) {
+	response, err := p.verifyReceipt(ctx, appStoreProductionEndpoint, receipt)
+	if err == nil && response.Status == appStoreStatusSandboxReceipt {
+		response, err = p.verifyReceipt(ctx, appStoreSandboxEndpoint, receipt)
+	}
+	if err != nil {
+		logger.Errorf("unable to verify App Store receipt: %s", err.Error())
+		return entity.SubscriptionState{}, failure.UnableVerifyReceipt
+	}
+	if response.Status != appStoreStatusValid || (p.bundleId != "" && response.Receipt.BundleId != p.bundleId) {
+		return entity.SubscriptionState{}, failure.InvalidReceipt
+	}
+
+	var latest *appStoreTransaction
+	var latestExpiresAt time.Time
+	for i, transaction := range response.LatestReceiptInfo {
+		expiresAt, ok := parseMillis(transaction.ExpiresDateMs)
+		if transaction.ProductId != productId || !ok {
+			continue
+		}
+		if latest == nil || expiresAt.After(latestExpiresAt) {
+			latest, latestExpiresAt = &response.LatestReceiptInfo[i], expiresAt
+		}
+	}
+	if latest == nil {
+		return entity.SubscriptionState{}, failure.InvalidReceipt
+	}
+
+	state := entity.SubscriptionState{
+		ProductId:             latest.ProductId,
+		OriginalTransactionId: latest.OriginalTransactionId,
+		Receipt:               receipt,
+		Status:                entity.SubscriptionStatusExpired,
+		ExpiresAt:             latestExpiresAt,
+	}
+	if response.LatestReceipt != "" {
+		state.Receipt = response.LatestReceipt
+	}
+
+	var gracePeriodExpiresAt time.Time
+	for _, renewal := range response.PendingRenewalInfo {
+		if renewal.OriginalTransactionId == latest.OriginalTransactionId {
+			state.AutoRenew = renewal.AutoRenewStatus == appStoreAutoRenewEnabled
+			gracePeriodExpiresAt, _ = parseMillis(renewal.GracePeriodExpiresDateMs)
+		}
+	}
+
+	now := time.Now()
+	switch {
+	case latest.CancellationDateMs != "":
+		state.Status = entity.SubscriptionStatusRevoked
+		state.AutoRenew = false
+	case latestExpiresAt.After(now):
+		state.Status = entity.SubscriptionStatusActive
+	case gracePeriodExpiresAt.After(now):
+		state.Status = entity.SubscriptionStatusGracePeriod
+		state.ExpiresAt = gracePeriodExpiresAt
+	}
+
+	return state, nil
+}
+
+// ParseNotification extracts subscription of App Store server notification. Signature of V2 notification isn't checked
+// because subscription is verified by receipt anyway
+func (p *AppStoreProvider) ParseNotification(body []byte) (entity.SubscriptionNotification, error) {
+	var notification appStoreNotification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return entity.SubscriptionNotification{}, failure.InvalidBody
+	}
+
+	if notification.SignedPayload == "" {
+		if notification.UnifiedReceipt.LatestReceipt == "" {
+			return entity.SubscriptionNotification{}, failure.InvalidBody
+		}
+		return entity.SubscriptionNotification{
+			ProductId: &notification.AutoRenewProductId,
+			Receipt:   &notification.UnifiedReceipt.LatestReceipt,
+		}, nil
+	}
+
+	var payload appStoreNotificationPayload
+	if err := decodeJWSPayload(notification.SignedPayload, &payload); err != nil {
+		return entity.SubscriptionNotification{}, failure.InvalidBody
+	}
+	if p.bundleId != "" && payload.Data.BundleId != p.bundleId {
+		return entity.SubscriptionNotification{}, failure.InvalidBody
+	}
+	if payload.Data.SignedTransactionInfo == "" {
+		return entity.SubscriptionNotification{}, nil
+	}
+
+	var transaction appStoreTransactionPayload
+	if err := decodeJWSPayload(payload.Data.SignedTransactionInfo, &transaction); err != nil {
+		return entity.SubscriptionNotification{}, failure.InvalidBody
+	}
+
+	return entity.SubscriptionNotification{
+		OriginalTransactionId: &transaction.OriginalTransactionId,
+		ProductId:             &transaction.ProductId,
+	}, nil
+}
+
+func (p *AppStoreProvider) verifyReceipt(ctx context.Context, endpoint, receipt string) (appStoreReceiptResponse, error) {
+	input, err := json.Marshal(appStoreReceiptRequest{
+		ReceiptData:            receipt,
+		Password:               p.sharedSecret,
+		ExcludeOldTransactions: true,
+	})
+	if err != nil {
+		return appStoreReceiptResponse{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(input))
+	if err != nil {
+		return appStoreReceiptResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return appStoreReceiptResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	var response appStoreReceiptResponse
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func parseMillis(millis string) (time.Time, bool) {
+	value, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(value), true
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"strings"
+	"time"
+)
+
+const fakeReceiptSeparator = ":"
+
+// FakeProvider accepts any receipt for local development and testing of clients.
+// Receipt is transaction ID optionally followed by subscription status, e.g. `test-1:grace_period`.
+// Every verification renews subscription for fixed period
+type FakeProvider struct {
+	period time.Duration
+}
+
+func NewFakeProvider(period time.Duration) *FakeProvider {
+	return &FakeProvider{
+		period: period,
+	}
+}
+
+type fakeNotification struct {
+	ProductId string `json:"product_id"`
+	Receipt   string `json:"receipt"`
+}
+
+func (p *FakeProvider) VerifyReceipt(_ context.Context, productId, receipt string) (entity.SubscriptionState, error) {
+	parts := strings.SplitN(receipt, fakeReceiptSeparator, 2)
+	if parts[0] == "" {
+		return entity.SubscriptionState{}, failure.InvalidReceipt
+	}
+
+	state := entity.SubscriptionState{
+		ProductId:             productId,
+		OriginalTransactionId: parts[0],
+		Receipt:               receipt,
+		Status:                entity.SubscriptionStatusActive,
+		ExpiresAt:             time.Now().Add(p.period),
+		AutoRenew:             true,
+	}
+	if len(parts) > 1 {
+		state.Status = parts[1]
+	}
+
+	switch state.Status {
+	case entity.SubscriptionStatusActive, entity.SubscriptionStatusGracePeriod:
+	case entity.SubscriptionStatusExpired, entity.SubscriptionStatusRevoked:
+		state.ExpiresAt = time.Now()
+		state.AutoRenew = false
+	default:
+		return entity.SubscriptionState{}, failure.InvalidReceipt
+	}
+
+	return state, nil
+}
+
+func (p *FakeProvider) ParseNotification(body []byte) (entity.SubscriptionNotification, error) {
+	var notification fakeNotification
+	if err := json.Unmarshal(body, &notification); err != nil || notification.Receipt == "" {
+		return entity.SubscriptionNotification{}, failure.InvalidBody
+	}
+
+	return entity.SubscriptionNotification{
+		ProductId: &notification.ProductId,
+		Receipt:   &notification.Receipt,
+	}, nil
+}
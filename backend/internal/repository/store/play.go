@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"github.com/mephistolie/chefbook-server/pkg/logger"
+	"google.golang.org/api/androidpublisher/v3"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	"net/http"
+	"time"
+)
+
+const (
+	playPaymentStatePending = 0
+	playNotAcknowledged     = 0
+)
+
+type PlayProvider struct {
+	publisher   *androidpublisher.Service
+	packageName string
+}
+
+func NewPlayProvider(ctx context.Context, packageName, credentialsPath string) (*PlayProvider, error) {
+	publisher, err := androidpublisher.NewService(ctx, option.WithCredentialsFile(credentialsPath))
+	if err != nil {
+		return nil, err
+	}
+	return &PlayProvider{
+		publisher:   publisher,
+		packageName: packageName,
+	}, nil
+}
+
+// playPushMessage is Pub/Sub push request with real-time developer notification
+type playPushMessage struct {
+	Message struct {
+		Data string `json:"data"`
+	} `json:"message"`
+}
+
+type playDeveloperNotification struct {
+	PackageName              string `json:"packageName"`
+	SubscriptionNotification *struct {
+		PurchaseToken  string `json:"purchaseToken"`
+		SubscriptionId string `json:"subscriptionId"`
+	} `json:"subscriptionNotification"`
+}
+
+// VerifyReceipt checks subscription by purchase token and acknowledges new purchase.
+// Purchase token is used as original transaction ID because it's kept on renewals
+func (p *PlayProvider) VerifyReceipt(ctx context.Context, productId, receipt string) (entity.SubscriptionState, error) {
+	purchase, err := p.publisher.Purchases.Subscriptions.Get(p.packageName, productId, receipt).Context(ctx).Do()
+	if err != nil {
+		if apiErr, ok := err.(*googleapi.Error); ok && (apiErr.Code == http.StatusBadRequest || apiErr.Code == http.StatusNotFound ||
+			apiErr.Code == http.StatusGone) {
+			return entity.SubscriptionState{}, failure.InvalidReceipt
+		}
+		logger.Errorf("unable to verify Google Play purchase: %s", err.Error())
+		return entity.SubscriptionState{}, failure.UnableVerifyReceipt
+	}
+
+	state := entity.SubscriptionState{
+		ProductId:             productId,
+		OriginalTransactionId: receipt,
+		Receipt:               receipt,
+		Status:                entity.SubscriptionStatusExpired,
+		ExpiresAt:             time.UnixMilli(purchase.ExpiryTimeMillis),
+		AutoRenew:             purchase.AutoRenewing,
+	}
+	if state.ExpiresAt.After(time.Now()) {
+		state.Status = entity.SubscriptionStatusActive
+		// payment state isn't present for cancelled subscriptions, so pending payment is checked for renewing ones only
+		if purchase.AutoRenewing && purchase.PaymentState == playPaymentStatePending {
+			state.Status = entity.SubscriptionStatusGracePeriod
+		}
+	}
+
+	if purchase.AcknowledgementState == playNotAcknowledged && state.Status != entity.SubscriptionStatusExpired {
+		err = p.publisher.Purchases.Subscriptions.Acknowledge(p.packageName, productId, receipt,
+			&androidpublisher.SubscriptionPurchasesAcknowledgeRequest{}).Context(ctx).Do()
+		if err != nil {
+			logger.Errorf("unable to acknowledge Google Play purchase: %s", err.Error())
+			return entity.SubscriptionState{}, failure.UnableVerifyReceipt
+		}
+	}
+
+	return state, nil
+}
+
+// ParseNotification extracts purchase token of real-time developer notification. Test notifications are skipped
+func (p *PlayProvider) ParseNotification(body []byte) (entity.SubscriptionNotification, error) {
+	var message playPushMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return entity.SubscriptionNotification{}, failure.InvalidBody
+	}
+	data, err := base64.StdEncoding.DecodeString(message.Message.Data)
+	if err != nil {
+		return entity.SubscriptionNotification{}, failure.InvalidBody
+	}
+
+	var notification playDeveloperNotification
+	if err = json.Unmarshal(data, &notification); err != nil || notification.PackageName != p.packageName {
+		return entity.SubscriptionNotification{}, failure.InvalidBody
+	}
+	if notification.SubscriptionNotification == nil {
+		return entity.SubscriptionNotification{}, nil
+	}
+
+	return entity.SubscriptionNotification{
+		ProductId: &notification.SubscriptionNotification.SubscriptionId,
+		Receipt:   &notification.SubscriptionNotification.PurchaseToken,
+	}, nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+	"github.com/mephistolie/chefbook-server/pkg/logger"
+	"time"
+)
+
+type achievementRule struct {
+	code     string
+	events   []string
+	target   int
+	progress func(repo repository.Achievement, userId int) (int, error)
+}
+
+var achievementRules = []achievementRule{
+	{
+		code:     entity.AchievementFirstPublicRecipe,
+		events:   []string{entity.AchievementEventRecipePublished},
+		target:   1,
+		progress: repository.Achievement.GetPublicRecipesCount,
+	},
+	{
+		code:     entity.AchievementFirstRecipeSaved,
+		events:   []string{entity.AchievementEventRecipeSaved},
+		target:   1,
+		progress: repository.Achievement.GetRecipeSavesCount,
+	},
+	{
+		code:     entity.AchievementLikesReceived,
+		events:   []string{entity.AchievementEventRecipeLiked},
+		target:   100,
+		progress: repository.Achievement.GetReceivedLikesCount,
+	},
+	{
+		code:     entity.AchievementRecipesCooked,
+		events:   []string{entity.AchievementEventRecipeCooked},
+		target:   10,
+		progress: repository.Achievement.GetCookedRecipesCount,
+	},
+	{
+		code:   entity.AchievementCookingStreak,
+		events: []string{entity.AchievementEventRecipeCooked},
+		target: 7,
+		progress: func(repo repository.Achievement, userId int) (int, error) {
+			streak, err := repo.GetCookingStreak(userId)
+			return streak.Longest, err
+		},
+	},
+}
+
+type AchievementService struct {
+	achievementsRepo repository.Achievement
+	broccoinService  *BroccoinService
+	rewards          map[string]int
+}
+
+func NewAchievementService(achievementsRepo repository.Achievement, broccoinService *BroccoinService,
+	rewards map[string]int) *AchievementService {
+	return &AchievementService{
+		achievementsRepo: achievementsRepo,
+		broccoinService:  broccoinService,
+		rewards:          rewards,
+	}
+}
+
+func (s *AchievementService) GetAchievements(userId int) (entity.Achievements, error) {
+	unlocked, err := s.getUnlockedAchievements(userId)
+	if err != nil {
+		return entity.Achievements{}, err
+	}
+
+	achievements := make([]entity.Achievement, len(achievementRules))
+	for i, rule := range achievementRules {
+		achievements[i] = entity.Achievement{
+			Code:   rule.code,
+			Target: rule.target,
+			Reward: s.rewards[rule.code],
+		}
+		if progress, err := rule.progress(s.achievementsRepo, userId); err == nil {
+			achievements[i].Progress = progress
+		}
+		if timestamp, ok := unlocked[rule.code]; ok {
+			unlockTimestamp := timestamp
+			achievements[i].UnlockTimestamp = &unlockTimestamp
+			achievements[i].Progress = rule.target
+		} else if achievements[i].Progress > rule.target {
+			achievements[i].Progress = rule.target
+		}
+	}
+
+	streak, err := s.achievementsRepo.GetCookingStreak(userId)
+	if err != nil {
+		return entity.Achievements{}, err
+	}
+
+	return entity.Achievements{
+		Achievements:  achievements,
+		CookingStreak: streak,
+	}, nil
+}
+
+// AddRecipeCooking records cooked recipe for cooking achievements and streak
+func (s *AchievementService) AddRecipeCooking(recipeId, userId int) error {
+	if err := s.achievementsRepo.AddRecipeCooking(recipeId, userId); err != nil {
+		return err
+	}
+	s.HandleEvent(userId, entity.AchievementEventRecipeCooked)
+	return nil
+}
+
+// HandleEvent evaluates only locked achievements which depend on event and unlocks reached ones
+func (s *AchievementService) HandleEvent(userId int, event string) {
+	unlocked, err := s.getUnlockedAchievements(userId)
+	if err != nil {
+		return
+	}
+
+	for _, rule := range achievementRules {
+		if _, ok := unlocked[rule.code]; ok || !rule.dependsOn(event) {
+			continue
+		}
+
+		progress, err := rule.progress(s.achievementsRepo, userId)
+		if err != nil || progress < rule.target {
+			continue
+		}
+
+		isUnlocked, err := s.achievementsRepo.UnlockAchievement(userId, rule.code)
+		if err != nil || !isUnlocked {
+			continue
+		}
+		logger.Infof("user %d unlocked achievement %s", userId, rule.code)
+
+		reference := rule.code
+		s.broccoinService.Credit(userId, s.rewards[rule.code], entity.BroccoinReasonAchievement, &reference)
+	}
+}
+
+func (s *AchievementService) getUnlockedAchievements(userId int) (map[string]time.Time, error) {
+	achievements, err := s.achievementsRepo.GetUnlockedAchievements(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	unlocked := make(map[string]time.Time)
+	for _, achievement := range achievements {
+		unlocked[achievement.Code] = achievement.UnlockTimestamp
+	}
+	return unlocked, nil
+}
+
+func (r achievementRule) dependsOn(event string) bool {
+	for _, ruleEvent := range r.events {
+		if ruleEvent == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+	"github.com/mephistolie/chefbook-server/pkg/allergen"
+)
+
+const unknownAllergensBatchSize = 100
+
+type AllergenService struct {
+	repo repository.Allergen
+}
+
+func NewAllergenService(repo repository.Allergen) *AllergenService {
+	return &AllergenService{
+		repo: repo,
+	}
+}
+
+func (s *AllergenService) GetSupportedAllergens() []string {
+	return allergen.Supported()
+}
+
+// DetectUnknownAllergens fills allergens of unencrypted recipes created before allergens detection
+func (s *AllergenService) DetectUnknownAllergens() error {
+	for {
+		recipes, err := s.repo.GetRecipesWithUnknownAllergens(unknownAllergensBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, recipe := range recipes {
+			if err := s.repo.SetRecipeAllergens(recipe.Id, detectIngredientsAllergens(recipe.Ingredients)); err != nil {
+				return err
+			}
+		}
+
+		if len(recipes) < unknownAllergensBatchSize {
+			return nil
+		}
+	}
+}
+
+func detectRecipeAllergens(recipe entity.RecipeInput) *[]string {
+	if recipe.IsEncrypted {
+		return nil
+	}
+	allergens := detectIngredientsAllergens(recipe.Ingredients)
+	return &allergens
+}
+
+func detectIngredientsAllergens(ingredients []entity.IngredientItem) []string {
+	var texts []string
+	for _, ingredient := range ingredients {
+		if ingredient.Type == entity.TypeIngredient {
+			texts = append(texts, ingredient.Text)
+		}
+	}
+	return allergen.Detect(texts)
+}
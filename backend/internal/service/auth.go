@@ -23,12 +23,15 @@ type AuthService struct {
 	accessTokenTTL  time.Duration
 	refreshTokenTTL time.Duration
 
-	mailService MailService
-	domain      string
+	mailService     MailService
+	broccoinService *BroccoinService
+	referralService *ReferralService
+	domain          string
 }
 
 func NewAuthService(repo repository.Auth, firebaseService *FirebaseService, hashManager hash.HashManager, tokenManager auth.TokenManager,
-	accessTokenTTL time.Duration, refreshTokenTTL time.Duration, mailService MailService, domain string) *AuthService {
+	accessTokenTTL time.Duration, refreshTokenTTL time.Duration, mailService MailService, broccoinService *BroccoinService,
+	referralService *ReferralService, domain string) *AuthService {
 	return &AuthService{
 		repo:            repo,
 		firebaseService: firebaseService,
@@ -37,11 +40,21 @@ func NewAuthService(repo repository.Auth, firebaseService *FirebaseService, hash
 		accessTokenTTL:  accessTokenTTL,
 		refreshTokenTTL: refreshTokenTTL,
 		mailService:     mailService,
+		broccoinService: broccoinService,
+		referralService: referralService,
 		domain:          domain,
 	}
 }
 
-func (s *AuthService) SignUp(credentials entity.Credentials) (int, error) {
+func (s *AuthService) SignUp(credentials entity.Credentials, referralCode *string) (int, error) {
+	referrerId := 0
+	if referralCode != nil {
+		var err error
+		if referrerId, err = s.referralService.GetReferrerId(*referralCode); err != nil {
+			return 0, err
+		}
+	}
+
 	hashedPassword, err := s.hashManager.Hash(credentials.Password)
 	if err != nil {
 		return 0, failure.Unknown
@@ -64,6 +77,9 @@ func (s *AuthService) SignUp(credentials entity.Credentials) (int, error) {
 		if err != nil {
 			return 0, failure.Unknown
 		}
+		if referrerId > 0 {
+			s.referralService.AddInvitee(referrerId, candidate.Id)
+		}
 		return candidate.Id, s.sendActivationLink(credentials.Email, activationLink)
 	}
 
@@ -73,12 +89,22 @@ func (s *AuthService) SignUp(credentials entity.Credentials) (int, error) {
 	if err != nil {
 		return 0, err
 	}
+	if referrerId > 0 {
+		s.referralService.AddInvitee(referrerId, userId)
+	}
 
 	return userId, s.sendActivationLink(credentials.Email, activationLink)
 }
 
 func (s *AuthService) ActivateProfile(activationLink uuid.UUID) error {
-	return s.repo.ActivateProfile(activationLink)
+	userId, err := s.repo.ActivateProfile(activationLink)
+	if err != nil {
+		return err
+	}
+
+	s.referralService.RewardActivation(userId)
+
+	return nil
 }
 
 func (s *AuthService) SignIn(credentials entity.Credentials, ip string) (entity.Tokens, error) {
@@ -116,6 +142,7 @@ func (s *AuthService) SignIn(credentials entity.Credentials, ip string) (entity.
 	}
 
 	_ = s.repo.DeleteOldSessions(user.Id, maxSessionsCount)
+	s.broccoinService.RewardDailyLogin(user.Id)
 
 	return tokens, nil
 }
@@ -140,7 +167,12 @@ func (s *AuthService) RefreshSession(refreshToken, ip string) (entity.Tokens, er
 		return entity.Tokens{}, err
 	}
 
-	return tokens, s.repo.UpdateSession(session, refreshToken)
+	if err = s.repo.UpdateSession(session, refreshToken); err != nil {
+		return entity.Tokens{}, err
+	}
+	s.broccoinService.RewardDailyLogin(user.Id)
+
+	return tokens, nil
 }
 
 func (s *AuthService) sendActivationLink(email string, activationLink uuid.UUID) error {
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"fmt"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+	"github.com/mephistolie/chefbook-server/pkg/logger"
+	"time"
+)
+
+const dailyLoginReferenceLayout = "2006-01-02"
+
+type BroccoinService struct {
+	broccoinsRepo repository.Broccoin
+	params        entity.BroccoinParams
+}
+
+func NewBroccoinService(broccoinsRepo repository.Broccoin, params entity.BroccoinParams) *BroccoinService {
+	return &BroccoinService{
+		broccoinsRepo: broccoinsRepo,
+		params:        params,
+	}
+}
+
+func (s *BroccoinService) GetTransactions(userId int, query entity.BroccoinTransactionsQuery) ([]entity.BroccoinTransaction, error) {
+	return s.broccoinsRepo.GetBroccoinTransactions(userId, query)
+}
+
+// SpendOnPremium exchanges broccoins for premium days
+func (s *BroccoinService) SpendOnPremium(userId, days int) (entity.PremiumPurchase, error) {
+	if s.params.PremiumDayPrice <= 0 {
+		return entity.PremiumPurchase{}, failure.AccessDenied
+	}
+	return s.broccoinsRepo.PurchasePremium(userId, days*s.params.PremiumDayPrice, days)
+}
+
+// Credit adds broccoins to user balance. Credit with the same reason and reference is added once
+func (s *BroccoinService) Credit(userId, amount int, reason string, reference *string) {
+	if amount <= 0 {
+		return
+	}
+	_, err := s.broccoinsRepo.AddBroccoinTransaction(entity.BroccoinTransactionInput{
+		UserId:    userId,
+		Amount:    amount,
+		Reason:    reason,
+		Reference: reference,
+	})
+	if err != nil && err != failure.BroccoinTransactionExists {
+		logger.Errorf("unable to credit %d broccoins to user %d for %s: %s", amount, userId, reason, err.Error())
+	}
+}
+
+// RewardDailyLogin credits broccoins for the first sign in or session refresh of the day
+func (s *BroccoinService) RewardDailyLogin(userId int) {
+	reference := time.Now().UTC().Format(dailyLoginReferenceLayout)
+	s.Credit(userId, s.params.DailyLoginReward, entity.BroccoinReasonDailyLogin, &reference)
+}
+
+// RewardLike credits broccoins to recipe owner. Every user like of recipe is rewarded once, so repeated likes aren't rewarded
+func (s *BroccoinService) RewardLike(recipeId, ownerId, userId int) {
+	if ownerId == userId {
+		return
+	}
+	reference := fmt.Sprintf("recipe:%d:user:%d", recipeId, userId)
+	s.Credit(ownerId, s.params.LikeReward, entity.BroccoinReasonLikeReceived, &reference)
+}
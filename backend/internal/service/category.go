@@ -25,6 +25,12 @@ func (s *CategoriesService) GetRecipeCategories(recipeId, userId int) []entity.C
 }
 
 func (s *CategoriesService) CreateCategory(category entity.CategoryInput, userId int) (int, error) {
+	if category.ParentId != nil {
+		if _, err := s.GetCategory(*category.ParentId, userId); err != nil {
+			return 0, err
+		}
+	}
+
 	return s.repo.CreateCategory(category, userId)
 }
 
@@ -50,9 +56,75 @@ func (s *CategoriesService) UpdateCategory(categoryId int, category entity.Categ
 		return failure.AccessDenied
 	}
 
+	if category.ParentId != nil {
+		parents := s.getCategoryParents(userId)
+		if _, ok := parents[*category.ParentId]; !ok {
+			return failure.CategoryNotFound
+		}
+		parents[categoryId] = category.ParentId
+		if hasCategoryCycle(parents) {
+			return failure.CategoryCycle
+		}
+	}
+
 	return s.repo.UpdateCategory(categoryId, category)
 }
 
+// SetCategoriesOrder places every user category under passed parent. Order of categories sets positions among siblings
+func (s *CategoriesService) SetCategoriesOrder(categories []entity.CategoryPosition, userId int) error {
+	parents := s.getCategoryParents(userId)
+	if len(parents) != len(categories) {
+		return failure.InvalidCategoriesOrder
+	}
+
+	orderedParents := make(map[int]*int)
+	for _, category := range categories {
+		if _, ok := parents[category.Id]; !ok {
+			return failure.InvalidCategoriesOrder
+		}
+		orderedParents[category.Id] = category.ParentId
+	}
+	if len(orderedParents) != len(parents) {
+		return failure.InvalidCategoriesOrder
+	}
+	for _, parentId := range orderedParents {
+		if parentId == nil {
+			continue
+		}
+		if _, ok := orderedParents[*parentId]; !ok {
+			return failure.CategoryNotFound
+		}
+	}
+	if hasCategoryCycle(orderedParents) {
+		return failure.CategoryCycle
+	}
+
+	return s.repo.SetCategoriesOrder(userId, categories)
+}
+
+func (s *CategoriesService) getCategoryParents(userId int) map[int]*int {
+	categories := s.repo.GetUserCategories(userId)
+	parents := make(map[int]*int, len(categories))
+	for _, category := range categories {
+		parents[category.Id] = category.ParentId
+	}
+	return parents
+}
+
+// hasCategoryCycle walks up from every category. Path longer than categories count means cycle
+func hasCategoryCycle(parents map[int]*int) bool {
+	for categoryId := range parents {
+		parentId := parents[categoryId]
+		for depth := 0; parentId != nil; depth++ {
+			if depth >= len(parents) {
+				return true
+			}
+			parentId = parents[*parentId]
+		}
+	}
+	return false
+}
+
 func (s *CategoriesService) DeleteCategory(categoryId, userId int) error {
 	category, err := s.repo.GetCategory(categoryId)
 	if err != nil {
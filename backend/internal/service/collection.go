@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+)
+
+type CollectionService struct {
+	collectionsRepo    repository.Collection
+	categoriesRepo     repository.Category
+	tagsRepo           repository.Tag
+	picturesService    *RecipePicturesService
+	entitlementService *EntitlementService
+}
+
+func NewCollectionService(collectionsRepo repository.Collection, categoriesRepo repository.Category,
+	tagsRepo repository.Tag, picturesService *RecipePicturesService, entitlementService *EntitlementService) *CollectionService {
+	return &CollectionService{
+		collectionsRepo:    collectionsRepo,
+		categoriesRepo:     categoriesRepo,
+		tagsRepo:           tagsRepo,
+		picturesService:    picturesService,
+		entitlementService: entitlementService,
+	}
+}
+
+func (s *CollectionService) GetCollections(query entity.CollectionsQuery, userId int) ([]entity.Collection, error) {
+	collections, err := s.collectionsRepo.GetCollections(query, userId)
+	for i := range collections {
+		collections[i].Owned = collections[i].OwnerId == userId
+	}
+	return collections, err
+}
+
+func (s *CollectionService) GetCollection(collectionId, userId int) (entity.Collection, error) {
+	collection, err := s.collectionsRepo.GetCollection(collectionId, userId)
+	if err != nil {
+		return entity.Collection{}, err
+	}
+	collection.Owned = collection.OwnerId == userId
+
+	collection.Recipes, err = s.collectionsRepo.GetCollectionRecipes(collectionId, userId)
+	if err != nil {
+		return entity.Collection{}, err
+	}
+	recipeIds := make([]int, len(collection.Recipes))
+	for i := range collection.Recipes {
+		recipeIds[i] = collection.Recipes[i].Id
+	}
+	recipesTags := s.tagsRepo.GetRecipesTags(recipeIds)
+
+	for i := range collection.Recipes {
+		collection.Recipes[i].Tags = recipesTags[collection.Recipes[i].Id]
+		if collection.Recipes[i].Tags == nil {
+			collection.Recipes[i].Tags = []entity.Tag{}
+		}
+		collection.Recipes[i].Owned = collection.Recipes[i].OwnerId == userId
+		collection.Recipes[i].Preview, collection.Recipes[i].PreviewThumbnails = s.picturesService.GetPreviewLinks(context.Background(), collection.Recipes[i].Id,
+			collection.Recipes[i].Visibility, collection.Recipes[i].Preview)
+	}
+
+	return collection, nil
+}
+
+func (s *CollectionService) CreateCollection(collection entity.CollectionInput, userId int) (int, error) {
+	if err := s.entitlementService.CheckCollectionsLimit(userId); err != nil {
+		return 0, err
+	}
+
+	return s.collectionsRepo.CreateCollection(collection, userId)
+}
+
+func (s *CollectionService) UpdateCollection(collectionId int, collection entity.CollectionInput, userId int) error {
+	if err := s.checkCollectionOwner(collectionId, userId); err != nil {
+		return err
+	}
+
+	return s.collectionsRepo.UpdateCollection(collectionId, collection)
+}
+
+func (s *CollectionService) DeleteCollection(collectionId, userId int) error {
+	if err := s.checkCollectionOwner(collectionId, userId); err != nil {
+		return err
+	}
+
+	return s.collectionsRepo.DeleteCollection(collectionId)
+}
+
+func (s *CollectionService) SetCollectionRecipes(collectionId int, recipeIds []int, userId int) error {
+	if err := s.checkCollectionOwner(collectionId, userId); err != nil {
+		return err
+	}
+
+	return s.collectionsRepo.SetCollectionRecipes(collectionId, recipeIds, userId)
+}
+
+func (s *CollectionService) SetCollectionFollowed(collectionId int, isFollowed bool, userId int) error {
+	if err := s.checkNotCollectionOwner(collectionId, userId); err != nil {
+		return err
+	}
+
+	return s.collectionsRepo.SetCollectionFollowed(collectionId, isFollowed, userId)
+}
+
+func (s *CollectionService) SetCollectionSaved(collectionId int, isSaved bool, userId int) error {
+	if err := s.checkNotCollectionOwner(collectionId, userId); err != nil {
+		return err
+	}
+
+	return s.collectionsRepo.SetCollectionSaved(collectionId, isSaved, userId)
+}
+
+func (s *CollectionService) ConvertCategoryToCollection(categoryId int, collection entity.CollectionInput, userId int) (int, error) {
+	ownerId, err := s.categoriesRepo.GetCategoryOwnerId(categoryId)
+	if err != nil {
+		return 0, err
+	}
+	if ownerId != userId {
+		return 0, failure.AccessDenied
+	}
+	if err = s.entitlementService.CheckCollectionsLimit(userId); err != nil {
+		return 0, err
+	}
+
+	return s.collectionsRepo.ConvertCategoryToCollection(categoryId, collection, userId)
+}
+
+func (s *CollectionService) checkCollectionOwner(collectionId, userId int) error {
+	ownerId, err := s.collectionsRepo.GetCollectionOwnerId(collectionId)
+	if err != nil {
+		return err
+	}
+	if ownerId != userId {
+		return failure.AccessDenied
+	}
+	return nil
+}
+
+func (s *CollectionService) checkNotCollectionOwner(collectionId, userId int) error {
+	ownerId, err := s.collectionsRepo.GetCollectionOwnerId(collectionId)
+	if err != nil {
+		return err
+	}
+	if ownerId == userId {
+		return failure.UnableFollowOwnCollection
+	}
+	return nil
+}
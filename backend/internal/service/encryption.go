@@ -5,75 +5,98 @@ import (
 	"github.com/mephistolie/chefbook-server/internal/entity"
 	"github.com/mephistolie/chefbook-server/internal/entity/failure"
 	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+	"github.com/mephistolie/chefbook-server/pkg/logger"
+	"strings"
+	"time"
 )
 
+const legacyKeyLinkPrefix = "http"
+
 type EncryptionService struct {
-	encryptionRepo repository.Encryption
-	sharingRepo    repository.RecipeSharing
-	recipesRepo    repository.Recipe
-	filesRepo      repository.File
+	encryptionRepo     repository.Encryption
+	sharingRepo        repository.RecipeSharing
+	recipesRepo        repository.Recipe
+	filesRepo          repository.File
+	quotaService       *QuotaService
+	entitlementService *EntitlementService
+	keyLinkTTL         time.Duration
 }
 
-func NewEncryptionService(encryptionRepo repository.Encryption, sharingRepo repository.RecipeSharing, recipesRepo repository.Recipe, filesRepo repository.File) *EncryptionService {
+func NewEncryptionService(encryptionRepo repository.Encryption, sharingRepo repository.RecipeSharing, recipesRepo repository.Recipe, filesRepo repository.File,
+	quotaService *QuotaService, entitlementService *EntitlementService, keyLinkTTL time.Duration) *EncryptionService {
 	return &EncryptionService{
-		encryptionRepo: encryptionRepo,
-		sharingRepo:    sharingRepo,
-		recipesRepo:    recipesRepo,
-		filesRepo:      filesRepo,
+		encryptionRepo:     encryptionRepo,
+		sharingRepo:        sharingRepo,
+		recipesRepo:        recipesRepo,
+		filesRepo:          filesRepo,
+		quotaService:       quotaService,
+		entitlementService: entitlementService,
+		keyLinkTTL:         keyLinkTTL,
 	}
 }
 
-func (s *EncryptionService) GetUserKeyLink(userId int) (string, error) {
-	link, err := s.encryptionRepo.GetUserKeyLink(userId)
+func (s *EncryptionService) GetUserKeyLink(ctx context.Context, userId int) (string, error) {
+	objectKey, err := s.encryptionRepo.GetUserKeyLink(userId)
 	if err != nil {
 		return "", err
 	}
 
-	if link == nil {
+	if objectKey == nil {
 		return "", failure.NoKey
 	}
 
-	return *link, nil
+	return s.getKeyLink(ctx, *objectKey)
 }
 
 func (s *EncryptionService) UploadUserKey(ctx context.Context, userId int, file entity.MultipartFile) (string, error) {
-	previousUrl, err := s.encryptionRepo.GetUserKeyLink(userId)
+	previousObjectKey, err := s.encryptionRepo.GetUserKeyLink(userId)
 	if err != nil {
 		return "", err
 	}
-
-	link, err := s.filesRepo.UploadUserKey(ctx, userId, file)
+	if previousObjectKey == nil {
+		if err = s.entitlementService.CheckEncryption(userId); err != nil {
+			return "", err
+		}
+	}
+	objectKey := getUserKeyFileKey(userId, file.Name)
+	replacedKey, err := s.reserveKey(userId, nil, objectKey, file.Size, previousObjectKey)
 	if err != nil {
 		return "", err
 	}
-	err = s.encryptionRepo.SetUserKeyLink(userId, &link)
+
+	if _, err = s.filesRepo.UploadUserKey(ctx, userId, file); err != nil {
+		s.quotaService.ReleaseFile(objectKey, replacedKey)
+		return "", err
+	}
+	err = s.encryptionRepo.SetUserKeyLink(userId, &objectKey)
 	if err != nil {
-		_ = s.filesRepo.DeleteFile(ctx, link)
+		_ = s.filesRepo.DeletePrivateFile(ctx, objectKey)
+		s.quotaService.ReleaseFile(objectKey, replacedKey)
 		return "", err
 	}
 
-	if previousUrl != nil {
-		_ = s.filesRepo.DeleteFile(ctx, *previousUrl)
+	if previousObjectKey != nil && *previousObjectKey != objectKey {
+		_ = s.deleteKey(ctx, *previousObjectKey)
 	}
 
-	return link, err
+	return s.getKeyLink(ctx, objectKey)
 }
 
 func (s *EncryptionService) DeleteUserKey(ctx context.Context, userId int) error {
-	link, err := s.encryptionRepo.GetUserKeyLink(userId)
+	objectKey, err := s.encryptionRepo.GetUserKeyLink(userId)
 	if err != nil {
 		return err
 	}
 
-	if link != nil {
-		_ = s.filesRepo.DeleteFile(ctx, *link)
+	if objectKey != nil {
+		_ = s.deleteKey(ctx, *objectKey)
 	}
 
 	err = s.encryptionRepo.SetUserKeyLink(userId, nil)
 	return err
 }
 
-func (s *EncryptionService) GetRecipeKey(recipeId, userId int) (string, error) {
+func (s *EncryptionService) GetRecipeKey(ctx context.Context, recipeId, userId int) (string, error) {
 	ownerId, err := s.recipesRepo.GetRecipeOwnerId(recipeId)
 	if err != nil {
 		return "", err
@@ -82,16 +105,16 @@ func (s *EncryptionService) GetRecipeKey(recipeId, userId int) (string, error) {
 		return "", failure.NotOwner
 	}
 
-	link, err := s.encryptionRepo.GetRecipeKeyLink(recipeId)
+	objectKey, err := s.encryptionRepo.GetRecipeKeyLink(recipeId)
 	if err != nil {
 		return "", err
 	}
 
-	if link == nil {
+	if objectKey == nil {
 		return "", failure.NoKey
 	}
 
-	return *link, err
+	return s.getKeyLink(ctx, *objectKey)
 }
 
 func (s *EncryptionService) UploadRecipeKey(ctx context.Context, recipeId, userId int, file entity.MultipartFile) (string, error) {
@@ -103,26 +126,37 @@ func (s *EncryptionService) UploadRecipeKey(ctx context.Context, recipeId, userI
 		return "", failure.NotOwner
 	}
 
-	previousLink, err := s.encryptionRepo.GetRecipeKeyLink(recipeId)
+	previousObjectKey, err := s.encryptionRepo.GetRecipeKeyLink(recipeId)
 	if err != nil {
 		return "", err
 	}
-
-	url, err := s.filesRepo.UploadRecipeKey(ctx, recipeId, file)
+	if previousObjectKey == nil {
+		if err = s.entitlementService.CheckEncryption(userId); err != nil {
+			return "", err
+		}
+	}
+	objectKey := getRecipeKeyFileKey(recipeId, file.Name)
+	replacedKey, err := s.reserveKey(userId, &recipeId, objectKey, file.Size, previousObjectKey)
 	if err != nil {
 		return "", err
 	}
-	err = s.encryptionRepo.SetRecipeKeyLink(recipeId, &url)
+
+	if _, err = s.filesRepo.UploadRecipeKey(ctx, recipeId, file); err != nil {
+		s.quotaService.ReleaseFile(objectKey, replacedKey)
+		return "", err
+	}
+	err = s.encryptionRepo.SetRecipeKeyLink(recipeId, &objectKey)
 	if err != nil {
-		_ = s.filesRepo.DeleteFile(ctx, url)
+		_ = s.filesRepo.DeletePrivateFile(ctx, objectKey)
+		s.quotaService.ReleaseFile(objectKey, replacedKey)
 		return "", err
 	}
 
-	if previousLink != nil {
-		_ = s.filesRepo.DeleteFile(ctx, *previousLink)
+	if previousObjectKey != nil && *previousObjectKey != objectKey {
+		_ = s.deleteKey(ctx, *previousObjectKey)
 	}
 
-	return url, err
+	return s.getKeyLink(ctx, objectKey)
 }
 
 func (s *EncryptionService) DeleteRecipeKey(ctx context.Context, recipeId, userId int) error {
@@ -134,13 +168,13 @@ func (s *EncryptionService) DeleteRecipeKey(ctx context.Context, recipeId, userI
 		return failure.NotOwner
 	}
 
-	link, err := s.encryptionRepo.GetRecipeKeyLink(recipeId)
+	objectKey, err := s.encryptionRepo.GetRecipeKeyLink(recipeId)
 	if err != nil {
 		return err
 	}
 
-	if link != nil {
-		err = s.filesRepo.DeleteFile(ctx, *link)
+	if objectKey != nil {
+		err = s.deleteKey(ctx, *objectKey)
 		if err != nil {
 			return err
 		}
@@ -153,3 +187,68 @@ func (s *EncryptionService) DeleteRecipeKey(ctx context.Context, recipeId, userI
 
 	return nil
 }
+
+// MigrateKeys revokes public access to keys uploaded before private storage and stores their object keys
+func (s *EncryptionService) MigrateKeys(ctx context.Context) (int, error) {
+	migrated := 0
+
+	userKeys, err := s.encryptionRepo.GetPublicUserKeyLinks()
+	if err != nil {
+		return migrated, err
+	}
+	for userId, link := range userKeys {
+		objectKey := s.filesRepo.GetObjectKey(link)
+		if err := s.filesRepo.MakeFilePrivate(ctx, objectKey); err != nil {
+			logger.Errorf("unable to migrate key of user %d: %s", userId, err.Error())
+			continue
+		}
+		if err := s.encryptionRepo.SetUserKeyLink(userId, &objectKey); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+
+	recipeKeys, err := s.encryptionRepo.GetPublicRecipeKeyLinks()
+	if err != nil {
+		return migrated, err
+	}
+	for recipeId, link := range recipeKeys {
+		objectKey := s.filesRepo.GetObjectKey(link)
+		if err := s.filesRepo.MakeFilePrivate(ctx, objectKey); err != nil {
+			logger.Errorf("unable to migrate key of recipe %d: %s", recipeId, err.Error())
+			continue
+		}
+		if err := s.encryptionRepo.SetRecipeKeyLink(recipeId, &objectKey); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+
+	return migrated, nil
+}
+
+// getKeyLink returns short-lived link to key. Keys which haven't been migrated yet keep their public links
+func (s *EncryptionService) getKeyLink(ctx context.Context, objectKey string) (string, error) {
+	if strings.HasPrefix(objectKey, legacyKeyLinkPrefix) {
+		return objectKey, nil
+	}
+	return s.filesRepo.GetPrivateFileLink(ctx, objectKey, s.keyLinkTTL)
+}
+
+// reserveKey counts key in user quota before its upload and returns object key of replaced key,
+// which isn't counted in usage
+func (s *EncryptionService) reserveKey(userId int, recipeId *int, objectKey string, size int64, previousObjectKey *string) (*string, error) {
+	if previousObjectKey != nil {
+		replacedKey := s.filesRepo.GetObjectKey(*previousObjectKey)
+		previousObjectKey = &replacedKey
+	}
+	return previousObjectKey, s.quotaService.ReserveFile(userId, recipeId, objectKey, size, previousObjectKey)
+}
+
+func (s *EncryptionService) deleteKey(ctx context.Context, objectKey string) error {
+	s.quotaService.RemoveFiles(s.filesRepo.GetObjectKey(objectKey))
+	if strings.HasPrefix(objectKey, legacyKeyLinkPrefix) {
+		return s.filesRepo.DeleteFile(ctx, s.filesRepo.GetObjectKey(objectKey))
+	}
+	return s.filesRepo.DeletePrivateFile(ctx, objectKey)
+}
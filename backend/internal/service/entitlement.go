@@ -0,0 +1,80 @@
+package service
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+	"time"
+)
+
+type EntitlementService struct {
+	authRepo       repository.Auth
+	collectionRepo repository.Collection
+	free           entity.TierEntitlements
+	premium        entity.TierEntitlements
+}
+
+func NewEntitlementService(authRepo repository.Auth, collectionRepo repository.Collection,
+	free, premium entity.TierEntitlements) *EntitlementService {
+	return &EntitlementService{
+		authRepo:       authRepo,
+		collectionRepo: collectionRepo,
+		free:           free,
+		premium:        premium,
+	}
+}
+
+// GetEntitlements returns features and limits of user tier. Premium is active until its end date
+func (s *EntitlementService) GetEntitlements(userId int) (entity.Entitlements, error) {
+	user, err := s.authRepo.GetUserById(userId)
+	if err != nil {
+		return entity.Entitlements{}, err
+	}
+
+	entitlements := entity.Entitlements{
+		PremiumEndDate:   user.PremiumEndDate,
+		TierEntitlements: s.free,
+	}
+	if isPremium(user.PremiumEndDate) {
+		entitlements.IsPremium = true
+		entitlements.TierEntitlements = s.premium
+	}
+
+	return entitlements, nil
+}
+
+// CheckEncryption checks that user can start using encryption. Already encrypted data stays available without it
+func (s *EntitlementService) CheckEncryption(userId int) error {
+	entitlements, err := s.GetEntitlements(userId)
+	if err != nil {
+		return err
+	}
+	if !entitlements.Encryption {
+		return failure.PremiumRequired
+	}
+	return nil
+}
+
+// CheckCollectionsLimit checks that user can create one more collection. Zero limit means unlimited collections
+func (s *EntitlementService) CheckCollectionsLimit(userId int) error {
+	entitlements, err := s.GetEntitlements(userId)
+	if err != nil {
+		return err
+	}
+	if entitlements.MaxCollections == 0 {
+		return nil
+	}
+
+	count, err := s.collectionRepo.GetCollectionsCount(userId)
+	if err != nil {
+		return err
+	}
+	if count >= entitlements.MaxCollections {
+		return failure.TooManyCollections
+	}
+	return nil
+}
+
+func isPremium(premiumEndDate *time.Time) bool {
+	return premiumEndDate != nil && premiumEndDate.After(time.Now())
+}
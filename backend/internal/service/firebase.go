@@ -21,10 +21,12 @@ type FirebaseService struct {
 	recipesOwnershipRepo repository.RecipeOwnership
 	categoriesRepo       repository.Category
 	shoppingListRepo     repository.ShoppingList
+	broccoinService      *BroccoinService
 }
 
 func NewFirebaseService(migrationRepo repository.FirebaseMigration, usersRepo repository.Auth, profileRepo repository.Profile, recipeRepo repository.Recipe,
-	recipeOwnershipRepo repository.RecipeOwnership, categoriesRepo repository.Category, shoppingListRepo repository.ShoppingList) *FirebaseService {
+	recipeOwnershipRepo repository.RecipeOwnership, categoriesRepo repository.Category, shoppingListRepo repository.ShoppingList,
+	broccoinService *BroccoinService) *FirebaseService {
 	return &FirebaseService{
 		migrationRepo:        migrationRepo,
 		usersRepo:            usersRepo,
@@ -33,6 +35,7 @@ func NewFirebaseService(migrationRepo repository.FirebaseMigration, usersRepo re
 		recipesOwnershipRepo: recipeOwnershipRepo,
 		categoriesRepo:       categoriesRepo,
 		shoppingListRepo:     shoppingListRepo,
+		broccoinService:      broccoinService,
 	}
 }
 
@@ -51,7 +54,7 @@ func (s *FirebaseService) MigrateFromFirebase(credentials entity.Credentials, fi
 	if err != nil {
 		return err
 	}
-	if err = s.usersRepo.ActivateProfile(activationLink); err != nil {
+	if _, err = s.usersRepo.ActivateProfile(activationLink); err != nil {
 		logger.Warn("migration: error during activating user")
 	}
 
@@ -84,9 +87,7 @@ func (s *FirebaseService) importProfileInfo(userId int, profile entity.FirebaseP
 		}
 	}
 
-	if err := s.profileRepo.IncreaseBroccoins(userId, oldUserBroccoins); err != nil {
-		logger.Warn("migration: error during adding broccoins to old user")
-	}
+	s.broccoinService.Credit(userId, oldUserBroccoins, entity.BroccoinReasonMigrationBonus, nil)
 }
 
 func (s *FirebaseService) importCategories(userId int, categories []entity.CategoryInput) map[string]int {
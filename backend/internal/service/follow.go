@@ -0,0 +1,57 @@
+package service
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+)
+
+type FollowService struct {
+	followRepo repository.Follow
+	authRepo   repository.Auth
+	filesRepo  repository.File
+}
+
+func NewFollowService(followRepo repository.Follow, authRepo repository.Auth, filesRepo repository.File) *FollowService {
+	return &FollowService{
+		followRepo: followRepo,
+		authRepo:   authRepo,
+		filesRepo:  filesRepo,
+	}
+}
+
+func (s *FollowService) FollowUser(authorId, userId int) error {
+	if authorId == userId {
+		return failure.UnableFollowYourself
+	}
+
+	if _, err := s.authRepo.GetUserById(authorId); err != nil {
+		return err
+	}
+
+	return s.followRepo.FollowUser(authorId, userId)
+}
+
+func (s *FollowService) UnfollowUser(authorId, userId int) error {
+	return s.followRepo.UnfollowUser(authorId, userId)
+}
+
+func (s *FollowService) GetFollowers(userId int) ([]entity.ProfileInfo, error) {
+	if _, err := s.authRepo.GetUserById(userId); err != nil {
+		return []entity.ProfileInfo{}, err
+	}
+
+	followers, err := s.followRepo.GetFollowers(userId)
+	setProfilesAvatarLinks(s.filesRepo, followers)
+	return followers, err
+}
+
+func (s *FollowService) GetFollowing(userId int) ([]entity.ProfileInfo, error) {
+	if _, err := s.authRepo.GetUserById(userId); err != nil {
+		return []entity.ProfileInfo{}, err
+	}
+
+	following, err := s.followRepo.GetFollowing(userId)
+	setProfilesAvatarLinks(s.filesRepo, following)
+	return following, err
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+	"github.com/mephistolie/chefbook-server/pkg/imaging"
+	"io"
+	"strings"
+)
+
+const processedImageNameLength = 16
+
+// processImage re-encodes uploaded picture and generates its thumbnails. Picture has to be decoded as a whole,
+// so not more than maxSize bytes are read. Processed picture is named by hash of its content, so same pictures share storage
+func processImage(processor imaging.Processor, file entity.MultipartFile, maxSize int64) (entity.MultipartFile, map[int]entity.MultipartFile, error) {
+	if file.IsUndecodableImage() {
+		return entity.MultipartFile{}, nil, failure.UnsupportedImage
+	}
+	content, err := io.ReadAll(io.LimitReader(file.Content, maxSize+1))
+	if err != nil || int64(len(content)) > maxSize {
+		return entity.MultipartFile{}, nil, failure.InvalidFileSize
+	}
+
+	img, err := processor.Process(content)
+	if errors.Is(err, imaging.ErrTooManyPixels) {
+		return entity.MultipartFile{}, nil, failure.TooLargeImage
+	}
+	if err != nil {
+		return entity.MultipartFile{}, nil, failure.UnsupportedFileType
+	}
+
+	hash := sha256.Sum256(img.Content)
+	name := hex.EncodeToString(hash[:processedImageNameLength]) + imaging.Extension
+
+	thumbnails := make(map[int]entity.MultipartFile)
+	for size, thumbnail := range img.Thumbnails {
+		thumbnails[size] = newImageFile(name, thumbnail)
+	}
+
+	return newImageFile(name, img.Content), thumbnails, nil
+}
+
+// getPictureSize returns size of picture with its thumbnails, which is counted in storage usage
+func getPictureSize(file entity.MultipartFile, thumbnails map[int]entity.MultipartFile) int64 {
+	size := file.Size
+	for _, thumbnail := range thumbnails {
+		size += thumbnail.Size
+	}
+	return size
+}
+
+func uploadThumbnails(ctx context.Context, filesRepo repository.File, objectKey string, thumbnails map[int]entity.MultipartFile, isPublic bool) error {
+	for size, thumbnail := range thumbnails {
+		if err := filesRepo.UploadThumbnail(ctx, objectKey, size, thumbnail, isPublic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteThumbnails(ctx context.Context, filesRepo repository.File, processor imaging.Processor, objectKey string) {
+	for _, thumbnail := range getThumbnailKeys(filesRepo, processor, objectKey) {
+		_ = filesRepo.DeleteFile(ctx, thumbnail)
+	}
+}
+
+// getThumbnailKeys returns object keys of thumbnails by their size. Only pictures processed by server have thumbnails
+func getThumbnailKeys(filesRepo repository.File, processor imaging.Processor, objectKey string) map[int]string {
+	if !strings.HasSuffix(objectKey, imaging.Extension) {
+		return nil
+	}
+
+	thumbnails := make(map[int]string)
+	for _, size := range processor.ThumbnailSizes() {
+		thumbnails[size] = filesRepo.GetThumbnailKey(objectKey, size)
+	}
+	return thumbnails
+}
+
+// getAvatarLinks returns public links to avatar and its thumbnails by avatar object key
+func getAvatarLinks(filesRepo repository.File, processor imaging.Processor, avatar *string) (*string, map[int]string) {
+	if avatar == nil {
+		return nil, nil
+	}
+
+	objectKey := filesRepo.GetObjectKey(*avatar)
+	thumbnails := getThumbnailKeys(filesRepo, processor, objectKey)
+	for size, thumbnail := range thumbnails {
+		thumbnails[size] = filesRepo.GetFileLink(thumbnail)
+	}
+	link := filesRepo.GetFileLink(objectKey)
+
+	return &link, thumbnails
+}
+
+func setProfilesAvatarLinks(filesRepo repository.File, profiles []entity.ProfileInfo) {
+	for i := range profiles {
+		if profiles[i].Avatar != nil {
+			link := filesRepo.GetFileLink(filesRepo.GetObjectKey(*profiles[i].Avatar))
+			profiles[i].Avatar = &link
+		}
+	}
+}
+
+func newImageFile(name string, content []byte) entity.MultipartFile {
+	return entity.MultipartFile{
+		Name:        name,
+		Content:     bytes.NewReader(content),
+		Size:        int64(len(content)),
+		ContentType: imaging.ContentType,
+	}
+}
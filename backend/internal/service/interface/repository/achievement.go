@@ -0,0 +1,14 @@
+package repository
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Achievement interface {
+	GetUnlockedAchievements(userId int) ([]entity.UnlockedAchievement, error)
+	UnlockAchievement(userId int, code string) (bool, error)
+	AddRecipeCooking(recipeId, userId int) error
+	GetPublicRecipesCount(userId int) (int, error)
+	GetRecipeSavesCount(userId int) (int, error)
+	GetReceivedLikesCount(userId int) (int, error)
+	GetCookedRecipesCount(userId int) (int, error)
+	GetCookingStreak(userId int) (entity.CookingStreak, error)
+}
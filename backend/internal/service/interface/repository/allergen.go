@@ -0,0 +1,8 @@
+package repository
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Allergen interface {
+	GetRecipesWithUnknownAllergens(limit int) ([]entity.Recipe, error)
+	SetRecipeAllergens(recipeId int, allergens []string) error
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Broccoin interface {
+	GetBroccoinTransactions(userId int, query entity.BroccoinTransactionsQuery) ([]entity.BroccoinTransaction, error)
+	AddBroccoinTransaction(input entity.BroccoinTransactionInput) (entity.BroccoinTransaction, error)
+	PurchasePremium(userId, price, days int) (entity.PremiumPurchase, error)
+}
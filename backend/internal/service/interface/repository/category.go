@@ -9,5 +9,6 @@ type Category interface {
 	GetCategoryOwnerId(categoryId int) (int, error)
 	CreateCategory(category entity.CategoryInput, userId int) (int, error)
 	UpdateCategory(categoryId int, category entity.CategoryInput) error
+	SetCategoriesOrder(userId int, categories []entity.CategoryPosition) error
 	DeleteCategory(categoryId int) error
 }
@@ -0,0 +1,18 @@
+package repository
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Collection interface {
+	GetCollections(params entity.CollectionsQuery, userId int) ([]entity.Collection, error)
+	GetCollection(collectionId, userId int) (entity.Collection, error)
+	GetCollectionRecipes(collectionId, userId int) ([]entity.RecipeInfo, error)
+	GetCollectionOwnerId(collectionId int) (int, error)
+	GetCollectionsCount(userId int) (int, error)
+	CreateCollection(collection entity.CollectionInput, userId int) (int, error)
+	UpdateCollection(collectionId int, collection entity.CollectionInput) error
+	DeleteCollection(collectionId int) error
+	SetCollectionRecipes(collectionId int, recipeIds []int, ownerId int) error
+	SetCollectionFollowed(collectionId int, isFollowed bool, userId int) error
+	SetCollectionSaved(collectionId int, isSaved bool, userId int) error
+	ConvertCategoryToCollection(categoryId int, collection entity.CollectionInput, userId int) (int, error)
+}
@@ -2,7 +2,9 @@ package repository
 
 type Encryption interface {
 	GetUserKeyLink(userId int) (*string, error)
-	SetUserKeyLink(userId int, url *string) error
+	SetUserKeyLink(userId int, objectKey *string) error
 	GetRecipeKeyLink(recipeId int) (*string, error)
-	SetRecipeKeyLink(recipeId int, url *string) error
+	SetRecipeKeyLink(recipeId int, objectKey *string) error
+	GetPublicUserKeyLinks() (map[int]string, error)
+	GetPublicRecipeKeyLinks() (map[int]string, error)
 }
@@ -3,14 +3,29 @@ package repository
 import (
 	"context"
 	"github.com/mephistolie/chefbook-server/internal/entity"
+	"time"
 )
 
 type File interface {
+	GetFiles(ctx context.Context) ([]entity.StoredFile, error)
 	UploadAvatar(ctx context.Context, userId int, input entity.MultipartFile) (string, error)
 	UploadUserKey(ctx context.Context, userId int, input entity.MultipartFile) (string, error)
 	GetRecipePictures(ctx context.Context, recipeId int) []string
-	UploadRecipePicture(ctx context.Context, recipeId int, input entity.MultipartFile) (string, error)
+	UploadRecipePicture(ctx context.Context, recipeId int, input entity.MultipartFile, isPublic bool) (string, error)
+	UploadThumbnail(ctx context.Context, objectKey string, size int, input entity.MultipartFile, isPublic bool) error
+	GetThumbnailKey(objectKey string, size int) string
+	GetRecipePictureLink(ctx context.Context, recipeId int, objectKey string, ttl time.Duration) (string, error)
+	GetRecipePictureUploadLink(ctx context.Context, recipeId int, uploadId string, input entity.UploadInput, ttl time.Duration) (entity.UploadLink, error)
+	GetRecipePictureUpload(ctx context.Context, recipeId int, uploadId string) (entity.MultipartFile, error)
+	CommitRecipePictureUpload(ctx context.Context, recipeId int, uploadId string, isPublic bool) (string, error)
+	DeleteRecipePictureUpload(ctx context.Context, recipeId int, uploadId string) error
+	SetRecipePicturesAccess(ctx context.Context, recipeId int, isPublic bool) error
 	DeleteRecipePicture(ctx context.Context, recipeId int, pictureName string) error
 	UploadRecipeKey(ctx context.Context, recipeId int, input entity.MultipartFile) (string, error)
-	DeleteFile(ctx context.Context, url string) error
+	DeleteFile(ctx context.Context, objectKey string) error
+	GetPrivateFileLink(ctx context.Context, objectKey string, ttl time.Duration) (string, error)
+	DeletePrivateFile(ctx context.Context, objectKey string) error
+	MakeFilePrivate(ctx context.Context, objectKey string) error
+	GetFileLink(objectKey string) string
+	GetObjectKey(link string) string
 }
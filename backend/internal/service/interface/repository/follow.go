@@ -0,0 +1,10 @@
+package repository
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Follow interface {
+	FollowUser(authorId, followerId int) error
+	UnfollowUser(authorId, followerId int) error
+	GetFollowers(userId int) ([]entity.ProfileInfo, error)
+	GetFollowing(userId int) ([]entity.ProfileInfo, error)
+}
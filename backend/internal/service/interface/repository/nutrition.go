@@ -0,0 +1,8 @@
+package repository
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Nutrition interface {
+	AddFoods(foods []entity.Food) error
+	FindFood(text, language string, minConfidence float64) (entity.FoodMatch, error)
+}
@@ -6,15 +6,19 @@ type RecipeOwnership interface {
 	CreateRecipe(recipe entity.RecipeInput, userId int) (int, error)
 	UpdateRecipe(recipeId int, recipe entity.RecipeInput) error
 	DeleteRecipe(recipeId int) error
+	SetRecipePictures(recipeId int, preview *string, cooking []entity.CookingItem) error
 }
 
 type Recipe interface {
 	GetRecipes(params entity.RecipesQuery, userId int) ([]entity.RecipeInfo, error)
+	GetRecipesTagFacets(params entity.RecipesQuery, userId int) ([]entity.TagFacet, error)
 	GetRecipe(recipeId int) (entity.Recipe, error)
-	GetRandomRecipe(languages *[]string, userId int) (entity.UserRecipe, error)
+	GetRandomRecipe(languages *[]string, excludedAllergens []string, userId int) (entity.UserRecipe, error)
 	GetRecipeWithUserFields(recipeId int, userId int) (entity.UserRecipe, error)
 	GetRecipeOwnerId(recipeId int) (int, error)
-	AddRecipeToRecipeBook(recipeId, userId int) error
+	GetNonPublicRecipeIds() ([]int, error)
+	GetRecipeIdsWithPictureLinks() ([]int, error)
+	AddRecipeToRecipeBook(recipeId, userId int, linkId *int) error
 	RemoveRecipeFromRecipeBook(recipeId, userId int) error
 	SetRecipeCategories(recipeId int, categoriesIds []int, userId int) error
 	SetRecipeFavourite(recipeId int, isFavourite bool, userId int) error
@@ -26,5 +30,12 @@ type RecipeSharing interface {
 	GetUserPublicKey(recipeId, userId int) (string, error)
 	SetUserPublicKeyLink(recipeId int, userId int, userKey *string) error
 	GetUserRecipeKey(recipeId, userId int) (string, error)
+	GetApprovedRecipeKey(recipeId, userId int) (string, error)
 	SetOwnerPrivateKeyLinkForUser(recipeId int, userId int, userKey *string) error
+	CreateKeyRequest(recipeId, userId int, userKey string) (int, error)
+	GetKeyRequest(requestId int) (entity.RecipeKeyRequest, error)
+	GetRecipeKeyRequests(recipeId int, status *string) ([]entity.RecipeKeyRequest, error)
+	GetUserKeyRequests(userId int, status *string) ([]entity.RecipeKeyRequest, error)
+	ApproveKeyRequest(requestId int, recipeKey string) error
+	SetKeyRequestStatus(requestId int, status string) error
 }
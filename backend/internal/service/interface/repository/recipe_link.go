@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"time"
+)
+
+type RecipeLink interface {
+	CreateRecipeLink(recipeId int, token string, expiresAt *time.Time) (entity.RecipeLink, error)
+	GetRecipeLinks(recipeId int) ([]entity.RecipeLink, error)
+	GetActiveRecipeLink(token string) (entity.RecipeLink, error)
+	DeleteRecipeLink(linkId, recipeId int) error
+	IsRecipeSavedByActiveLink(recipeId, userId int) bool
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type RecipePicture interface {
+	GetRecipePictures(recipeId int) ([]entity.RecipePicture, error)
+	GetRecipePicture(pictureId, recipeId int) (entity.RecipePicture, error)
+	AddRecipePicture(recipeId int, objectKey string) (entity.RecipePicture, error)
+	SetRecipePictureCaption(pictureId, recipeId int, caption *string) error
+	SetRecipePreview(recipeId int, preview *string) error
+	SetRecipePicturesOrder(recipeId int, pictureIds []int) error
+	DeleteRecipePicture(pictureId, recipeId int) error
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Recommendation interface {
+	UpdateSimilarities(limit int) error
+	UpdateIngredientTokens() error
+	GetCollaborativeRecommendations(userId int, languages *[]string, limit int) ([]entity.RecipeInfo, error)
+	GetContentRecommendations(userId int, languages *[]string, limit int) ([]entity.RecipeInfo, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Referral interface {
+	GetReferralCode(userId int) (*string, error)
+	SetReferralCode(userId int, code string) (string, error)
+	GetUserIdByReferralCode(code string) (int, error)
+	GetReferrals(referrerId int) ([]entity.Referral, error)
+	GetPendingReferralsCount(referrerId int) (int, error)
+	AddReferral(referrerId, inviteeId int) error
+	ActivateReferral(inviteeId int, limits entity.ReferralLimits, referrerPremiumDays, inviteePremiumDays int) (entity.Referral, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"time"
+)
+
+type Storage interface {
+	GetStorageReferences() (entity.StorageReferences, error)
+	GetStorageUsage(userId int, excludedKey *string) (entity.StorageUsage, error)
+	ReserveUserFile(file entity.UserFile, quota entity.StorageQuota, replacedKey *string) error
+	GetUserFileKeys(countedBefore time.Time) ([]string, error)
+	DeleteUserFiles(objectKeys []string) error
+	GetRecipeOwnerIds() (map[int]int, error)
+	SetUserFiles(files []entity.UserFile) (int, error)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"time"
+)
+
+type Subscription interface {
+	GetSubscriptions(userId int) ([]entity.Subscription, error)
+	GetSubscription(provider, originalTransactionId string) (entity.Subscription, error)
+	GetExpiredSubscriptions(expiredBefore time.Time) ([]entity.Subscription, error)
+	SetSubscription(subscription entity.Subscription) (entity.Subscription, error)
+}
+
+// SubscriptionProvider verifies purchases with store
+type SubscriptionProvider interface {
+	VerifyReceipt(ctx context.Context, productId, receipt string) (entity.SubscriptionState, error)
+	ParseNotification(body []byte) (entity.SubscriptionNotification, error)
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Tag interface {
+	GetTags() ([]entity.Tag, error)
+	GetRecipeTags(recipeId int) []entity.Tag
+	GetRecipesTags(recipeIds []int) map[int][]entity.Tag
+	SetRecipeTags(recipeId int, tagIds []string) error
+}
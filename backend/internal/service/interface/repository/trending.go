@@ -0,0 +1,8 @@
+package repository
+
+import "github.com/mephistolie/chefbook-server/internal/entity"
+
+type Trending interface {
+	AddRecipeView(recipeId, userId int) error
+	UpdateTrendingScores(params entity.TrendingParams) error
+}
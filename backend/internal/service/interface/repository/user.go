@@ -12,7 +12,7 @@ type Auth interface {
 	GetUserByEmail(email string) (entity.Profile, error)
 	GetUserByRefreshToken(refreshToken string) (entity.Profile, error)
 	GetUserActivationLink(userId int) (uuid.UUID, error)
-	ActivateProfile(activationLink uuid.UUID) error
+	ActivateProfile(activationLink uuid.UUID) (int, error)
 	ChangePassword(userId int, password string) error
 	CreateSession(session entity.Session) error
 	UpdateSession(session entity.Session, oldRefreshToken string) error
@@ -21,9 +21,13 @@ type Auth interface {
 }
 
 type Profile interface {
+	GetPublicProfile(userId, requesterId int) (entity.PublicProfile, error)
 	SetUsername(userId int, username *string) error
+	SetBio(userId int, bio *string) error
+	GetExcludedAllergens(userId int) ([]string, error)
+	SetExcludedAllergens(userId int, allergens []string) error
+	GetAvatarLinks() (map[int]string, error)
 	SetAvatarLink(userId int, url *string) error
 	SetPremiumDate(userId int, expiresAt time.Time) error
 	SetProfileCreationDate(userId int, creationTimestamp time.Time) error
-	IncreaseBroccoins(userId, broccoins int) error
 }
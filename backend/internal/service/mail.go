@@ -25,6 +25,11 @@ type verificationEmailInput struct {
 	VerificationLink string
 }
 
+type keyRequestResolvedEmailInput struct {
+	RecipeName string
+	IsApproved bool
+}
+
 func NewMailService(sender emailProvider.Sender, config config.MailConfig, cache cache.Cache) *MailService {
 	return &MailService{
 		sender: sender,
@@ -50,6 +55,21 @@ func (s *MailService) SendVerificationEmail(input entity.VerificationEmailInput)
 	return nil
 }
 
+func (s *MailService) SendKeyRequestResolvedEmail(input entity.KeyRequestResolvedEmailInput) error {
+	templateInput := keyRequestResolvedEmailInput{RecipeName: input.RecipeName, IsApproved: input.IsApproved}
+	sendInput := emailProvider.SendEmailInput{Subject: s.config.Subjects.KeyRequestResolved, To: input.Email}
+
+	if err := sendInput.GenerateBodyFromHTML(s.config.Templates.KeyRequestResolved, templateInput); err != nil {
+		return err
+	}
+
+	if err := s.sender.Send(sendInput); err != nil {
+		return failure.UnableSendEmail
+	}
+
+	return nil
+}
+
 func (s *MailService) createVerificationLink(domain string, code uuid.UUID) string {
 	return fmt.Sprintf(verificationLinkTmpl, domain, code)
 }
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	foodsImportBatchSize = 500
+
+	// maxFilledIngredients limits food lookups made while saving recipe
+	maxFilledIngredients = 30
+)
+
+// unitGrams contains grams per unit. Units are normalized to lowercase letters only, so 'ст. л.' turns into 'стл'.
+// Volume units are converted with water density
+var unitGrams = map[string]float64{
+	"g": 1, "gr": 1, "gram": 1, "grams": 1, "г": 1, "гр": 1, "грамм": 1,
+	"kg": 1000, "кг": 1000,
+	"mg": 0.001, "мг": 0.001,
+	"ml": 1, "мл": 1,
+	"l": 1000, "л": 1000, "литр": 1000,
+	"tsp": 5, "teaspoon": 5, "teaspoons": 5, "чл": 5,
+	"tbsp": 15, "tablespoon": 15, "tablespoons": 15, "стл": 15,
+	"cup": 240, "cups": 240, "стакан": 250, "стакана": 250, "стаканов": 250,
+	"oz": 28.35, "lb": 453.6, "lbs": 453.6,
+}
+
+type NutritionService struct {
+	nutritionRepo repository.Nutrition
+	recipeRepo    repository.Recipe
+	linksRepo     repository.RecipeLink
+	params        entity.NutritionParams
+}
+
+func NewNutritionService(nutritionRepo repository.Nutrition, recipeRepo repository.Recipe, linksRepo repository.RecipeLink,
+	params entity.NutritionParams) *NutritionService {
+	return &NutritionService{
+		nutritionRepo: nutritionRepo,
+		recipeRepo:    recipeRepo,
+		linksRepo:     linksRepo,
+		params:        params,
+	}
+}
+
+func (s *NutritionService) GetRecipeNutrition(recipeId, userId int, linkToken *string) (entity.Nutrition, error) {
+	recipe, err := s.recipeRepo.GetRecipe(recipeId)
+	if err != nil {
+		return entity.Nutrition{}, err
+	}
+
+	if _, err = checkRecipeAccess(s.linksRepo, recipe.Id, recipe.OwnerId, recipe.Visibility, userId, linkToken); err != nil {
+		return entity.Nutrition{}, err
+	}
+	if recipe.IsEncrypted {
+		return entity.Nutrition{}, failure.UnableCalculateNutrition
+	}
+
+	return s.CalculateNutrition(recipe.Ingredients, recipe.Servings, recipe.Language), nil
+}
+
+// CalculateNutrition matches ingredients with imported foods and sums nutrients per serving.
+// Ingredients without amount in weight or volume units can't be counted and get zero confidence
+func (s *NutritionService) CalculateNutrition(ingredients []entity.IngredientItem, servings *int16, language string) entity.Nutrition {
+	nutrition := entity.Nutrition{
+		Ingredients: []entity.IngredientNutrition{},
+	}
+
+	var calories, protein, fats, carbohydrates, confidenceSum float64
+	for _, ingredient := range ingredients {
+		if ingredient.Type != entity.TypeIngredient {
+			continue
+		}
+
+		ingredientNutrition := entity.IngredientNutrition{
+			Text: ingredient.Text,
+		}
+
+		match, err := s.findFood(ingredient.Text, language)
+		if err == nil && match.Confidence >= s.params.MinConfidence {
+			ingredientNutrition.Food = &match.Food.Name
+			ingredientNutrition.Confidence = match.Confidence
+
+			if grams := getIngredientGrams(ingredient); grams != nil {
+				ingredientCalories := match.Food.Calories * *grams / 100
+				ingredientNutrition.Grams = grams
+				ingredientNutrition.Calories = &ingredientCalories
+
+				calories += ingredientCalories
+				protein += match.Food.Protein * *grams / 100
+				fats += match.Food.Fats * *grams / 100
+				carbohydrates += match.Food.Carbohydrates * *grams / 100
+			} else {
+				ingredientNutrition.Confidence = 0
+			}
+		}
+
+		confidenceSum += ingredientNutrition.Confidence
+		nutrition.Ingredients = append(nutrition.Ingredients, ingredientNutrition)
+	}
+
+	if len(nutrition.Ingredients) == 0 {
+		return nutrition
+	}
+	nutrition.Confidence = confidenceSum / float64(len(nutrition.Ingredients))
+
+	if calories == 0 {
+		return nutrition
+	}
+
+	servingsCount := 1.0
+	if servings != nil && *servings > 0 {
+		servingsCount = float64(*servings)
+	}
+	nutrition.Calories = roundNutrient(calories / servingsCount)
+	nutrition.Macronutrients = entity.Macronutrients{
+		Protein:       roundNutrient(protein / servingsCount),
+		Fats:          roundNutrient(fats / servingsCount),
+		Carbohydrates: roundNutrient(carbohydrates / servingsCount),
+	}
+
+	return nutrition
+}
+
+// FillMissingNutrition sets calculated calories and macronutrients if owner hasn't specified any of them.
+// Recipes with too many ingredients are skipped to keep saving fast; their nutrition is still available on demand
+func (s *NutritionService) FillMissingNutrition(recipe *entity.RecipeInput) {
+	if recipe.IsEncrypted || recipe.Calories != nil || recipe.Macronutrients.Protein != nil ||
+		recipe.Macronutrients.Fats != nil || recipe.Macronutrients.Carbohydrates != nil {
+		return
+	}
+
+	ingredientsCount := 0
+	for _, ingredient := range recipe.Ingredients {
+		if ingredient.Type == entity.TypeIngredient {
+			ingredientsCount++
+		}
+	}
+	if ingredientsCount > maxFilledIngredients {
+		return
+	}
+
+	nutrition := s.CalculateNutrition(recipe.Ingredients, recipe.Servings, recipe.Language)
+	if nutrition.Calories == nil || nutrition.Confidence < s.params.MinConfidence {
+		return
+	}
+
+	recipe.Calories = nutrition.Calories
+	recipe.Macronutrients = nutrition.Macronutrients
+}
+
+func (s *NutritionService) ImportFoods(reader io.Reader, format entity.FoodsCsvFormat) (int, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = format.Delimiter
+	csvReader.LazyQuotes = true
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return 0, failure.InvalidFoodsFile
+	}
+	columns := make(map[string]int)
+	for i, column := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))] = i
+	}
+	for _, column := range []string{format.IdColumn, format.NameColumn, format.CaloriesColumn, format.ProteinColumn,
+		format.FatsColumn, format.CarbohydratesColumn} {
+		if _, ok := columns[column]; !ok {
+			return 0, failure.InvalidFoodsFile
+		}
+	}
+
+	imported := 0
+	var batch []entity.Food
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return imported, failure.InvalidFoodsFile
+		}
+
+		food := entity.Food{
+			Source:        format.Source,
+			ExternalId:    getCsvValue(record, columns[format.IdColumn]),
+			Name:          getCsvValue(record, columns[format.NameColumn]),
+			Language:      format.Language,
+			Calories:      parseNutrientValue(getCsvValue(record, columns[format.CaloriesColumn])),
+			Protein:       parseNutrientValue(getCsvValue(record, columns[format.ProteinColumn])),
+			Fats:          parseNutrientValue(getCsvValue(record, columns[format.FatsColumn])),
+			Carbohydrates: parseNutrientValue(getCsvValue(record, columns[format.CarbohydratesColumn])),
+		}
+		if food.ExternalId == "" || food.Name == "" {
+			continue
+		}
+
+		batch = append(batch, food)
+		if len(batch) >= foodsImportBatchSize {
+			if err := s.nutritionRepo.AddFoods(batch); err != nil {
+				return imported, err
+			}
+			imported += len(batch)
+			batch = batch[:0]
+		}
+	}
+
+	if len(batch) > 0 {
+		if err := s.nutritionRepo.AddFoods(batch); err != nil {
+			return imported, err
+		}
+		imported += len(batch)
+	}
+
+	return imported, nil
+}
+
+func (s *NutritionService) findFood(text, language string) (entity.FoodMatch, error) {
+	match, err := s.nutritionRepo.FindFood(text, language, s.params.MinConfidence)
+	if err == nil {
+		return match, nil
+	}
+	return s.nutritionRepo.FindFood(text, "", s.params.MinConfidence)
+}
+
+func getIngredientGrams(ingredient entity.IngredientItem) *float64 {
+	if ingredient.Amount == nil || *ingredient.Amount <= 0 || ingredient.Unit == nil {
+		return nil
+	}
+
+	unit := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, *ingredient.Unit)
+
+	gramsPerUnit, ok := unitGrams[unit]
+	if !ok {
+		return nil
+	}
+
+	grams := float64(*ingredient.Amount) * gramsPerUnit
+	return &grams
+}
+
+func getCsvValue(record []string, index int) string {
+	if index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
+
+// parseNutrientValue supports decimal commas and values like '< 0,5' used by food composition tables.
+// Missing values and traces are counted as zero
+func parseNutrientValue(value string) float64 {
+	value = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(value), "<"))
+	value = strings.ReplaceAll(value, ",", ".")
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed < 0 {
+		return 0
+	}
+	return parsed
+}
+
+func roundNutrient(value float64) *int16 {
+	rounded := int16(math.Min(math.Round(value), math.MaxInt16))
+	return &rounded
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/mephistolie/chefbook-server/internal/entity/failure"
 	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
 	"github.com/mephistolie/chefbook-server/pkg/hash"
+	"github.com/mephistolie/chefbook-server/pkg/imaging"
 )
 
 type ProfileService struct {
@@ -13,20 +14,45 @@ type ProfileService struct {
 	profileRepo repository.Profile
 	filesRepo   repository.File
 
-	hashManager hash.HashManager
+	quotaService   *QuotaService
+	hashManager    hash.HashManager
+	imageProcessor imaging.Processor
+	avatarMaxSize  int64
 }
 
-func NewProfileService(usersRepo repository.Auth, profileRepo repository.Profile, filesRepo repository.File, hashManager hash.HashManager) *ProfileService {
+func NewProfileService(usersRepo repository.Auth, profileRepo repository.Profile, filesRepo repository.File, quotaService *QuotaService,
+	hashManager hash.HashManager, imageProcessor imaging.Processor, avatarMaxSize int64) *ProfileService {
 	return &ProfileService{
-		authRepo:    usersRepo,
-		profileRepo: profileRepo,
-		filesRepo:   filesRepo,
-		hashManager: hashManager,
+		authRepo:       usersRepo,
+		profileRepo:    profileRepo,
+		filesRepo:      filesRepo,
+		quotaService:   quotaService,
+		hashManager:    hashManager,
+		imageProcessor: imageProcessor,
+		avatarMaxSize:  avatarMaxSize,
 	}
 }
 
 func (s *ProfileService) GetProfile(userId int) (entity.Profile, error) {
-	return s.authRepo.GetUserById(userId)
+	profile, err := s.authRepo.GetUserById(userId)
+	if err != nil {
+		return profile, err
+	}
+
+	profile.Avatar, profile.AvatarThumbnails = getAvatarLinks(s.filesRepo, s.imageProcessor, profile.Avatar)
+	if usage, err := s.quotaService.GetStorageUsage(userId); err == nil {
+		profile.StorageUsage = &usage
+	}
+
+	return profile, nil
+}
+
+func (s *ProfileService) GetPublicProfile(userId, requesterId int) (entity.PublicProfile, error) {
+	profile, err := s.profileRepo.GetPublicProfile(userId, requesterId)
+	if err == nil {
+		profile.Avatar, profile.AvatarThumbnails = getAvatarLinks(s.filesRepo, s.imageProcessor, profile.Avatar)
+	}
+	return profile, err
 }
 
 func (s *ProfileService) ChangePassword(userId int, oldPassword string, newPassword string) error {
@@ -51,27 +77,83 @@ func (s *ProfileService) SetUsername(userId int, username *string) error {
 	return s.profileRepo.SetUsername(userId, username)
 }
 
+func (s *ProfileService) SetBio(userId int, bio *string) error {
+	if bio != nil && len(*bio) == 0 {
+		bio = nil
+	}
+	return s.profileRepo.SetBio(userId, bio)
+}
+
+func (s *ProfileService) SetExcludedAllergens(userId int, allergens []string) error {
+	return s.profileRepo.SetExcludedAllergens(userId, allergens)
+}
+
 func (s *ProfileService) UploadAvatar(ctx context.Context, userId int, file entity.MultipartFile) (string, error) {
 	user, err := s.authRepo.GetUserById(userId)
 	if err != nil {
 		return "", err
 	}
 
-	url, err := s.filesRepo.UploadAvatar(ctx, userId, file)
+	file, thumbnails, err := processImage(s.imageProcessor, file, s.avatarMaxSize)
 	if err != nil {
+		return "", err
+	}
+
+	var previousObjectKey *string
+	if user.Avatar != nil {
+		objectKey := s.filesRepo.GetObjectKey(*user.Avatar)
+		previousObjectKey = &objectKey
+	}
+	objectKey := getAvatarKey(userId, file.Name)
+	if err = s.quotaService.ReserveFile(userId, nil, objectKey, getPictureSize(file, thumbnails), previousObjectKey); err != nil {
+		return "", err
+	}
+
+	if _, err = s.filesRepo.UploadAvatar(ctx, userId, file); err != nil {
+		s.quotaService.ReleaseFile(objectKey, previousObjectKey)
+		return "", failure.UnableUploadFile
+	}
+	if err = uploadThumbnails(ctx, s.filesRepo, objectKey, thumbnails, true); err != nil {
+		s.quotaService.ReleaseFile(objectKey, previousObjectKey)
 		return "", failure.UnableUploadFile
 	}
-	err = s.profileRepo.SetAvatarLink(userId, &url)
+	err = s.profileRepo.SetAvatarLink(userId, &objectKey)
 	if err != nil {
-		_ = s.filesRepo.DeleteFile(ctx, url)
+		_ = s.filesRepo.DeleteFile(ctx, objectKey)
+		deleteThumbnails(ctx, s.filesRepo, s.imageProcessor, objectKey)
+		s.quotaService.ReleaseFile(objectKey, previousObjectKey)
 		return "", failure.UnableSetAvatar
 	}
 
-	if user.Avatar != nil {
-		_ = s.filesRepo.DeleteFile(ctx, *user.Avatar)
+	if previousObjectKey != nil && *previousObjectKey != objectKey {
+		_ = s.filesRepo.DeleteFile(ctx, *previousObjectKey)
+		deleteThumbnails(ctx, s.filesRepo, s.imageProcessor, *previousObjectKey)
+		s.quotaService.RemoveFiles(*previousObjectKey)
+	}
+
+	return s.filesRepo.GetFileLink(objectKey), nil
+}
+
+// MigrateAvatarLinks replaces absolute links to avatars in storage with object keys
+func (s *ProfileService) MigrateAvatarLinks() (int, error) {
+	links, err := s.profileRepo.GetAvatarLinks()
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for userId, link := range links {
+		objectKey := s.filesRepo.GetObjectKey(link)
+		if objectKey == link {
+			continue
+		}
+		if err := s.profileRepo.SetAvatarLink(userId, &objectKey); err != nil {
+			return migrated, err
+		}
+		migrated++
 	}
 
-	return url, nil
+	return migrated, nil
 }
 
 func (s *ProfileService) DeleteAvatar(ctx context.Context, userId int) error {
@@ -80,10 +162,13 @@ func (s *ProfileService) DeleteAvatar(ctx context.Context, userId int) error {
 		return err
 	}
 
-	err = s.filesRepo.DeleteFile(ctx, *user.Avatar)
+	objectKey := s.filesRepo.GetObjectKey(*user.Avatar)
+	err = s.filesRepo.DeleteFile(ctx, objectKey)
 	if err != nil {
 		return err
 	}
+	deleteThumbnails(ctx, s.filesRepo, s.imageProcessor, objectKey)
+	s.quotaService.RemoveFiles(objectKey)
 	err = s.profileRepo.SetAvatarLink(userId, nil)
 	if err != nil {
 		return err
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+	"github.com/mephistolie/chefbook-server/pkg/imaging"
+	"github.com/mephistolie/chefbook-server/pkg/logger"
+	"strconv"
+	"strings"
+)
+
+type QuotaService struct {
+	storageRepo        repository.Storage
+	filesRepo          repository.File
+	entitlementService *EntitlementService
+	imageProcessor     imaging.Processor
+}
+
+func NewQuotaService(storageRepo repository.Storage, filesRepo repository.File, entitlementService *EntitlementService,
+	imageProcessor imaging.Processor) *QuotaService {
+	return &QuotaService{
+		storageRepo:        storageRepo,
+		filesRepo:          filesRepo,
+		entitlementService: entitlementService,
+		imageProcessor:     imageProcessor,
+	}
+}
+
+// GetStorageUsage returns size and count of user files with limits of user tier
+func (s *QuotaService) GetStorageUsage(userId int) (entity.StorageUsage, error) {
+	return s.getStorageUsage(userId, nil)
+}
+
+// ReserveFile counts file in usage of user before its upload if it fits into user quota. Reservation is atomic,
+// so parallel uploads can't exceed quota. Replaced file isn't counted in usage. Same object key is counted once
+func (s *QuotaService) ReserveFile(userId int, recipeId *int, objectKey string, size int64, replacedKey *string) error {
+	entitlements, err := s.entitlementService.GetEntitlements(userId)
+	if err != nil {
+		return err
+	}
+
+	file := entity.UserFile{
+		Key:      objectKey,
+		UserId:   userId,
+		RecipeId: recipeId,
+		Size:     size,
+	}
+	return s.storageRepo.ReserveUserFile(file, entitlements.StorageQuota, replacedKey)
+}
+
+// ReleaseFile cancels reservation of file which upload failed. File reserved in place of itself stays counted
+func (s *QuotaService) ReleaseFile(objectKey string, replacedKey *string) {
+	if replacedKey == nil || *replacedKey != objectKey {
+		s.RemoveFiles(objectKey)
+	}
+}
+
+// RemoveFiles stops counting deleted files in usage of their owners
+func (s *QuotaService) RemoveFiles(objectKeys ...string) {
+	if err := s.storageRepo.DeleteUserFiles(objectKeys); err != nil {
+		logger.Errorf("unable to remove files %s from storage usage: %s", strings.Join(objectKeys, ", "), err.Error())
+	}
+}
+
+// RecountUsage recalculates usage of all users by storage content. Reservations of direct uploads
+// which aren't uploaded yet are dropped
+func (s *QuotaService) RecountUsage(ctx context.Context) (int, error) {
+	files, err := s.filesRepo.GetFiles(ctx)
+	if err != nil {
+		return 0, err
+	}
+	recipeOwners, err := s.storageRepo.GetRecipeOwnerIds()
+	if err != nil {
+		return 0, err
+	}
+
+	sizes := make(map[string]int64)
+	for _, file := range files {
+		sizes[file.Key] = file.Size
+	}
+	thumbnails := make(map[string]bool)
+	for _, file := range files {
+		for _, thumbnail := range getThumbnailKeys(s.filesRepo, s.imageProcessor, file.Key) {
+			thumbnails[thumbnail] = true
+		}
+	}
+
+	var userFiles []entity.UserFile
+	for _, file := range files {
+		if thumbnails[file.Key] {
+			continue
+		}
+		userFile, ok := getUserFile(file.Key, recipeOwners)
+		if !ok {
+			continue
+		}
+		userFile.Size = file.Size
+		for _, thumbnail := range getThumbnailKeys(s.filesRepo, s.imageProcessor, file.Key) {
+			userFile.Size += sizes[thumbnail]
+		}
+		userFiles = append(userFiles, userFile)
+	}
+
+	return s.storageRepo.SetUserFiles(userFiles)
+}
+
+func (s *QuotaService) getStorageUsage(userId int, excludedKey *string) (entity.StorageUsage, error) {
+	entitlements, err := s.entitlementService.GetEntitlements(userId)
+	if err != nil {
+		return entity.StorageUsage{}, err
+	}
+	usage, err := s.storageRepo.GetStorageUsage(userId, excludedKey)
+	if err != nil {
+		return entity.StorageUsage{}, err
+	}
+	usage.MaxBytes, usage.MaxFiles = entitlements.StorageQuota.MaxBytes, entitlements.StorageQuota.MaxFiles
+
+	return usage, nil
+}
+
+// getUserFile finds owner of file by its location. Only avatars, keys, recipe pictures and pending uploads are counted
+func getUserFile(objectKey string, recipeOwners map[int]int) (entity.UserFile, bool) {
+	parts := strings.SplitN(objectKey, "/", 4)
+	if len(parts) < 4 {
+		return entity.UserFile{}, false
+	}
+	ownerId, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return entity.UserFile{}, false
+	}
+
+	switch parts[0] {
+	case storageUsersDir:
+		if parts[2] == storageAvatarsDir || parts[2] == storageKeysDir {
+			return entity.UserFile{Key: objectKey, UserId: ownerId}, true
+		}
+	case storageRecipesDir:
+		userId, ok := recipeOwners[ownerId]
+		if ok && (parts[2] == storageImagesDir || parts[2] == storageKeysDir || parts[2] == storageUploadsDir) {
+			recipeId := ownerId
+			return entity.UserFile{Key: objectKey, UserId: userId, RecipeId: &recipeId}, true
+		}
+	}
+
+	return entity.UserFile{}, false
+}
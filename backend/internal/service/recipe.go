@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"github.com/mephistolie/chefbook-server/internal/entity"
 	"github.com/mephistolie/chefbook-server/internal/entity/failure"
 	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
@@ -10,64 +11,137 @@ import (
 type RecipeService struct {
 	recipesRepo            repository.Recipe
 	categoriesRepo         repository.Category
+	trendingRepo           repository.Trending
+	tagsRepo               repository.Tag
+	profileRepo            repository.Profile
+	linksRepo              repository.RecipeLink
+	picturesService        *RecipePicturesService
+	broccoinService        *BroccoinService
+	achievementService     *AchievementService
 }
 
-func NewRecipeService(recipesRepo repository.Recipe, categoriesRepo repository.Category) *RecipeService {
+func NewRecipeService(recipesRepo repository.Recipe, categoriesRepo repository.Category, trendingRepo repository.Trending,
+	tagsRepo repository.Tag, profileRepo repository.Profile, linksRepo repository.RecipeLink,
+	picturesService *RecipePicturesService, broccoinService *BroccoinService,
+	achievementService *AchievementService) *RecipeService {
 	return &RecipeService{
 		recipesRepo:            recipesRepo,
 		categoriesRepo:         categoriesRepo,
+		trendingRepo:           trendingRepo,
+		tagsRepo:               tagsRepo,
+		profileRepo:            profileRepo,
+		linksRepo:              linksRepo,
+		picturesService:        picturesService,
+		broccoinService:        broccoinService,
+		achievementService:     achievementService,
 	}
 }
 
 func (s *RecipeService)	GetRecipes(query entity.RecipesQuery, userId int) ([]entity.RecipeInfo, error) {
+	query.ExcludedAllergens = s.getExcludedAllergens(userId)
 	recipes, err := s.recipesRepo.GetRecipes(query, userId)
 
+	recipeIds := make([]int, len(recipes))
+	for i := range recipes {
+		recipeIds[i] = recipes[i].Id
+	}
+	recipesTags := s.tagsRepo.GetRecipesTags(recipeIds)
+
 	for i := range recipes {
 		recipes[i].Categories= s.categoriesRepo.GetRecipeCategories(recipes[i].Id, userId)
+		recipes[i].Tags = recipesTags[recipes[i].Id]
+		if recipes[i].Tags == nil {
+			recipes[i].Tags = []entity.Tag{}
+		}
 		if recipes[i].OwnerId == userId {
 			recipes[i].Owned = true
 		}
+		recipes[i].Preview, recipes[i].PreviewThumbnails = s.picturesService.GetPreviewLinks(context.Background(), recipes[i].Id,
+			recipes[i].Visibility, recipes[i].Preview)
 	}
 	return recipes, err
 }
 
-func (s *RecipeService) GetRecipe(recipeId, userId int) (entity.UserRecipe, error) {
+func (s *RecipeService) GetRecipesTagFacets(query entity.RecipesQuery, userId int) ([]entity.TagFacet, error) {
+	query.ExcludedAllergens = s.getExcludedAllergens(userId)
+	return s.recipesRepo.GetRecipesTagFacets(query, userId)
+}
+
+func (s *RecipeService) GetFeed(query entity.RecipesQuery, userId int) ([]entity.RecipeInfo, error) {
+	return s.GetRecipes(getFeedQuery(query), userId)
+}
+
+func (s *RecipeService) GetFeedTagFacets(query entity.RecipesQuery, userId int) ([]entity.TagFacet, error) {
+	return s.GetRecipesTagFacets(getFeedQuery(query), userId)
+}
+
+func (s *RecipeService) GetRecipe(recipeId, userId int, linkToken *string) (entity.UserRecipe, error) {
 	recipe, err := s.recipesRepo.GetRecipeWithUserFields(recipeId, userId)
 	if err != nil {
 		return entity.UserRecipe{}, err
 	}
 
-	if strings.ToLower(recipe.Visibility) == entity.VisibilityPrivate && recipe.OwnerId != userId {
-		return entity.UserRecipe{}, failure.AccessDenied
+	if _, err := checkRecipeAccess(s.linksRepo, recipe.Id, recipe.OwnerId, recipe.Visibility, userId, linkToken); err != nil {
+		return entity.UserRecipe{}, err
 	}
+	recipe.Preview, recipe.PreviewThumbnails = s.picturesService.GetPreviewLinks(context.Background(), recipe.Id,
+		recipe.Visibility, recipe.Preview)
+	recipe.Cooking = s.picturesService.GetCookingLinks(context.Background(), recipe.Id, recipe.Visibility, recipe.Cooking)
 
 	recipe.Categories = s.categoriesRepo.GetRecipeCategories(recipeId, userId)
+	recipe.Tags = s.tagsRepo.GetRecipeTags(recipeId)
 	if recipe.OwnerId == userId {
 		recipe.Owned = true
+	} else {
+		_ = s.trendingRepo.AddRecipeView(recipeId, userId)
 	}
 
 	return recipe, err
 }
 
+func (s *RecipeService) GetSharedRecipe(linkToken string, userId int) (entity.UserRecipe, error) {
+	link, err := s.linksRepo.GetActiveRecipeLink(linkToken)
+	if err != nil {
+		return entity.UserRecipe{}, err
+	}
+
+	return s.GetRecipe(link.RecipeId, userId, &linkToken)
+}
+
 func (s *RecipeService) GetRandomRecipe(languages *[]string, userId int) (entity.UserRecipe, error) {
-	return s.recipesRepo.GetRandomRecipe(languages, userId)
+	recipe, err := s.recipesRepo.GetRandomRecipe(languages, s.getExcludedAllergens(userId), userId)
+	if err != nil {
+		return entity.UserRecipe{}, err
+	}
+
+	recipe.Tags = s.tagsRepo.GetRecipeTags(recipe.Id)
+	recipe.Preview, recipe.PreviewThumbnails = s.picturesService.GetPreviewLinks(context.Background(), recipe.Id,
+		recipe.Visibility, recipe.Preview)
+	recipe.Cooking = s.picturesService.GetCookingLinks(context.Background(), recipe.Id, recipe.Visibility, recipe.Cooking)
+
+	return recipe, nil
 }
 
-func (s *RecipeService) AddRecipeToRecipeBook(recipeId, userId int) error {
+func (s *RecipeService) AddRecipeToRecipeBook(recipeId, userId int, linkToken *string) error {
 	recipe, err := s.recipesRepo.GetRecipe(recipeId)
 	if err != nil {
 		return err
 	}
 
-	if strings.ToLower(recipe.Visibility) == entity.VisibilityPrivate && recipe.OwnerId != userId {
-		return failure.AccessDenied
+	linkId, err := checkRecipeAccess(s.linksRepo, recipe.Id, recipe.OwnerId, recipe.Visibility, userId, linkToken)
+	if err != nil {
+		return err
 	}
 
-	err = s.recipesRepo.AddRecipeToRecipeBook(recipeId, userId)
+	err = s.recipesRepo.AddRecipeToRecipeBook(recipeId, userId, linkId)
 	if err != nil {
 		return failure.UnableAddRecipe
 	}
 
+	if recipe.OwnerId != userId {
+		s.achievementService.HandleEvent(recipe.OwnerId, entity.AchievementEventRecipeSaved)
+	}
+
 	return nil
 }
 
@@ -83,6 +157,73 @@ func (s *RecipeService) SetRecipeFavourite(recipeId int, favourite bool, userId
 	return s.recipesRepo.SetRecipeFavourite(recipeId, favourite, userId)
 }
 
-func (s *RecipeService) SetRecipeLikeStatus(recipeId int, favourite bool, userId int) error {
-	return s.recipesRepo.SetRecipeLiked(recipeId, favourite, userId)
+// SetRecipeLikeStatus likes or unlikes recipe. Only recipes available to user can be liked,
+// so owner is rewarded only for likes of users who see recipe
+func (s *RecipeService) SetRecipeLikeStatus(recipeId int, favourite bool, userId int, linkToken *string) error {
+	recipe, err := s.recipesRepo.GetRecipe(recipeId)
+	if err != nil {
+		return err
+	}
+	if _, err = checkRecipeAccess(s.linksRepo, recipe.Id, recipe.OwnerId, recipe.Visibility, userId, linkToken); err != nil {
+		return err
+	}
+
+	if err = s.recipesRepo.SetRecipeLiked(recipeId, favourite, userId); err != nil {
+		return err
+	}
+
+	if favourite {
+		s.broccoinService.RewardLike(recipeId, recipe.OwnerId, userId)
+		s.achievementService.HandleEvent(recipe.OwnerId, entity.AchievementEventRecipeLiked)
+	}
+
+	return nil
+}
+
+func (s *RecipeService) MarkRecipeCooked(recipeId, userId int, linkToken *string) error {
+	recipe, err := s.recipesRepo.GetRecipe(recipeId)
+	if err != nil {
+		return err
+	}
+
+	if _, err := checkRecipeAccess(s.linksRepo, recipe.Id, recipe.OwnerId, recipe.Visibility, userId, linkToken); err != nil {
+		return err
+	}
+
+	return s.achievementService.AddRecipeCooking(recipeId, userId)
+}
+
+// checkRecipeAccess allows private recipes only for owner, for users with active share link
+// and for users who saved recipe by link that is still active. Returns used link ID
+func checkRecipeAccess(linksRepo repository.RecipeLink, recipeId, ownerId int, visibility string, userId int, linkToken *string) (*int, error) {
+	if strings.ToLower(visibility) != entity.VisibilityPrivate || ownerId == userId {
+		return nil, nil
+	}
+
+	if linkToken != nil {
+		link, err := linksRepo.GetActiveRecipeLink(*linkToken)
+		if err == nil && link.RecipeId == recipeId {
+			return &link.Id, nil
+		}
+	}
+
+	if linksRepo.IsRecipeSavedByActiveLink(recipeId, userId) {
+		return nil, nil
+	}
+
+	return nil, failure.AccessDenied
+}
+
+func getFeedQuery(query entity.RecipesQuery) entity.RecipesQuery {
+	query.Saved = false
+	query.Followed = true
+	return query
+}
+
+func (s *RecipeService) getExcludedAllergens(userId int) []string {
+	allergens, err := s.profileRepo.GetExcludedAllergens(userId)
+	if err != nil {
+		return []string{}
+	}
+	return allergens
 }
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+)
+
+const linkTokenBytes = 24
+
+type RecipeLinkService struct {
+	linksRepo   repository.RecipeLink
+	recipesRepo repository.Recipe
+}
+
+func NewRecipeLinkService(linksRepo repository.RecipeLink, recipesRepo repository.Recipe) *RecipeLinkService {
+	return &RecipeLinkService{
+		linksRepo:   linksRepo,
+		recipesRepo: recipesRepo,
+	}
+}
+
+func (s *RecipeLinkService) CreateRecipeLink(recipeId int, link entity.RecipeLinkInput, userId int) (entity.RecipeLink, error) {
+	if err := s.checkRecipeOwner(recipeId, userId); err != nil {
+		return entity.RecipeLink{}, err
+	}
+
+	token, err := generateLinkToken()
+	if err != nil {
+		return entity.RecipeLink{}, failure.Unknown
+	}
+
+	return s.linksRepo.CreateRecipeLink(recipeId, token, link.ExpiresAt)
+}
+
+func (s *RecipeLinkService) GetRecipeLinks(recipeId, userId int) ([]entity.RecipeLink, error) {
+	if err := s.checkRecipeOwner(recipeId, userId); err != nil {
+		return []entity.RecipeLink{}, err
+	}
+
+	return s.linksRepo.GetRecipeLinks(recipeId)
+}
+
+func (s *RecipeLinkService) DeleteRecipeLink(recipeId, linkId, userId int) error {
+	if err := s.checkRecipeOwner(recipeId, userId); err != nil {
+		return err
+	}
+
+	return s.linksRepo.DeleteRecipeLink(linkId, recipeId)
+}
+
+func (s *RecipeLinkService) checkRecipeOwner(recipeId, userId int) error {
+	ownerId, err := s.recipesRepo.GetRecipeOwnerId(recipeId)
+	if err != nil {
+		return err
+	}
+	if ownerId != userId {
+		return failure.NotOwner
+	}
+	return nil
+}
+
+func generateLinkToken() (string, error) {
+	b := make([]byte, linkTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -1,37 +1,95 @@
 package service
 
 import (
+	"context"
 	"github.com/mephistolie/chefbook-server/internal/entity"
 	"github.com/mephistolie/chefbook-server/internal/entity/failure"
 	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+	"github.com/mephistolie/chefbook-server/pkg/logger"
+	"reflect"
 )
 
 type RecipeOwnershipService struct {
-	recipeRepo    repository.Recipe
-	ownershipRepo repository.RecipeOwnership
+	recipeRepo         repository.Recipe
+	ownershipRepo      repository.RecipeOwnership
+	nutritionService   *NutritionService
+	picturesService    *RecipePicturesService
+	entitlementService *EntitlementService
+	achievementService *AchievementService
 }
 
-func NewRecipeOwnershipService(recipeRepo repository.Recipe, ownershipRepo repository.RecipeOwnership) *RecipeOwnershipService {
+func NewRecipeOwnershipService(recipeRepo repository.Recipe, ownershipRepo repository.RecipeOwnership,
+	nutritionService *NutritionService, picturesService *RecipePicturesService, entitlementService *EntitlementService,
+	achievementService *AchievementService) *RecipeOwnershipService {
 	return &RecipeOwnershipService{
-		recipeRepo:    recipeRepo,
-		ownershipRepo: ownershipRepo,
+		recipeRepo:         recipeRepo,
+		ownershipRepo:      ownershipRepo,
+		nutritionService:   nutritionService,
+		picturesService:    picturesService,
+		entitlementService: entitlementService,
+		achievementService: achievementService,
 	}
 }
 
 func (s *RecipeOwnershipService) CreateRecipe(recipe entity.RecipeInput, userId int) (int, error) {
-	return s.ownershipRepo.CreateRecipe(recipe, userId)
+	if recipe.IsEncrypted {
+		if err := s.entitlementService.CheckEncryption(userId); err != nil {
+			return 0, err
+		}
+	}
+	recipe.Allergens = detectRecipeAllergens(recipe)
+	s.nutritionService.FillMissingNutrition(&recipe)
+	s.picturesService.SetPictureKeys(&recipe)
+	if err := s.picturesService.SetCookingPictureIds(nil, recipe.Cooking); err != nil {
+		return 0, err
+	}
+
+	recipeId, err := s.ownershipRepo.CreateRecipe(recipe, userId)
+	if err != nil {
+		return 0, err
+	}
+	if isPublicRecipe(recipe.Visibility) {
+		s.achievementService.HandleEvent(userId, entity.AchievementEventRecipePublished)
+	}
+
+	return recipeId, nil
 }
 
-func (s *RecipeOwnershipService) UpdateRecipe(recipe entity.RecipeInput, recipeId, userId int) error {
-	ownerId, err := s.recipeRepo.GetRecipeOwnerId(recipeId)
+func (s *RecipeOwnershipService) UpdateRecipe(ctx context.Context, recipe entity.RecipeInput, recipeId, userId int) error {
+	previousRecipe, err := s.recipeRepo.GetRecipe(recipeId)
 	if err != nil {
 		return err
 	}
-	if ownerId != userId {
+	if previousRecipe.OwnerId != userId {
 		return failure.NotOwner
 	}
+	if recipe.IsEncrypted && !previousRecipe.IsEncrypted {
+		if err = s.entitlementService.CheckEncryption(userId); err != nil {
+			return err
+		}
+	}
+
+	recipe.Allergens = detectRecipeAllergens(recipe)
+	s.nutritionService.FillMissingNutrition(&recipe)
+	s.picturesService.SetPictureKeys(&recipe)
+	if err = s.picturesService.SetCookingPictureIds(&recipeId, recipe.Cooking); err != nil {
+		return err
+	}
+	if err = s.ownershipRepo.UpdateRecipe(recipeId, recipe); err != nil {
+		return err
+	}
+	if err = s.picturesService.SyncRecipePreview(recipeId, recipe.Preview); err != nil {
+		return err
+	}
+
+	if isPublicRecipe(previousRecipe.Visibility) != isPublicRecipe(recipe.Visibility) {
+		if isPublicRecipe(recipe.Visibility) {
+			s.achievementService.HandleEvent(userId, entity.AchievementEventRecipePublished)
+		}
+		return s.picturesService.SetRecipePicturesVisibility(ctx, recipeId, recipe.Visibility)
+	}
 
-	return s.ownershipRepo.UpdateRecipe(recipeId, recipe)
+	return nil
 }
 
 func (s *RecipeOwnershipService) DeleteRecipe(recipeId, userId int) error {
@@ -45,3 +103,39 @@ func (s *RecipeOwnershipService) DeleteRecipe(recipeId, userId int) error {
 
 	return s.ownershipRepo.DeleteRecipe(recipeId)
 }
+
+// MigratePictureLinks replaces absolute links to storage in recipe previews and cooking pictures with object keys
+func (s *RecipeOwnershipService) MigratePictureLinks() (int, error) {
+	recipeIds, err := s.recipeRepo.GetRecipeIdsWithPictureLinks()
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for _, recipeId := range recipeIds {
+		recipe, err := s.recipeRepo.GetRecipe(recipeId)
+		if err != nil {
+			logger.Errorf("unable to migrate pictures links of recipe %d: %s", recipeId, err.Error())
+			continue
+		}
+
+		previousPreview, previousCooking := recipe.Preview, copyCooking(recipe.Cooking)
+		preview, cooking := s.picturesService.getPictureKeys(recipe.Preview, recipe.Cooking)
+		if reflect.DeepEqual(previousPreview, preview) && reflect.DeepEqual(previousCooking, cooking) {
+			continue
+		}
+
+		if err = s.ownershipRepo.SetRecipePictures(recipeId, preview, cooking); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+
+	return migrated, nil
+}
+
+func copyCooking(cooking []entity.CookingItem) []entity.CookingItem {
+	copied := make([]entity.CookingItem, len(cooking))
+	copy(copied, cooking)
+	return copied
+}
@@ -2,62 +2,282 @@ package service
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"github.com/mephistolie/chefbook-server/internal/entity"
 	"github.com/mephistolie/chefbook-server/internal/entity/failure"
 	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+	"github.com/mephistolie/chefbook-server/pkg/imaging"
+	"github.com/mephistolie/chefbook-server/pkg/logger"
+	"io"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type RecipePicturesService struct {
-	recipesRepo            repository.Recipe
-	filesRepo              repository.File
+	recipesRepo    repository.Recipe
+	ownershipRepo  repository.RecipeOwnership
+	picturesRepo   repository.RecipePicture
+	linksRepo      repository.RecipeLink
+	filesRepo      repository.File
+	quotaService   *QuotaService
+	imageProcessor imaging.Processor
+	pictureLinkTTL time.Duration
+	pictureMaxSize int64
 }
 
-func NewRecipePicturesService(recipesRepo repository.Recipe, filesRepo repository.File) *RecipePicturesService {
+func NewRecipePicturesService(recipesRepo repository.Recipe, ownershipRepo repository.RecipeOwnership, picturesRepo repository.RecipePicture,
+	linksRepo repository.RecipeLink, filesRepo repository.File, quotaService *QuotaService, imageProcessor imaging.Processor,
+	pictureLinkTTL time.Duration, pictureMaxSize int64) *RecipePicturesService {
 	return &RecipePicturesService{
-		recipesRepo:            recipesRepo,
-		filesRepo:              filesRepo,
+		recipesRepo:    recipesRepo,
+		ownershipRepo:  ownershipRepo,
+		picturesRepo:   picturesRepo,
+		linksRepo:      linksRepo,
+		filesRepo:      filesRepo,
+		quotaService:   quotaService,
+		imageProcessor: imageProcessor,
+		pictureLinkTTL: pictureLinkTTL,
+		pictureMaxSize: pictureMaxSize,
 	}
 }
 
-func (s *RecipePicturesService) GetRecipePictures(ctx context.Context, recipeId int, userId int) ([]string, error) {
-	ownerId, err := s.recipesRepo.GetRecipeOwnerId(recipeId)
+// GetRecipePictures returns recipe gallery in its order with links to pictures and their thumbnails
+func (s *RecipePicturesService) GetRecipePictures(ctx context.Context, recipeId int, userId int, linkToken *string) ([]entity.RecipePicture, error) {
+	recipe, err := s.recipesRepo.GetRecipe(recipeId)
 	if err != nil {
-		return []string{}, err
+		return []entity.RecipePicture{}, err
 	}
-	if ownerId != userId {
-		return []string{}, failure.NotOwner
+	if _, err := checkRecipeAccess(s.linksRepo, recipe.Id, recipe.OwnerId, recipe.Visibility, userId, linkToken); err != nil {
+		return []entity.RecipePicture{}, err
 	}
 
-	pictures := s.filesRepo.GetRecipePictures(ctx, recipeId)
-	if pictures == nil {
-		pictures = []string{}
+	pictures, err := s.picturesRepo.GetRecipePictures(recipeId)
+	if err != nil {
+		return []entity.RecipePicture{}, err
+	}
+	for i := range pictures {
+		s.setPictureLinks(ctx, recipe.Visibility, &pictures[i])
 	}
 
 	return pictures, nil
 }
 
-func (s *RecipePicturesService) UploadRecipePicture(ctx context.Context, recipeId, userId int, file entity.MultipartFile) (string, error) {
+// GetRecipePicture returns link to recipe picture, which is signed and expiring for non-public recipes
+func (s *RecipePicturesService) GetRecipePicture(ctx context.Context, recipeId int, userId int, pictureId int, linkToken *string) (string, error) {
 	recipe, err := s.recipesRepo.GetRecipe(recipeId)
 	if err != nil {
 		return "", err
 	}
+	if _, err := checkRecipeAccess(s.linksRepo, recipe.Id, recipe.OwnerId, recipe.Visibility, userId, linkToken); err != nil {
+		return "", err
+	}
+
+	picture, err := s.picturesRepo.GetRecipePicture(pictureId, recipeId)
+	if err != nil {
+		return "", err
+	}
+
+	return s.getPictureLink(ctx, recipeId, recipe.Visibility, picture.Link), nil
+}
+
+// UploadRecipePicture stores picture and appends it to the end of recipe gallery
+func (s *RecipePicturesService) UploadRecipePicture(ctx context.Context, recipeId, userId int, file entity.MultipartFile) (entity.RecipePicture, error) {
+	recipe, err := s.recipesRepo.GetRecipe(recipeId)
+	if err != nil {
+		return entity.RecipePicture{}, err
+	}
+	if !recipe.IsEncrypted && file.IsUndecodableImage() {
+		return entity.RecipePicture{}, failure.UnsupportedImage
+	}
 	if !recipe.IsEncrypted && !file.IsImage() {
-		return "", failure.UnsupportedFileType
+		return entity.RecipePicture{}, failure.UnsupportedFileType
 	}
 	if recipe.OwnerId != userId {
-		return "", failure.NotOwner
+		return entity.RecipePicture{}, failure.NotOwner
+	}
+
+	objectKey, err := s.storeRecipePicture(ctx, recipe, file, nil)
+	if err != nil {
+		return entity.RecipePicture{}, err
+	}
+	return s.addRecipePicture(ctx, recipe, objectKey)
+}
+
+// GetRecipePictureUploadLink returns presigned link for direct picture upload to storage, which must be confirmed after upload.
+// Upload is counted in storage usage until it's confirmed or collected as garbage
+func (s *RecipePicturesService) GetRecipePictureUploadLink(ctx context.Context, recipeId, userId int, input entity.UploadInput) (entity.UploadLink, error) {
+	recipe, err := s.recipesRepo.GetRecipe(recipeId)
+	if err != nil {
+		return entity.UploadLink{}, err
+	}
+	if recipe.OwnerId != userId {
+		return entity.UploadLink{}, failure.NotOwner
+	}
+	if input.Size <= 0 || input.Size > s.pictureMaxSize {
+		return entity.UploadLink{}, failure.InvalidFileSize
+	}
+	file := entity.MultipartFile{ContentType: input.ContentType}
+	if !recipe.IsEncrypted && file.IsUndecodableImage() {
+		return entity.UploadLink{}, failure.UnsupportedImage
+	}
+	if !recipe.IsEncrypted && !file.IsImage() {
+		return entity.UploadLink{}, failure.UnsupportedFileType
+	}
+
+	uploadId := uuid.NewString()
+	uploadKey := getRecipeUploadKey(recipeId, uploadId)
+	if err = s.quotaService.ReserveFile(userId, &recipeId, uploadKey, input.Size, nil); err != nil {
+		return entity.UploadLink{}, err
+	}
+
+	link, err := s.filesRepo.GetRecipePictureUploadLink(ctx, recipeId, uploadId, input, s.pictureLinkTTL)
+	if err != nil {
+		s.quotaService.RemoveFiles(uploadKey)
+		return entity.UploadLink{}, err
+	}
+	return link, nil
+}
+
+// ConfirmRecipePictureUpload registers picture uploaded by presigned link. Pictures of non-encrypted recipes
+// are processed like usual uploads, encrypted ones are moved inside storage
+func (s *RecipePicturesService) ConfirmRecipePictureUpload(ctx context.Context, recipeId, userId int, uploadId string) (entity.RecipePicture, error) {
+	recipe, err := s.recipesRepo.GetRecipe(recipeId)
+	if err != nil {
+		return entity.RecipePicture{}, err
+	}
+	if recipe.OwnerId != userId {
+		return entity.RecipePicture{}, failure.NotOwner
+	}
+
+	file, err := s.filesRepo.GetRecipePictureUpload(ctx, recipeId, uploadId)
+	if err != nil {
+		return entity.RecipePicture{}, err
+	}
+	if closer, ok := file.Content.(io.Closer); ok {
+		defer closer.Close()
+	}
+	uploadKey := getRecipeUploadKey(recipeId, uploadId)
+	defer func() {
+		_ = s.filesRepo.DeleteRecipePictureUpload(ctx, recipeId, uploadId)
+		s.quotaService.RemoveFiles(uploadKey)
+	}()
+
+	if file.Size > s.pictureMaxSize {
+		return entity.RecipePicture{}, failure.InvalidFileSize
 	}
 
-	url, err := s.filesRepo.UploadRecipePicture(ctx, recipeId, file)
+	var objectKey string
+	if recipe.IsEncrypted {
+		objectKey = getRecipePictureKey(recipeId, uploadId)
+		if err = s.quotaService.ReserveFile(userId, &recipeId, objectKey, file.Size, &uploadKey); err != nil {
+			return entity.RecipePicture{}, err
+		}
+		if _, err = s.filesRepo.CommitRecipePictureUpload(ctx, recipeId, uploadId, isPublicRecipe(recipe.Visibility)); err != nil {
+			s.quotaService.RemoveFiles(objectKey)
+		}
+	} else {
+		objectKey, err = s.storeRecipePicture(ctx, recipe, file, &uploadKey)
+	}
 	if err != nil {
-		_ = s.filesRepo.DeleteFile(ctx, url)
+		return entity.RecipePicture{}, err
+	}
+
+	return s.addRecipePicture(ctx, recipe, objectKey)
+}
+
+// storeRecipePicture uploads picture if it fits into quota of recipe owner and returns its object key.
+// Picture with thumbnails is counted in usage in place of replaced file, e.g. pending upload
+func (s *RecipePicturesService) storeRecipePicture(ctx context.Context, recipe entity.Recipe, file entity.MultipartFile,
+	replacedKey *string) (string, error) {
+	var err error
+	var thumbnails map[int]entity.MultipartFile
+	if !recipe.IsEncrypted {
+		if file, thumbnails, err = processImage(s.imageProcessor, file, s.pictureMaxSize); err != nil {
+			return "", err
+		}
+	}
+
+	objectKey := getRecipePictureKey(recipe.Id, file.Name)
+	if err = s.quotaService.ReserveFile(recipe.OwnerId, &recipe.Id, objectKey, getPictureSize(file, thumbnails), replacedKey); err != nil {
+		return "", err
+	}
+
+	isPublic := isPublicRecipe(recipe.Visibility)
+	if _, err = s.filesRepo.UploadRecipePicture(ctx, recipe.Id, file, isPublic); err != nil {
+		s.quotaService.RemoveFiles(objectKey)
+		return "", err
+	}
+	if err = uploadThumbnails(ctx, s.filesRepo, objectKey, thumbnails, isPublic); err != nil {
+		_ = s.filesRepo.DeleteRecipePicture(ctx, recipe.Id, file.Name)
+		s.quotaService.RemoveFiles(objectKey)
 		return "", err
 	}
 
-	return url, nil
+	return objectKey, nil
+}
+
+// UpdateRecipePicture sets picture caption and makes picture recipe preview or resets preview if it was preview one
+func (s *RecipePicturesService) UpdateRecipePicture(recipeId, userId, pictureId int, input entity.RecipePictureInput) error {
+	ownerId, err := s.recipesRepo.GetRecipeOwnerId(recipeId)
+	if err != nil {
+		return err
+	}
+	if ownerId != userId {
+		return failure.NotOwner
+	}
+
+	picture, err := s.picturesRepo.GetRecipePicture(pictureId, recipeId)
+	if err != nil {
+		return err
+	}
+	if err = s.picturesRepo.SetRecipePictureCaption(pictureId, recipeId, input.Caption); err != nil {
+		return err
+	}
+
+	if input.IsPreview {
+		return s.picturesRepo.SetRecipePreview(recipeId, &picture.Link)
+	} else if picture.IsPreview {
+		return s.picturesRepo.SetRecipePreview(recipeId, nil)
+	}
+
+	return nil
+}
+
+// SetRecipePicturesOrder reorders recipe gallery. Passed ids must contain every recipe picture exactly once
+func (s *RecipePicturesService) SetRecipePicturesOrder(recipeId, userId int, pictureIds []int) error {
+	ownerId, err := s.recipesRepo.GetRecipeOwnerId(recipeId)
+	if err != nil {
+		return err
+	}
+	if ownerId != userId {
+		return failure.NotOwner
+	}
+
+	pictures, err := s.picturesRepo.GetRecipePictures(recipeId)
+	if err != nil {
+		return err
+	}
+	if len(pictures) != len(pictureIds) {
+		return failure.InvalidPictureOrder
+	}
+	orderedIds := make(map[int]bool)
+	for _, pictureId := range pictureIds {
+		orderedIds[pictureId] = true
+	}
+	for _, picture := range pictures {
+		if !orderedIds[picture.Id] {
+			return failure.InvalidPictureOrder
+		}
+	}
+
+	return s.picturesRepo.SetRecipePicturesOrder(recipeId, pictureIds)
 }
 
-func (s *RecipePicturesService) DeleteRecipePicture(ctx context.Context, recipeId, userId int, pictureName string) error {
+// DeleteRecipePicture deletes picture from recipe gallery and storage
+func (s *RecipePicturesService) DeleteRecipePicture(ctx context.Context, recipeId, userId, pictureId int) error {
 	ownerId, err := s.recipesRepo.GetRecipeOwnerId(recipeId)
 	if err != nil {
 		return err
@@ -66,10 +286,305 @@ func (s *RecipePicturesService) DeleteRecipePicture(ctx context.Context, recipeI
 		return failure.NotOwner
 	}
 
-	err = s.filesRepo.DeleteRecipePicture(ctx, recipeId, pictureName)
+	picture, err := s.picturesRepo.GetRecipePicture(pictureId, recipeId)
+	if err != nil {
+		return err
+	}
+	if err = s.picturesRepo.DeleteRecipePicture(pictureId, recipeId); err != nil {
+		return err
+	}
+
+	if err = s.filesRepo.DeleteRecipePicture(ctx, recipeId, path.Base(picture.Link)); err != nil {
+		logger.Errorf("unable to delete picture %s of recipe %d: %s", picture.Link, recipeId, err.Error())
+	}
+	s.quotaService.RemoveFiles(picture.Link)
+
+	return nil
+}
+
+// SetRecipePicturesVisibility makes recipe pictures public or private after recipe visibility change
+func (s *RecipePicturesService) SetRecipePicturesVisibility(ctx context.Context, recipeId int, visibility string) error {
+	return s.filesRepo.SetRecipePicturesAccess(ctx, recipeId, isPublicRecipe(visibility))
+}
+
+// GetPreviewLinks returns links to recipe preview and its thumbnails. Links of non-public recipes are signed
+func (s *RecipePicturesService) GetPreviewLinks(ctx context.Context, recipeId int, visibility string, preview *string) (*string, map[int]string) {
+	if preview == nil {
+		return nil, nil
+	}
+
+	objectKey := s.filesRepo.GetObjectKey(*preview)
+	thumbnails := getThumbnailKeys(s.filesRepo, s.imageProcessor, objectKey)
+	for size, thumbnail := range thumbnails {
+		thumbnails[size] = s.getPictureLink(ctx, recipeId, visibility, thumbnail)
+	}
+	link := s.getPictureLink(ctx, recipeId, visibility, objectKey)
+
+	return &link, thumbnails
+}
+
+// GetCookingLinks returns cooking steps with links to their pictures. Pictures referenced by ids go first,
+// links and keys stored before galleries follow them. Links of non-public recipes are signed
+func (s *RecipePicturesService) GetCookingLinks(ctx context.Context, recipeId int, visibility string, cooking []entity.CookingItem) []entity.CookingItem {
+	var pictures map[int]string
+	for i := range cooking {
+		if cooking[i].Pictures == nil && cooking[i].PictureIds == nil {
+			continue
+		}
+		if pictures == nil {
+			pictures = s.getGalleryKeys(recipeId)
+		}
+
+		var links []string
+		if cooking[i].PictureIds != nil {
+			for _, pictureId := range *cooking[i].PictureIds {
+				if objectKey, ok := pictures[pictureId]; ok {
+					links = append(links, s.getPictureLink(ctx, recipeId, visibility, objectKey))
+				}
+			}
+		}
+		if cooking[i].Pictures != nil {
+			for _, picture := range *cooking[i].Pictures {
+				links = append(links, s.getPictureLink(ctx, recipeId, visibility, s.filesRepo.GetObjectKey(picture)))
+			}
+		}
+		cooking[i].Pictures = nil
+		if len(links) > 0 {
+			cooking[i].Pictures = &links
+		}
+	}
+	return cooking
+}
+
+// SetPictureKeys replaces links to storage in recipe preview and cooking pictures with object keys,
+// so stored recipes don't depend on storage host
+func (s *RecipePicturesService) SetPictureKeys(recipe *entity.RecipeInput) {
+	recipe.Preview, recipe.Cooking = s.getPictureKeys(recipe.Preview, recipe.Cooking)
+}
+
+// SetCookingPictureIds replaces links to gallery pictures in cooking steps with picture ids and checks
+// that steps reference only pictures of recipe gallery. New recipe has no gallery yet, so its steps can't have pictures
+func (s *RecipePicturesService) SetCookingPictureIds(recipeId *int, cooking []entity.CookingItem) error {
+	pictures := []entity.RecipePicture{}
+	if recipeId != nil {
+		var err error
+		if pictures, err = s.picturesRepo.GetRecipePictures(*recipeId); err != nil {
+			return err
+		}
+	}
+
+	return setCookingPictureIds(pictures, cooking, false)
+}
+
+// SyncRecipePreview marks gallery picture used as recipe preview
+func (s *RecipePicturesService) SyncRecipePreview(recipeId int, preview *string) error {
+	return s.picturesRepo.SetRecipePreview(recipeId, preview)
+}
+
+// ImportPictures adds pictures uploaded before galleries to galleries of their recipes.
+// Cooking steps referencing imported pictures by links are switched to picture ids
+func (s *RecipePicturesService) ImportPictures(ctx context.Context) (int, error) {
+	files, err := s.filesRepo.GetFiles(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	recipePictures := make(map[int][]string)
+	var recipeIds []int
+	for _, file := range files {
+		parts := strings.SplitN(file.Key, "/", 4)
+		if len(parts) < 4 || parts[0] != storageRecipesDir || parts[2] != storageImagesDir || strings.Contains(parts[3], "/") {
+			continue
+		}
+		recipeId, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		if _, ok := recipePictures[recipeId]; !ok {
+			recipeIds = append(recipeIds, recipeId)
+		}
+		recipePictures[recipeId] = append(recipePictures[recipeId], file.Key)
+	}
+
+	imported := 0
+	for _, recipeId := range recipeIds {
+		if err := s.importRecipePictures(recipeId, recipePictures[recipeId]); err != nil {
+			logger.Errorf("unable to import pictures of recipe %d: %s", recipeId, err.Error())
+			continue
+		}
+		imported++
+	}
+
+	return imported, nil
+}
+
+func (s *RecipePicturesService) importRecipePictures(recipeId int, objectKeys []string) error {
+	recipe, err := s.recipesRepo.GetRecipe(recipeId)
+	if err != nil {
+		return err
+	}
+
+	for _, objectKey := range objectKeys {
+		if _, err = s.picturesRepo.AddRecipePicture(recipeId, objectKey); err != nil {
+			return err
+		}
+	}
+
+	preview, cooking := s.getPictureKeys(recipe.Preview, copyCooking(recipe.Cooking))
+	if preview != nil {
+		if err = s.picturesRepo.SetRecipePreview(recipeId, preview); err != nil {
+			return err
+		}
+	}
+
+	pictures, err := s.picturesRepo.GetRecipePictures(recipeId)
 	if err != nil {
 		return err
 	}
+	if err = setCookingPictureIds(pictures, cooking, true); err != nil {
+		return err
+	}
+	if reflect.DeepEqual(recipe.Cooking, cooking) {
+		return nil
+	}
+	return s.ownershipRepo.SetRecipePictures(recipeId, preview, cooking)
+}
+
+// MigratePictures revokes public access to pictures of non-public recipes uploaded before access control
+func (s *RecipePicturesService) MigratePictures(ctx context.Context) (int, error) {
+	recipeIds, err := s.recipesRepo.GetNonPublicRecipeIds()
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for _, recipeId := range recipeIds {
+		if err := s.filesRepo.SetRecipePicturesAccess(ctx, recipeId, false); err != nil {
+			logger.Errorf("unable to migrate pictures of recipe %d: %s", recipeId, err.Error())
+			continue
+		}
+		migrated++
+	}
+
+	return migrated, nil
+}
+
+func (s *RecipePicturesService) getPictureKeys(preview *string, cooking []entity.CookingItem) (*string, []entity.CookingItem) {
+	if preview != nil {
+		objectKey := s.filesRepo.GetObjectKey(*preview)
+		preview = &objectKey
+	}
+	for i := range cooking {
+		if cooking[i].Pictures == nil {
+			continue
+		}
+		pictures := make([]string, len(*cooking[i].Pictures))
+		for j, picture := range *cooking[i].Pictures {
+			pictures[j] = s.filesRepo.GetObjectKey(picture)
+		}
+		cooking[i].Pictures = &pictures
+	}
+	return preview, cooking
+}
+
+func (s *RecipePicturesService) addRecipePicture(ctx context.Context, recipe entity.Recipe, objectKey string) (entity.RecipePicture, error) {
+	picture, err := s.picturesRepo.AddRecipePicture(recipe.Id, objectKey)
+	if err != nil {
+		return entity.RecipePicture{}, err
+	}
+	s.setPictureLinks(ctx, recipe.Visibility, &picture)
+	return picture, nil
+}
+
+func (s *RecipePicturesService) setPictureLinks(ctx context.Context, visibility string, picture *entity.RecipePicture) {
+	objectKey := picture.Link
+	picture.Thumbnails = getThumbnailKeys(s.filesRepo, s.imageProcessor, objectKey)
+	for size, thumbnail := range picture.Thumbnails {
+		picture.Thumbnails[size] = s.getPictureLink(ctx, picture.RecipeId, visibility, thumbnail)
+	}
+	picture.Link = s.getPictureLink(ctx, picture.RecipeId, visibility, objectKey)
+}
+
+// getGalleryKeys returns object keys of recipe gallery pictures by their ids
+func (s *RecipePicturesService) getGalleryKeys(recipeId int) map[int]string {
+	keys := make(map[int]string)
+	pictures, err := s.picturesRepo.GetRecipePictures(recipeId)
+	if err != nil {
+		return keys
+	}
+	for _, picture := range pictures {
+		keys[picture.Id] = picture.Link
+	}
+	return keys
+}
+
+func (s *RecipePicturesService) getPictureLink(ctx context.Context, recipeId int, visibility, objectKey string) string {
+	if isPublicRecipe(visibility) {
+		return s.filesRepo.GetFileLink(objectKey)
+	}
+
+	link, err := s.filesRepo.GetRecipePictureLink(ctx, recipeId, objectKey, s.pictureLinkTTL)
+	if err != nil {
+		return s.filesRepo.GetFileLink(objectKey)
+	}
+	return link
+}
+
+// setCookingPictureIds moves gallery pictures referenced by object keys to picture ids of cooking steps.
+// Ids and keys of pictures missing in gallery are rejected. Legacy links, e.g. to other hosts,
+// are kept only by import of pictures uploaded before galleries
+func setCookingPictureIds(pictures []entity.RecipePicture, cooking []entity.CookingItem, keepLinks bool) error {
+	pictureIds := make(map[string]int)
+	galleryIds := make(map[int]bool)
+	for _, picture := range pictures {
+		pictureIds[picture.Link] = picture.Id
+		galleryIds[picture.Id] = true
+	}
+
+	for i := range cooking {
+		var ids []int
+		addedIds := make(map[int]bool)
+		if cooking[i].PictureIds != nil {
+			for _, pictureId := range *cooking[i].PictureIds {
+				if !galleryIds[pictureId] {
+					return failure.InvalidCookingPicture
+				}
+				if !addedIds[pictureId] {
+					ids = append(ids, pictureId)
+					addedIds[pictureId] = true
+				}
+			}
+		}
+
+		var links []string
+		if cooking[i].Pictures != nil {
+			for _, picture := range *cooking[i].Pictures {
+				if pictureId, ok := pictureIds[picture]; ok {
+					if !addedIds[pictureId] {
+						ids = append(ids, pictureId)
+						addedIds[pictureId] = true
+					}
+					continue
+				}
+				if !keepLinks {
+					return failure.InvalidCookingPicture
+				}
+				links = append(links, picture)
+			}
+		}
+
+		cooking[i].PictureIds, cooking[i].Pictures = nil, nil
+		if len(ids) > 0 {
+			cooking[i].PictureIds = &ids
+		}
+		if len(links) > 0 {
+			cooking[i].Pictures = &links
+		}
+	}
 
 	return nil
 }
+
+func isPublicRecipe(visibility string) bool {
+	return strings.ToLower(visibility) == entity.VisibilityPublic
+}
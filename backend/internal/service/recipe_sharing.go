@@ -4,17 +4,27 @@ import (
 	"github.com/mephistolie/chefbook-server/internal/entity"
 	"github.com/mephistolie/chefbook-server/internal/entity/failure"
 	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+	"github.com/mephistolie/chefbook-server/pkg/logger"
 )
 
 type RecipeSharingService struct {
 	recipesRepo        repository.Recipe
 	recipesSharingRepo repository.RecipeSharing
+	linksRepo          repository.RecipeLink
+	authRepo           repository.Auth
+	filesRepo          repository.File
+	mailService        MailService
 }
 
-func NewRecipeSharingService(recipesRepo repository.Recipe, recipesSharingRepo repository.RecipeSharing) *RecipeSharingService {
+func NewRecipeSharingService(recipesRepo repository.Recipe, recipesSharingRepo repository.RecipeSharing,
+	linksRepo repository.RecipeLink, authRepo repository.Auth, filesRepo repository.File, mailService MailService) *RecipeSharingService {
 	return &RecipeSharingService{
 		recipesRepo:        recipesRepo,
 		recipesSharingRepo: recipesSharingRepo,
+		linksRepo:          linksRepo,
+		authRepo:           authRepo,
+		filesRepo:          filesRepo,
+		mailService:        mailService,
 	}
 }
 
@@ -28,7 +38,9 @@ func (s *RecipeSharingService) GetUsersList(recipeId, userId int) ([]entity.Prof
 		return []entity.ProfileInfo{}, failure.NotOwner
 	}
 
-	return s.recipesSharingRepo.GetRecipeUserList(recipeId)
+	users, err := s.recipesSharingRepo.GetRecipeUserList(recipeId)
+	setProfilesAvatarLinks(s.filesRepo, users)
+	return users, err
 }
 
 func (s *RecipeSharingService) GetUserPublicKey(recipeId, userId, requesterId int) (string, error) {
@@ -57,8 +69,19 @@ func (s *RecipeSharingService) SetUserPublicKey(recipeId, userId int, userKey *s
 	return nil
 }
 
-func (s *RecipeSharingService) GetOwnerPrivateKeyForUser(recipeId, userId int) (string, error) {
-	return s.recipesSharingRepo.GetUserRecipeKey(recipeId, userId)
+// GetOwnerPrivateKeyForUser returns link to key set by owner manually if it exists,
+// otherwise key from approved key request
+func (s *RecipeSharingService) GetOwnerPrivateKeyForUser(recipeId, userId int) (entity.RecipeUserKey, error) {
+	if link, err := s.recipesSharingRepo.GetUserRecipeKey(recipeId, userId); err == nil {
+		return entity.RecipeUserKey{Link: &link}, nil
+	}
+
+	key, err := s.recipesSharingRepo.GetApprovedRecipeKey(recipeId, userId)
+	if err != nil {
+		return entity.RecipeUserKey{}, err
+	}
+
+	return entity.RecipeUserKey{ApprovedKey: &key}, nil
 }
 
 func (s *RecipeSharingService) SetOwnerPrivateKeyForUser(recipeId int, userId int, requesterId int, ownerKey *string) error {
@@ -83,3 +106,123 @@ func (s *RecipeSharingService) DeleteUserAccess(recipeId, userId, requesterId in
 	}
 	return s.recipesRepo.RemoveRecipeFromRecipeBook(recipeId, userId)
 }
+
+// RequestRecipeKey asks owner for key of encrypted recipe. Key of private recipe can be requested
+// only by users who have access to it, e.g. by share link
+func (s *RecipeSharingService) RequestRecipeKey(recipeId, userId int, userKey string, linkToken *string) (int, error) {
+	recipe, err := s.recipesRepo.GetRecipe(recipeId)
+	if err != nil {
+		return 0, err
+	}
+	if recipe.OwnerId == userId {
+		return 0, failure.AccessDenied
+	}
+	if _, err = checkRecipeAccess(s.linksRepo, recipe.Id, recipe.OwnerId, recipe.Visibility, userId, linkToken); err != nil {
+		return 0, err
+	}
+	if !recipe.IsEncrypted {
+		return 0, failure.RecipeNotEncrypted
+	}
+
+	return s.recipesSharingRepo.CreateKeyRequest(recipeId, userId, userKey)
+}
+
+func (s *RecipeSharingService) GetRecipeKeyRequests(recipeId, userId int, status *string) ([]entity.RecipeKeyRequest, error) {
+	if err := s.checkRecipeOwner(recipeId, userId); err != nil {
+		return []entity.RecipeKeyRequest{}, err
+	}
+
+	return s.recipesSharingRepo.GetRecipeKeyRequests(recipeId, status)
+}
+
+func (s *RecipeSharingService) GetUserKeyRequests(userId int, status *string) ([]entity.RecipeKeyRequest, error) {
+	return s.recipesSharingRepo.GetUserKeyRequests(userId, status)
+}
+
+func (s *RecipeSharingService) ApproveKeyRequest(recipeId, requestId, userId int, recipeKey string) error {
+	request, err := s.getOwnedRecipeKeyRequest(recipeId, requestId, userId)
+	if err != nil {
+		return err
+	}
+
+	if err := s.recipesSharingRepo.ApproveKeyRequest(requestId, recipeKey); err != nil {
+		return err
+	}
+	s.notifyKeyRequestResolved(request, true)
+
+	return nil
+}
+
+func (s *RecipeSharingService) RejectKeyRequest(recipeId, requestId, userId int) error {
+	request, err := s.getOwnedRecipeKeyRequest(recipeId, requestId, userId)
+	if err != nil {
+		return err
+	}
+
+	if err := s.recipesSharingRepo.SetKeyRequestStatus(requestId, entity.KeyRequestRejected); err != nil {
+		return err
+	}
+	s.notifyKeyRequestResolved(request, false)
+
+	return nil
+}
+
+func (s *RecipeSharingService) CancelKeyRequest(recipeId, requestId, userId int) error {
+	request, err := s.recipesSharingRepo.GetKeyRequest(requestId)
+	if err != nil {
+		return err
+	}
+	if request.RecipeId != recipeId {
+		return failure.KeyRequestNotFound
+	}
+	if request.UserId != userId {
+		return failure.AccessDenied
+	}
+
+	return s.recipesSharingRepo.SetKeyRequestStatus(requestId, entity.KeyRequestCancelled)
+}
+
+func (s *RecipeSharingService) getOwnedRecipeKeyRequest(recipeId, requestId, userId int) (entity.RecipeKeyRequest, error) {
+	if err := s.checkRecipeOwner(recipeId, userId); err != nil {
+		return entity.RecipeKeyRequest{}, err
+	}
+
+	request, err := s.recipesSharingRepo.GetKeyRequest(requestId)
+	if err != nil {
+		return entity.RecipeKeyRequest{}, err
+	}
+	if request.RecipeId != recipeId {
+		return entity.RecipeKeyRequest{}, failure.KeyRequestNotFound
+	}
+
+	return request, nil
+}
+
+func (s *RecipeSharingService) checkRecipeOwner(recipeId, userId int) error {
+	ownerId, err := s.recipesRepo.GetRecipeOwnerId(recipeId)
+	if err != nil {
+		return err
+	}
+	if ownerId != userId {
+		return failure.NotOwner
+	}
+	return nil
+}
+
+// notifyKeyRequestResolved emails requester about owner decision. Request state is already saved,
+// so requester still can see it in key requests list if email can't be sent
+func (s *RecipeSharingService) notifyKeyRequestResolved(request entity.RecipeKeyRequest, isApproved bool) {
+	requester, err := s.authRepo.GetUserById(request.UserId)
+	if err != nil {
+		return
+	}
+
+	err = s.mailService.SendKeyRequestResolvedEmail(entity.KeyRequestResolvedEmailInput{
+		Email:      requester.Email,
+		RecipeName: request.RecipeName,
+		IsApproved: isApproved,
+	})
+	if err != nil {
+		logger.Errorf("failed to notify user %d about key request %d: %s", request.UserId, request.Id, err.Error())
+	}
+}
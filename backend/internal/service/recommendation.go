@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+	"github.com/mephistolie/chefbook-server/pkg/logger"
+)
+
+type RecommendationService struct {
+	repo                repository.Recommendation
+	picturesService     *RecipePicturesService
+	similarRecipesLimit int
+}
+
+func NewRecommendationService(repo repository.Recommendation, picturesService *RecipePicturesService, similarRecipesLimit int) *RecommendationService {
+	return &RecommendationService{
+		repo:                repo,
+		picturesService:     picturesService,
+		similarRecipesLimit: similarRecipesLimit,
+	}
+}
+
+func (s *RecommendationService) UpdateRecommendationData() error {
+	if err := s.repo.UpdateSimilarities(s.similarRecipesLimit); err != nil {
+		return err
+	}
+	return s.repo.UpdateIngredientTokens()
+}
+
+func (s *RecommendationService) GetRecommendedRecipes(languages *[]string, count, userId int) ([]entity.RecipeInfo, error) {
+	recipes, err := s.repo.GetCollaborativeRecommendations(userId, languages, count)
+	if err != nil {
+		return []entity.RecipeInfo{}, err
+	}
+
+	if len(recipes) >= count {
+		return s.setPreviewLinks(recipes), nil
+	}
+
+	contentRecipes, err := s.repo.GetContentRecommendations(userId, languages, count)
+	if err != nil {
+		logger.Errorf("unable to get content recommendations for user %d: %s", userId, err.Error())
+		return s.setPreviewLinks(recipes), nil
+	}
+
+	recommendedIds := make(map[int]bool)
+	for _, recipe := range recipes {
+		recommendedIds[recipe.Id] = true
+	}
+	for _, recipe := range contentRecipes {
+		if len(recipes) >= count {
+			break
+		}
+		if !recommendedIds[recipe.Id] {
+			recipes = append(recipes, recipe)
+		}
+	}
+
+	return s.setPreviewLinks(recipes), nil
+}
+
+func (s *RecommendationService) setPreviewLinks(recipes []entity.RecipeInfo) []entity.RecipeInfo {
+	for i := range recipes {
+		recipes[i].Preview, recipes[i].PreviewThumbnails = s.picturesService.GetPreviewLinks(context.Background(), recipes[i].Id,
+			recipes[i].Visibility, recipes[i].Preview)
+	}
+	return recipes
+}
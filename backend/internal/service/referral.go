@@ -0,0 +1,129 @@
+package service
+
+import (
+	"crypto/rand"
+	"fmt"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+	"github.com/mephistolie/chefbook-server/pkg/logger"
+	"strings"
+)
+
+const (
+	referralCodeLength      = 8
+	referralCodeAlphabet    = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	referralCodeGenerations = 5
+)
+
+type ReferralService struct {
+	referralsRepo   repository.Referral
+	broccoinService *BroccoinService
+	params          entity.ReferralParams
+}
+
+func NewReferralService(referralsRepo repository.Referral, broccoinService *BroccoinService, params entity.ReferralParams) *ReferralService {
+	return &ReferralService{
+		referralsRepo:   referralsRepo,
+		broccoinService: broccoinService,
+		params:          params,
+	}
+}
+
+func (s *ReferralService) GetReferrals(userId int) (entity.Referrals, error) {
+	code, err := s.GetReferralCode(userId)
+	if err != nil {
+		return entity.Referrals{}, err
+	}
+
+	referrals, err := s.referralsRepo.GetReferrals(userId)
+	if err != nil {
+		return entity.Referrals{}, err
+	}
+
+	return entity.Referrals{
+		Code:      code,
+		Referrals: referrals,
+	}, nil
+}
+
+// GetReferralCode returns user referral code. Code is generated on first request
+func (s *ReferralService) GetReferralCode(userId int) (string, error) {
+	code, err := s.referralsRepo.GetReferralCode(userId)
+	if err != nil {
+		return "", err
+	}
+	if code != nil {
+		return *code, nil
+	}
+
+	for i := 0; i < referralCodeGenerations; i++ {
+		generatedCode, err := generateReferralCode()
+		if err != nil {
+			return "", failure.Unknown
+		}
+		actualCode, err := s.referralsRepo.SetReferralCode(userId, generatedCode)
+		if err != failure.ReferralCodeExists {
+			return actualCode, err
+		}
+	}
+
+	return "", failure.Unknown
+}
+
+// GetReferrerId returns id of referral code owner
+func (s *ReferralService) GetReferrerId(code string) (int, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != referralCodeLength {
+		return 0, failure.InvalidReferralCode
+	}
+	return s.referralsRepo.GetUserIdByReferralCode(code)
+}
+
+// AddInvitee links signed up user to referrer. Link is skipped if referrer has too many pending invitees
+func (s *ReferralService) AddInvitee(referrerId, inviteeId int) {
+	if referrerId == inviteeId {
+		return
+	}
+
+	if s.params.Limits.MaxPendingInvites > 0 {
+		pendingCount, err := s.referralsRepo.GetPendingReferralsCount(referrerId)
+		if err != nil {
+			return
+		}
+		if pendingCount >= s.params.Limits.MaxPendingInvites {
+			logger.Warnf("referrer %d reached pending invites limit; user %d isn't linked", referrerId, inviteeId)
+			return
+		}
+	}
+
+	_ = s.referralsRepo.AddReferral(referrerId, inviteeId)
+}
+
+// RewardActivation completes referral of activated user and rewards both referrer and invitee
+func (s *ReferralService) RewardActivation(inviteeId int) {
+	referral, err := s.referralsRepo.ActivateReferral(inviteeId, s.params.Limits, s.params.ReferrerReward.PremiumDays,
+		s.params.InviteeReward.PremiumDays)
+	if err != nil {
+		return
+	}
+	if referral.Status != entity.ReferralStatusRewarded {
+		logger.Warnf("referrer %d reached rewards limit; activation of user %d isn't rewarded", referral.ReferrerId, inviteeId)
+		return
+	}
+
+	reference := fmt.Sprintf("invitee:%d", inviteeId)
+	s.broccoinService.Credit(referral.ReferrerId, s.params.ReferrerReward.Broccoins, entity.BroccoinReasonReferral, &reference)
+	s.broccoinService.Credit(inviteeId, s.params.InviteeReward.Broccoins, entity.BroccoinReasonReferral, &reference)
+}
+
+func generateReferralCode() (string, error) {
+	b := make([]byte, referralCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = referralCodeAlphabet[int(b[i])%len(referralCodeAlphabet)]
+	}
+	return string(b), nil
+}
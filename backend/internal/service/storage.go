@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+	"github.com/mephistolie/chefbook-server/pkg/imaging"
+	"github.com/mephistolie/chefbook-server/pkg/logger"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// storage layout directories which content is owned by database rows
+const (
+	storageUsersDir   = "users"
+	storageRecipesDir = "recipes"
+	storageAvatarsDir = "avatars"
+	storageKeysDir    = "keys"
+	storageImagesDir  = "images"
+	storageUploadsDir = "uploads"
+)
+
+// getAvatarKey and other key getters follow storage layout shared by all drivers,
+// so quota of file can be reserved before its upload
+func getAvatarKey(userId int, name string) string {
+	return fmt.Sprintf("%s/%d/%s/%s", storageUsersDir, userId, storageAvatarsDir, name)
+}
+
+func getUserKeyFileKey(userId int, name string) string {
+	return fmt.Sprintf("%s/%d/%s/%s", storageUsersDir, userId, storageKeysDir, name)
+}
+
+func getRecipePictureKey(recipeId int, name string) string {
+	return fmt.Sprintf("%s/%d/%s/%s", storageRecipesDir, recipeId, storageImagesDir, name)
+}
+
+func getRecipeKeyFileKey(recipeId int, name string) string {
+	return fmt.Sprintf("%s/%d/%s/%s", storageRecipesDir, recipeId, storageKeysDir, name)
+}
+
+func getRecipeUploadKey(recipeId int, uploadId string) string {
+	return fmt.Sprintf("%s/%d/%s/%s", storageRecipesDir, recipeId, storageUploadsDir, uploadId)
+}
+
+type StorageService struct {
+	storageRepo    repository.Storage
+	filesRepo      repository.File
+	imageProcessor imaging.Processor
+	gracePeriod    time.Duration
+}
+
+func NewStorageService(storageRepo repository.Storage, filesRepo repository.File, imageProcessor imaging.Processor,
+	gracePeriod time.Duration) *StorageService {
+	return &StorageService{
+		storageRepo:    storageRepo,
+		filesRepo:      filesRepo,
+		imageProcessor: imageProcessor,
+		gracePeriod:    gracePeriod,
+	}
+}
+
+// CollectGarbage deletes files which aren't referenced by database and stops counting them in storage usage.
+// Files younger than grace period are skipped, because they may be uploaded but not saved to database yet.
+// Reservations of files missing in storage, e.g. of expired direct uploads, are released after grace period too.
+// Dry run only reports files which would be deleted
+func (s *StorageService) CollectGarbage(ctx context.Context, dryRun bool) (entity.GarbageCollectionReport, error) {
+	report := entity.GarbageCollectionReport{
+		DryRun:        dryRun,
+		OrphanedFiles: []string{},
+	}
+
+	files, err := s.filesRepo.GetFiles(ctx)
+	if err != nil {
+		return report, err
+	}
+	references, err := s.storageRepo.GetStorageReferences()
+	if err != nil {
+		return report, err
+	}
+
+	referencedFiles := make(map[string]bool)
+	for _, file := range references.Files {
+		objectKey := s.filesRepo.GetObjectKey(file)
+		referencedFiles[objectKey] = true
+		for _, thumbnail := range getThumbnailKeys(s.filesRepo, s.imageProcessor, objectKey) {
+			referencedFiles[thumbnail] = true
+		}
+	}
+
+	storedFiles := make(map[string]bool)
+	for _, file := range files {
+		storedFiles[file.Key] = true
+	}
+
+	var releasedFiles []string
+	threshold := time.Now().Add(-s.gracePeriod)
+	report.ScannedFiles = len(files)
+	for _, file := range files {
+		if file.LastModified.After(threshold) || referencedFiles[file.Key] || !isOrphanedFile(file.Key) {
+			continue
+		}
+		report.OrphanedFiles = append(report.OrphanedFiles, file.Key)
+
+		if !dryRun {
+			if err := s.filesRepo.DeleteFile(ctx, file.Key); err != nil {
+				logger.Errorf("unable to delete orphaned file %s: %s", file.Key, err.Error())
+				continue
+			}
+			report.DeletedFiles++
+			releasedFiles = append(releasedFiles, file.Key)
+		}
+		report.ReclaimedBytes += file.Size
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	countedFiles, err := s.storageRepo.GetUserFileKeys(threshold)
+	if err != nil {
+		return report, err
+	}
+	for _, objectKey := range countedFiles {
+		if !storedFiles[objectKey] {
+			releasedFiles = append(releasedFiles, objectKey)
+		}
+	}
+	if len(releasedFiles) > 0 {
+		if err = s.storageRepo.DeleteUserFiles(releasedFiles); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// isOrphanedFile checks unreferenced file by its location. Files of users and recipes must be referenced directly,
+// so pictures of existing recipes which aren't in gallery, preview or cooking are orphaned too.
+// Files outside known layout are never orphaned
+func isOrphanedFile(objectKey string) bool {
+	parts := strings.SplitN(objectKey, "/", 4)
+	if len(parts) < 4 {
+		return false
+	}
+	if _, err := strconv.Atoi(parts[1]); err != nil {
+		return false
+	}
+
+	switch parts[0] {
+	case storageUsersDir:
+		return parts[2] == storageAvatarsDir || parts[2] == storageKeysDir
+	case storageRecipesDir:
+		return parts[2] == storageImagesDir || parts[2] == storageKeysDir || parts[2] == storageUploadsDir
+	}
+
+	return false
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+	"github.com/mephistolie/chefbook-server/pkg/logger"
+	"time"
+)
+
+type SubscriptionService struct {
+	subscriptionsRepo repository.Subscription
+	providers         map[string]repository.SubscriptionProvider
+	productIds        map[string]bool
+	webhookToken      string
+}
+
+func NewSubscriptionService(subscriptionsRepo repository.Subscription, providers map[string]repository.SubscriptionProvider,
+	productIds []string, webhookToken string) *SubscriptionService {
+	products := make(map[string]bool)
+	for _, productId := range productIds {
+		products[productId] = true
+	}
+	return &SubscriptionService{
+		subscriptionsRepo: subscriptionsRepo,
+		providers:         providers,
+		productIds:        products,
+		webhookToken:      webhookToken,
+	}
+}
+
+func (s *SubscriptionService) GetSubscriptions(userId int) ([]entity.Subscription, error) {
+	return s.subscriptionsRepo.GetSubscriptions(userId)
+}
+
+// VerifyPurchase verifies purchase receipt with store and links subscription to user
+func (s *SubscriptionService) VerifyPurchase(ctx context.Context, receipt entity.PurchaseReceipt, userId int) (entity.Subscription, error) {
+	provider, ok := s.providers[receipt.Provider]
+	if !ok {
+		return entity.Subscription{}, failure.UnsupportedSubscriptionProvider
+	}
+	if len(s.productIds) > 0 && !s.productIds[receipt.ProductId] {
+		return entity.Subscription{}, failure.InvalidReceipt
+	}
+
+	state, err := provider.VerifyReceipt(ctx, receipt.ProductId, receipt.Receipt)
+	if err != nil {
+		return entity.Subscription{}, err
+	}
+
+	return s.setSubscription(userId, receipt.Provider, state)
+}
+
+// HandleNotification refreshes subscription changed by store: renewed, expired, refunded and so on.
+// Notifications of unknown subscriptions are skipped, they're linked to user on purchase verification
+func (s *SubscriptionService) HandleNotification(ctx context.Context, providerName, token string, body []byte) error {
+	if s.webhookToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.webhookToken)) != 1 {
+		return failure.AccessDenied
+	}
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return failure.UnsupportedSubscriptionProvider
+	}
+
+	notification, err := provider.ParseNotification(body)
+	if err != nil {
+		return err
+	}
+
+	var state entity.SubscriptionState
+	var subscription entity.Subscription
+	switch {
+	case notification.Receipt != nil:
+		productId := ""
+		if notification.ProductId != nil {
+			productId = *notification.ProductId
+		}
+		if state, err = provider.VerifyReceipt(ctx, productId, *notification.Receipt); err != nil {
+			return err
+		}
+		subscription, err = s.subscriptionsRepo.GetSubscription(providerName, state.OriginalTransactionId)
+	case notification.OriginalTransactionId != nil:
+		if subscription, err = s.subscriptionsRepo.GetSubscription(providerName, *notification.OriginalTransactionId); err != nil {
+			break
+		}
+		state, err = provider.VerifyReceipt(ctx, subscription.ProductId, subscription.Receipt)
+	default:
+		return nil
+	}
+	if err == failure.SubscriptionNotFound {
+		logger.Warnf("skipped %s notification of unknown subscription", providerName)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = s.setSubscription(subscription.UserId, providerName, state)
+	return err
+}
+
+// RefreshExpiredSubscriptions verifies subscriptions which expired without store notification.
+// Renewed subscriptions extend premium, others are marked as expired
+func (s *SubscriptionService) RefreshExpiredSubscriptions(ctx context.Context) (int, error) {
+	subscriptions, err := s.subscriptionsRepo.GetExpiredSubscriptions(time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	refreshed := 0
+	for _, subscription := range subscriptions {
+		provider, ok := s.providers[subscription.Provider]
+		if !ok {
+			logger.Warnf("unable to refresh subscription %d: provider %s is disabled", subscription.Id, subscription.Provider)
+			continue
+		}
+		state, err := provider.VerifyReceipt(ctx, subscription.ProductId, subscription.Receipt)
+		if err == failure.InvalidReceipt {
+			state = entity.SubscriptionState{
+				ProductId:             subscription.ProductId,
+				OriginalTransactionId: subscription.OriginalTransactionId,
+				Receipt:               subscription.Receipt,
+				Status:                entity.SubscriptionStatusExpired,
+				ExpiresAt:             subscription.ExpiresAt,
+			}
+		} else if err != nil {
+			logger.Errorf("unable to refresh subscription %d: %s", subscription.Id, err.Error())
+			continue
+		}
+		if _, err = s.setSubscription(subscription.UserId, subscription.Provider, state); err != nil {
+			logger.Errorf("unable to refresh subscription %d: %s", subscription.Id, err.Error())
+			continue
+		}
+		refreshed++
+	}
+
+	return refreshed, nil
+}
+
+func (s *SubscriptionService) setSubscription(userId int, provider string, state entity.SubscriptionState) (entity.Subscription, error) {
+	return s.subscriptionsRepo.SetSubscription(entity.Subscription{
+		UserId:                userId,
+		Provider:              provider,
+		ProductId:             state.ProductId,
+		OriginalTransactionId: state.OriginalTransactionId,
+		Receipt:               state.Receipt,
+		Status:                state.Status,
+		ExpiresAt:             state.ExpiresAt,
+		AutoRenew:             state.AutoRenew,
+	})
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/entity/failure"
+	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+)
+
+type TagService struct {
+	tagsRepo    repository.Tag
+	recipesRepo repository.Recipe
+}
+
+func NewTagService(tagsRepo repository.Tag, recipesRepo repository.Recipe) *TagService {
+	return &TagService{
+		tagsRepo:    tagsRepo,
+		recipesRepo: recipesRepo,
+	}
+}
+
+func (s *TagService) GetTags() ([]entity.Tag, error) {
+	return s.tagsRepo.GetTags()
+}
+
+func (s *TagService) SetRecipeTags(recipeId int, tags []string, userId int) error {
+	ownerId, err := s.recipesRepo.GetRecipeOwnerId(recipeId)
+	if err != nil {
+		return err
+	}
+	if ownerId != userId {
+		return failure.NotOwner
+	}
+
+	return s.tagsRepo.SetRecipeTags(recipeId, tags)
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"github.com/mephistolie/chefbook-server/internal/entity"
+	"github.com/mephistolie/chefbook-server/internal/service/interface/repository"
+)
+
+type TrendingService struct {
+	repo   repository.Trending
+	params entity.TrendingParams
+}
+
+func NewTrendingService(repo repository.Trending, params entity.TrendingParams) *TrendingService {
+	return &TrendingService{
+		repo:   repo,
+		params: params,
+	}
+}
+
+func (s *TrendingService) UpdateTrendingScores() error {
+	return s.repo.UpdateTrendingScores(s.params)
+}
@@ -0,0 +1,130 @@
+package allergen
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const prefixWildcard = "*"
+
+//go:embed dictionary.json
+var dictionaryJson []byte
+
+type phrase []string
+
+type entry struct {
+	keywords   []phrase
+	exceptions []phrase
+}
+
+var dictionary = mustLoadDictionary(dictionaryJson)
+
+// Detect returns sorted list of allergens mentioned in texts.
+// Keywords are matched against whole words; keyword words ending with '*' match any word with such prefix.
+// Exceptions hide words from keywords of the same allergen, e.g. 'eggplant' for eggs
+func Detect(texts []string) []string {
+	var words []string
+	for _, text := range texts {
+		words = append(words, tokenize(text)...)
+	}
+
+	allergens := []string{}
+	for allergen, entry := range dictionary {
+		visibleWords := make([]string, len(words))
+		copy(visibleWords, words)
+		for _, exception := range entry.exceptions {
+			for i := range visibleWords {
+				if exception.matches(visibleWords, i) {
+					for j := range exception {
+						visibleWords[i+j] = ""
+					}
+				}
+			}
+		}
+		for _, keyword := range entry.keywords {
+			if keyword.matchesAny(visibleWords) {
+				allergens = append(allergens, allergen)
+				break
+			}
+		}
+	}
+	sort.Strings(allergens)
+
+	return allergens
+}
+
+func IsSupported(allergen string) bool {
+	_, ok := dictionary[allergen]
+	return ok
+}
+
+func Supported() []string {
+	allergens := make([]string, 0, len(dictionary))
+	for allergen := range dictionary {
+		allergens = append(allergens, allergen)
+	}
+	sort.Strings(allergens)
+	return allergens
+}
+
+func (p phrase) matchesAny(words []string) bool {
+	for i := range words {
+		if p.matches(words, i) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p phrase) matches(words []string, position int) bool {
+	if position+len(p) > len(words) {
+		return false
+	}
+	for i, pattern := range p {
+		word := words[position+i]
+		if word == "" {
+			return false
+		}
+		if strings.HasSuffix(pattern, prefixWildcard) {
+			if !strings.HasPrefix(word, strings.TrimSuffix(pattern, prefixWildcard)) {
+				return false
+			}
+		} else if word != pattern {
+			return false
+		}
+	}
+	return true
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && string(r) != prefixWildcard
+	})
+}
+
+func mustLoadDictionary(data []byte) map[string]entry {
+	var raw map[string]struct {
+		Keywords   []string `json:"keywords"`
+		Exceptions []string `json:"exceptions"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		panic(err)
+	}
+
+	loaded := make(map[string]entry, len(raw))
+	for allergen, rawEntry := range raw {
+		var loadedEntry entry
+		for _, keyword := range rawEntry.Keywords {
+			loadedEntry.keywords = append(loadedEntry.keywords, tokenize(keyword))
+		}
+		for _, exception := range rawEntry.Exceptions {
+			loadedEntry.exceptions = append(loadedEntry.exceptions, tokenize(exception))
+		}
+		loaded[allergen] = loadedEntry
+	}
+
+	return loaded
+}
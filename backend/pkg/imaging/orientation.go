@@ -0,0 +1,126 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+const (
+	orientationNormal = 1
+	orientationTag    = 0x0112
+
+	markerPrefix      = 0xFF
+	markerSOI         = 0xD8
+	markerSOS         = 0xDA
+	markerAPP1        = 0xE1
+	exifHeader        = "Exif\x00\x00"
+	ifdEntryLength    = 12
+	tiffHeaderSize    = 8
+	byteOrderIntel    = "II"
+	byteOrderMotorola = "MM"
+)
+
+// readOrientation returns orientation from JPEG EXIF or normal orientation if it's missing
+func readOrientation(content []byte) int {
+	if len(content) < 2 || content[0] != markerPrefix || content[1] != markerSOI {
+		return orientationNormal
+	}
+
+	offset := 2
+	for offset+4 <= len(content) {
+		if content[offset] != markerPrefix {
+			return orientationNormal
+		}
+		marker := content[offset+1]
+		length := int(binary.BigEndian.Uint16(content[offset+2:]))
+		if marker == markerSOS || length < 2 || offset+2+length > len(content) {
+			return orientationNormal
+		}
+
+		segment := content[offset+4 : offset+2+length]
+		if marker == markerAPP1 && bytes.HasPrefix(segment, []byte(exifHeader)) {
+			return readTiffOrientation(segment[len(exifHeader):])
+		}
+		offset += 2 + length
+	}
+
+	return orientationNormal
+}
+
+func readTiffOrientation(tiff []byte) int {
+	if len(tiff) < tiffHeaderSize {
+		return orientationNormal
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case byteOrderIntel:
+		order = binary.LittleEndian
+	case byteOrderMotorola:
+		order = binary.BigEndian
+	default:
+		return orientationNormal
+	}
+
+	ifdOffset := int(order.Uint32(tiff[4:]))
+	if ifdOffset < tiffHeaderSize || ifdOffset+2 > len(tiff) {
+		return orientationNormal
+	}
+
+	entriesCount := int(order.Uint16(tiff[ifdOffset:]))
+	for i := 0; i < entriesCount; i++ {
+		entry := ifdOffset + 2 + i*ifdEntryLength
+		if entry+ifdEntryLength > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) == orientationTag {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return orientationNormal
+			}
+			return orientation
+		}
+	}
+
+	return orientationNormal
+}
+
+// orient rotates and flips image so it's displayed as intended by camera
+func orient(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation == orientationNormal {
+		return img
+	}
+
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			var srcX, srcY int
+			switch orientation {
+			case 2:
+				srcX, srcY = width-1-x, y
+			case 3:
+				srcX, srcY = width-1-x, height-1-y
+			case 4:
+				srcX, srcY = x, height-1-y
+			case 5:
+				srcX, srcY = y, x
+			case 6:
+				srcX, srcY = y, height-1-x
+			case 7:
+				srcX, srcY = width-1-y, height-1-x
+			case 8:
+				srcX, srcY = width-1-y, x
+			}
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], img.Pix[img.PixOffset(srcX, srcY):img.PixOffset(srcX, srcY)+4])
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,156 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+)
+
+// ContentType and Extension of processed pictures. Pictures are deliberately re-encoded to JPEG only:
+// there is no pure Go WebP encoder, and JPEG is supported by every client
+const ContentType = "image/jpeg"
+const Extension = ".jpg"
+
+// ErrTooManyPixels is returned for images which would take too much memory after decoding
+var ErrTooManyPixels = errors.New("image has too many pixels")
+
+type Processor interface {
+	Process(content []byte) (Image, error)
+	ThumbnailSizes() []int
+}
+
+// Image is re-encoded picture without metadata and its thumbnails by longest side size
+type Image struct {
+	Content    []byte
+	Thumbnails map[int][]byte
+}
+
+type JpegProcessor struct {
+	maxSide        int
+	maxPixels      int64
+	quality        int
+	thumbnailSizes []int
+}
+
+func NewJpegProcessor(maxSide int, maxPixels int64, quality int, thumbnailSizes []int) *JpegProcessor {
+	return &JpegProcessor{
+		maxSide:        maxSide,
+		maxPixels:      maxPixels,
+		quality:        quality,
+		thumbnailSizes: thumbnailSizes,
+	}
+}
+
+func (p *JpegProcessor) ThumbnailSizes() []int {
+	return p.thumbnailSizes
+}
+
+// Process decodes image, applies EXIF orientation and re-encodes it to JPEG.
+// Re-encoding drops all metadata including GPS position. Small file may declare huge dimensions,
+// so image size is checked by header before decoding
+func (p *JpegProcessor) Process(content []byte) (Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return Image{}, err
+	}
+	if p.maxPixels > 0 && int64(config.Width)*int64(config.Height) > p.maxPixels {
+		return Image{}, ErrTooManyPixels
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return Image{}, err
+	}
+
+	img := toRGBA(decoded)
+	img = orient(img, readOrientation(content))
+	img = fit(img, p.maxSide)
+
+	processed, err := p.encode(img)
+	if err != nil {
+		return Image{}, err
+	}
+
+	thumbnails := make(map[int][]byte)
+	for _, size := range p.thumbnailSizes {
+		thumbnail, err := p.encode(fit(img, size))
+		if err != nil {
+			return Image{}, err
+		}
+		thumbnails[size] = thumbnail
+	}
+
+	return Image{
+		Content:    processed,
+		Thumbnails: thumbnails,
+	}, nil
+}
+
+func (p *JpegProcessor) encode(img image.Image) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, img, &jpeg.Options{Quality: p.quality}); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+// fit downscales image with box filter so its longest side doesn't exceed maxSide
+func fit(img *image.RGBA, maxSide int) *image.RGBA {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if maxSide <= 0 || (width <= maxSide && height <= maxSide) {
+		return img
+	}
+
+	dstWidth, dstHeight := maxSide, maxSide
+	if width > height {
+		dstHeight = max(1, height*maxSide/width)
+	} else {
+		dstWidth = max(1, width*maxSide/height)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		srcY0, srcY1 := y*height/dstHeight, max((y+1)*height/dstHeight, y*height/dstHeight+1)
+		for x := 0; x < dstWidth; x++ {
+			srcX0, srcX1 := x*width/dstWidth, max((x+1)*width/dstWidth, x*width/dstWidth+1)
+
+			var sum [4]int
+			for srcY := srcY0; srcY < srcY1; srcY++ {
+				offset := img.PixOffset(srcX0, srcY)
+				for srcX := srcX0; srcX < srcX1; srcX++ {
+					for i := range sum {
+						sum[i] += int(img.Pix[offset+i])
+					}
+					offset += 4
+				}
+			}
+
+			count := (srcY1 - srcY0) * (srcX1 - srcX0)
+			dstOffset := dst.PixOffset(x, y)
+			for i := range sum {
+				dst.Pix[dstOffset+i] = uint8(sum[i] / count)
+			}
+		}
+	}
+
+	return dst
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
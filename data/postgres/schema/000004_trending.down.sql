@@ -0,0 +1,15 @@
+DROP INDEX recipes_trending_score_idx;
+
+ALTER TABLE recipes
+    DROP COLUMN trending_score;
+
+DROP TABLE recipe_views;
+
+DROP INDEX users_recipes_creation_timestamp_idx;
+DROP INDEX likes_creation_timestamp_idx;
+
+ALTER TABLE users_recipes
+    DROP COLUMN creation_timestamp;
+
+ALTER TABLE likes
+    DROP COLUMN creation_timestamp;
@@ -0,0 +1,38 @@
+-- time of existing likes and saves is unknown, so they are backfilled as infinitely old
+-- and don't get into trending window as fresh activity
+ALTER TABLE likes
+    ADD COLUMN creation_timestamp TIMESTAMP WITH TIME ZONE;
+
+UPDATE likes
+SET creation_timestamp='-infinity';
+
+ALTER TABLE likes
+    ALTER COLUMN creation_timestamp SET DEFAULT timezone('utc', now()),
+    ALTER COLUMN creation_timestamp SET NOT NULL;
+
+ALTER TABLE users_recipes
+    ADD COLUMN creation_timestamp TIMESTAMP WITH TIME ZONE;
+
+UPDATE users_recipes
+SET creation_timestamp='-infinity';
+
+ALTER TABLE users_recipes
+    ALTER COLUMN creation_timestamp SET DEFAULT timezone('utc', now()),
+    ALTER COLUMN creation_timestamp SET NOT NULL;
+
+CREATE TABLE recipe_views
+(
+    recipe_id          INT REFERENCES recipes (recipe_id) ON DELETE CASCADE NOT NULL,
+    user_id            INT REFERENCES users (user_id) ON DELETE CASCADE     NOT NULL,
+    creation_timestamp TIMESTAMP WITH TIME ZONE                             NOT NULL DEFAULT timezone('utc', now())
+);
+
+CREATE INDEX recipe_views_recipe_id_user_id_idx ON recipe_views (recipe_id, user_id, creation_timestamp);
+CREATE INDEX recipe_views_creation_timestamp_idx ON recipe_views (creation_timestamp);
+CREATE INDEX likes_creation_timestamp_idx ON likes (creation_timestamp);
+CREATE INDEX users_recipes_creation_timestamp_idx ON users_recipes (creation_timestamp);
+
+ALTER TABLE recipes
+    ADD COLUMN trending_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+CREATE INDEX recipes_trending_score_idx ON recipes (trending_score DESC) WHERE visibility = 'public' AND encrypted = false;
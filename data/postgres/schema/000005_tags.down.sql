@@ -0,0 +1,3 @@
+DROP TABLE recipes_tags;
+
+DROP TABLE tags;
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    DROP COLUMN excluded_allergens;
+
+ALTER TABLE recipes
+    DROP COLUMN allergens;
@@ -0,0 +1,5 @@
+ALTER TABLE recipes
+    ADD COLUMN allergens VARCHAR(16)[];
+
+ALTER TABLE users
+    ADD COLUMN excluded_allergens VARCHAR(16)[] NOT NULL DEFAULT '{}';
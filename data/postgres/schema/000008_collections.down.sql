@@ -0,0 +1,3 @@
+DROP TABLE users_collections;
+DROP TABLE collections_recipes;
+DROP TABLE collections;
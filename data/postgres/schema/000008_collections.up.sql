@@ -0,0 +1,35 @@
+CREATE TABLE collections
+(
+    collection_id      SERIAL PRIMARY KEY                               NOT NULL UNIQUE,
+    name               VARCHAR(100)                                     NOT NULL,
+    description        VARCHAR(1500),
+    owner_id           INT REFERENCES users (user_id) ON DELETE CASCADE NOT NULL,
+    visibility         visibility_type                                  NOT NULL DEFAULT 'public',
+    creation_timestamp TIMESTAMP WITH TIME ZONE                         NOT NULL DEFAULT timezone('utc', now()),
+    update_timestamp   TIMESTAMP WITH TIME ZONE                         NOT NULL DEFAULT timezone('utc', now()),
+    CHECK (visibility <> 'private')
+);
+
+CREATE INDEX collections_owner_id_idx ON collections (owner_id);
+
+CREATE TABLE collections_recipes
+(
+    collection_id INT REFERENCES collections (collection_id) ON DELETE CASCADE NOT NULL,
+    recipe_id     INT REFERENCES recipes (recipe_id) ON DELETE CASCADE         NOT NULL,
+    position      INT                                                          NOT NULL,
+    PRIMARY KEY (collection_id, recipe_id)
+);
+
+CREATE INDEX collections_recipes_recipe_id_idx ON collections_recipes (recipe_id);
+
+CREATE TABLE users_collections
+(
+    collection_id      INT REFERENCES collections (collection_id) ON DELETE CASCADE NOT NULL,
+    user_id            INT REFERENCES users (user_id) ON DELETE CASCADE             NOT NULL,
+    followed           BOOLEAN                                                      NOT NULL DEFAULT false,
+    saved              BOOLEAN                                                      NOT NULL DEFAULT false,
+    creation_timestamp TIMESTAMP WITH TIME ZONE                                     NOT NULL DEFAULT timezone('utc', now()),
+    PRIMARY KEY (collection_id, user_id)
+);
+
+CREATE INDEX users_collections_user_id_idx ON users_collections (user_id);
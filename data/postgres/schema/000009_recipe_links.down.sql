@@ -0,0 +1,4 @@
+ALTER TABLE users_recipes
+    DROP COLUMN link_id;
+
+DROP TABLE recipe_links;
@@ -0,0 +1,13 @@
+CREATE TABLE recipe_links
+(
+    link_id            SERIAL PRIMARY KEY                                   NOT NULL UNIQUE,
+    token              VARCHAR(64)                                          NOT NULL UNIQUE,
+    recipe_id          INT REFERENCES recipes (recipe_id) ON DELETE CASCADE NOT NULL,
+    expires_at         TIMESTAMP WITH TIME ZONE,
+    creation_timestamp TIMESTAMP WITH TIME ZONE                             NOT NULL DEFAULT timezone('utc', now())
+);
+
+CREATE INDEX recipe_links_recipe_id_idx ON recipe_links (recipe_id);
+
+ALTER TABLE users_recipes
+    ADD COLUMN link_id INT REFERENCES recipe_links (link_id) ON DELETE SET NULL;
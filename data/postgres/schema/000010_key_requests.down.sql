@@ -0,0 +1,9 @@
+DROP INDEX encrypted_recipes_requests_user_id_idx;
+DROP INDEX encrypted_recipes_requests_pending_idx;
+
+ALTER TABLE encrypted_recipes_requests
+    DROP CONSTRAINT encrypted_recipes_requests_status_check,
+    DROP COLUMN update_timestamp,
+    DROP COLUMN creation_timestamp,
+    DROP COLUMN status,
+    DROP COLUMN request_id;
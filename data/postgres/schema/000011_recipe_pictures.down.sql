@@ -0,0 +1,4 @@
+DROP INDEX recipe_pictures_preview_idx;
+DROP INDEX recipe_pictures_recipe_id_idx;
+
+DROP TABLE recipe_pictures;
@@ -0,0 +1,3 @@
+DROP INDEX storage_files_user_id_idx;
+
+DROP TABLE storage_files;
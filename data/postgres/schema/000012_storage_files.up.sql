@@ -0,0 +1,10 @@
+CREATE TABLE storage_files
+(
+    object_key         VARCHAR(255) PRIMARY KEY                             NOT NULL UNIQUE,
+    user_id            INT REFERENCES users (user_id) ON DELETE CASCADE     NOT NULL,
+    recipe_id          INT REFERENCES recipes (recipe_id) ON DELETE CASCADE,
+    size               BIGINT                                               NOT NULL DEFAULT 0,
+    creation_timestamp TIMESTAMP WITH TIME ZONE                             NOT NULL DEFAULT timezone('utc', now())
+);
+
+CREATE INDEX storage_files_user_id_idx ON storage_files (user_id);
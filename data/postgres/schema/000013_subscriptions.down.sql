@@ -0,0 +1,4 @@
+DROP INDEX subscriptions_status_expires_at_idx;
+DROP INDEX subscriptions_user_id_idx;
+
+DROP TABLE subscriptions;
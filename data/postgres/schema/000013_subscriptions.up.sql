@@ -0,0 +1,18 @@
+CREATE TABLE subscriptions
+(
+    subscription_id         SERIAL PRIMARY KEY                               NOT NULL UNIQUE,
+    user_id                 INT REFERENCES users (user_id) ON DELETE CASCADE NOT NULL,
+    provider                VARCHAR(32)                                      NOT NULL,
+    product_id              VARCHAR(255)                                     NOT NULL,
+    original_transaction_id VARCHAR(512)                                     NOT NULL,
+    receipt                 TEXT                                             NOT NULL,
+    status                  VARCHAR(32)                                      NOT NULL,
+    expires_at              TIMESTAMP WITH TIME ZONE                         NOT NULL,
+    auto_renew              BOOLEAN                                          NOT NULL DEFAULT FALSE,
+    creation_timestamp      TIMESTAMP WITH TIME ZONE                         NOT NULL DEFAULT timezone('utc', now()),
+    update_timestamp        TIMESTAMP WITH TIME ZONE                         NOT NULL DEFAULT timezone('utc', now()),
+    UNIQUE (provider, original_transaction_id)
+);
+
+CREATE INDEX subscriptions_user_id_idx ON subscriptions (user_id);
+CREATE INDEX subscriptions_status_expires_at_idx ON subscriptions (status, expires_at);
@@ -0,0 +1,6 @@
+ALTER TABLE users
+    DROP CONSTRAINT users_broccoins_non_negative;
+
+DROP INDEX broccoin_transactions_user_id_idx;
+
+DROP TABLE broccoin_transactions;
@@ -0,0 +1,6 @@
+DROP INDEX referrals_referrer_id_idx;
+
+DROP TABLE referrals;
+
+ALTER TABLE users
+    DROP COLUMN referral_code;
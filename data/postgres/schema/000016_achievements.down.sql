@@ -0,0 +1,5 @@
+DROP TABLE users_achievements;
+
+DROP INDEX recipe_cookings_user_id_idx;
+
+DROP TABLE recipe_cookings;
@@ -0,0 +1,18 @@
+CREATE TABLE recipe_cookings
+(
+    user_id            INT REFERENCES users (user_id) ON DELETE CASCADE     NOT NULL,
+    recipe_id          INT REFERENCES recipes (recipe_id) ON DELETE CASCADE NOT NULL,
+    cooking_date       DATE                                                 NOT NULL DEFAULT timezone('utc', now())::date,
+    creation_timestamp TIMESTAMP WITH TIME ZONE                             NOT NULL DEFAULT timezone('utc', now()),
+    UNIQUE (user_id, recipe_id, cooking_date)
+);
+
+CREATE INDEX recipe_cookings_user_id_idx ON recipe_cookings (user_id, cooking_date);
+
+CREATE TABLE users_achievements
+(
+    user_id          INT REFERENCES users (user_id) ON DELETE CASCADE NOT NULL,
+    achievement      VARCHAR(32)                                      NOT NULL,
+    unlock_timestamp TIMESTAMP WITH TIME ZONE                         NOT NULL DEFAULT timezone('utc', now()),
+    PRIMARY KEY (user_id, achievement)
+);
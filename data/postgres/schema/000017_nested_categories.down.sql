@@ -0,0 +1,5 @@
+DROP INDEX categories_user_id_idx;
+
+ALTER TABLE categories
+    DROP COLUMN position,
+    DROP COLUMN parent_id;
@@ -0,0 +1,14 @@
+ALTER TABLE categories
+    ADD COLUMN parent_id INT REFERENCES categories (category_id) ON DELETE SET NULL CHECK (parent_id <> category_id),
+    ADD COLUMN position  INT NOT NULL DEFAULT 0;
+
+UPDATE categories
+SET position=ordered.position
+FROM
+(
+    SELECT category_id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY category_id) AS position
+    FROM categories
+) AS ordered
+WHERE categories.category_id=ordered.category_id;
+
+CREATE INDEX categories_user_id_idx ON categories (user_id, parent_id, position);
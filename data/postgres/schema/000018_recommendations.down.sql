@@ -0,0 +1,5 @@
+DROP INDEX recipe_ingredient_tokens_token_idx;
+
+DROP TABLE recipe_ingredient_tokens;
+
+DROP TABLE recipe_similarities;
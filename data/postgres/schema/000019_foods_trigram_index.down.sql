@@ -0,0 +1 @@
+DROP INDEX foods_name_trgm_idx;
@@ -0,0 +1 @@
+CREATE INDEX foods_name_trgm_idx ON foods USING gist (lower(name) gist_trgm_ops);
@@ -0,0 +1,10 @@
+CREATE TABLE follows
+(
+    follower_id        INT REFERENCES users (user_id) ON DELETE CASCADE NOT NULL,
+    author_id          INT REFERENCES users (user_id) ON DELETE CASCADE NOT NULL,
+    creation_timestamp TIMESTAMP WITH TIME ZONE                         NOT NULL DEFAULT timezone('utc', now()),
+    PRIMARY KEY (follower_id, author_id),
+    CHECK (follower_id <> author_id)
+);
+
+CREATE INDEX follows_author_id_idx ON follows (author_id);
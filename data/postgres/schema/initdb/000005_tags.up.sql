@@ -0,0 +1,58 @@
+CREATE TABLE tags
+(
+    tag_id VARCHAR(32) PRIMARY KEY,
+    type   VARCHAR(16) NOT NULL
+);
+
+CREATE TABLE recipes_tags
+(
+    recipe_id INT REFERENCES recipes (recipe_id) ON DELETE CASCADE NOT NULL,
+    tag_id    VARCHAR(32) REFERENCES tags (tag_id) ON DELETE CASCADE NOT NULL,
+    PRIMARY KEY (recipe_id, tag_id)
+);
+
+CREATE INDEX recipes_tags_tag_id_idx ON recipes_tags (tag_id);
+
+INSERT INTO tags (tag_id, type)
+VALUES ('american', 'cuisine'),
+       ('chinese', 'cuisine'),
+       ('french', 'cuisine'),
+       ('georgian', 'cuisine'),
+       ('greek', 'cuisine'),
+       ('indian', 'cuisine'),
+       ('italian', 'cuisine'),
+       ('japanese', 'cuisine'),
+       ('korean', 'cuisine'),
+       ('mexican', 'cuisine'),
+       ('middle_eastern', 'cuisine'),
+       ('russian', 'cuisine'),
+       ('spanish', 'cuisine'),
+       ('thai', 'cuisine'),
+       ('breakfast', 'course'),
+       ('appetizer', 'course'),
+       ('soup', 'course'),
+       ('salad', 'course'),
+       ('main_course', 'course'),
+       ('side_dish', 'course'),
+       ('dessert', 'course'),
+       ('snack', 'course'),
+       ('drink', 'course'),
+       ('sauce', 'course'),
+       ('baking', 'course'),
+       ('vegetarian', 'diet'),
+       ('vegan', 'diet'),
+       ('gluten_free', 'diet'),
+       ('dairy_free', 'diet'),
+       ('low_carb', 'diet'),
+       ('keto', 'diet'),
+       ('paleo', 'diet'),
+       ('halal', 'diet'),
+       ('kosher', 'diet'),
+       ('birthday', 'occasion'),
+       ('christmas', 'occasion'),
+       ('new_year', 'occasion'),
+       ('easter', 'occasion'),
+       ('halloween', 'occasion'),
+       ('picnic', 'occasion'),
+       ('party', 'occasion'),
+       ('weeknight', 'occasion');
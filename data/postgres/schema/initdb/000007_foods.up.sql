@@ -0,0 +1,17 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE foods
+(
+    food_id       SERIAL PRIMARY KEY,
+    source        VARCHAR(16)      NOT NULL,
+    external_id   VARCHAR(64)      NOT NULL,
+    name          VARCHAR(256)     NOT NULL,
+    language      VARCHAR(2)       NOT NULL DEFAULT 'en',
+    calories      DOUBLE PRECISION NOT NULL DEFAULT 0,
+    protein       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    fats          DOUBLE PRECISION NOT NULL DEFAULT 0,
+    carbohydrates DOUBLE PRECISION NOT NULL DEFAULT 0,
+    UNIQUE (source, external_id)
+);
+
+CREATE INDEX foods_language_idx ON foods (language);
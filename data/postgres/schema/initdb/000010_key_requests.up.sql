@@ -0,0 +1,15 @@
+ALTER TABLE encrypted_recipes_requests
+    ADD COLUMN request_id         SERIAL PRIMARY KEY,
+    ADD COLUMN status             VARCHAR(16)              NOT NULL DEFAULT 'pending',
+    ADD COLUMN creation_timestamp TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT timezone('utc', now()),
+    ADD COLUMN update_timestamp   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT timezone('utc', now()),
+    ADD CONSTRAINT encrypted_recipes_requests_status_check
+        CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled'));
+
+UPDATE encrypted_recipes_requests
+SET status='approved'
+WHERE encrypted_recipe_key IS NOT NULL;
+
+CREATE UNIQUE INDEX encrypted_recipes_requests_pending_idx ON encrypted_recipes_requests (recipe_id, user_id)
+    WHERE status = 'pending';
+CREATE INDEX encrypted_recipes_requests_user_id_idx ON encrypted_recipes_requests (user_id);
@@ -0,0 +1,20 @@
+CREATE TABLE recipe_pictures
+(
+    picture_id         SERIAL PRIMARY KEY                                   NOT NULL UNIQUE,
+    recipe_id          INT REFERENCES recipes (recipe_id) ON DELETE CASCADE NOT NULL,
+    object_key         VARCHAR(255)                                         NOT NULL,
+    caption            VARCHAR(500),
+    position           INT                                                  NOT NULL DEFAULT 0,
+    is_preview         BOOLEAN                                              NOT NULL DEFAULT FALSE,
+    creation_timestamp TIMESTAMP WITH TIME ZONE                             NOT NULL DEFAULT timezone('utc', now()),
+    UNIQUE (recipe_id, object_key)
+);
+
+CREATE INDEX recipe_pictures_recipe_id_idx ON recipe_pictures (recipe_id, position);
+CREATE UNIQUE INDEX recipe_pictures_preview_idx ON recipe_pictures (recipe_id)
+    WHERE is_preview;
+
+INSERT INTO recipe_pictures (recipe_id, object_key, is_preview)
+SELECT recipe_id, preview, TRUE
+FROM recipes
+WHERE preview LIKE 'recipes/' || recipe_id || '/images/%';
@@ -0,0 +1,25 @@
+CREATE TABLE broccoin_transactions
+(
+    transaction_id     SERIAL PRIMARY KEY                               NOT NULL UNIQUE,
+    user_id            INT REFERENCES users (user_id) ON DELETE CASCADE NOT NULL,
+    amount             INT                                              NOT NULL CHECK (amount <> 0),
+    balance            INT                                              NOT NULL CHECK (balance >= 0),
+    reason             VARCHAR(32)                                      NOT NULL,
+    reference          VARCHAR(255),
+    creation_timestamp TIMESTAMP WITH TIME ZONE                         NOT NULL DEFAULT timezone('utc', now()),
+    UNIQUE (user_id, reason, reference)
+);
+
+CREATE INDEX broccoin_transactions_user_id_idx ON broccoin_transactions (user_id, transaction_id);
+
+INSERT INTO broccoin_transactions (user_id, amount, balance, reason)
+SELECT user_id, broccoins, broccoins, 'opening_balance'
+FROM users
+WHERE broccoins > 0;
+
+UPDATE users
+SET broccoins=0
+WHERE broccoins < 0;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_broccoins_non_negative CHECK (broccoins >= 0);
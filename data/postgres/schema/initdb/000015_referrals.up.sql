@@ -0,0 +1,14 @@
+ALTER TABLE users
+    ADD COLUMN referral_code VARCHAR(16) UNIQUE;
+
+CREATE TABLE referrals
+(
+    referrer_id          INT REFERENCES users (user_id) ON DELETE CASCADE NOT NULL,
+    invitee_id           INT REFERENCES users (user_id) ON DELETE CASCADE NOT NULL UNIQUE,
+    status               VARCHAR(16)                                      NOT NULL DEFAULT 'pending',
+    creation_timestamp   TIMESTAMP WITH TIME ZONE                         NOT NULL DEFAULT timezone('utc', now()),
+    activation_timestamp TIMESTAMP WITH TIME ZONE,
+    CHECK (referrer_id <> invitee_id)
+);
+
+CREATE INDEX referrals_referrer_id_idx ON referrals (referrer_id, status);
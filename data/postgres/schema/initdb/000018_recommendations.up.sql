@@ -0,0 +1,16 @@
+CREATE TABLE recipe_similarities
+(
+    recipe_id         INT REFERENCES recipes (recipe_id) ON DELETE CASCADE NOT NULL,
+    similar_recipe_id INT REFERENCES recipes (recipe_id) ON DELETE CASCADE NOT NULL,
+    score             DOUBLE PRECISION                                     NOT NULL,
+    PRIMARY KEY (recipe_id, similar_recipe_id)
+);
+
+CREATE TABLE recipe_ingredient_tokens
+(
+    recipe_id INT REFERENCES recipes (recipe_id) ON DELETE CASCADE NOT NULL,
+    token     TEXT                                                 NOT NULL,
+    PRIMARY KEY (recipe_id, token)
+);
+
+CREATE INDEX recipe_ingredient_tokens_token_idx ON recipe_ingredient_tokens (token);